@@ -3,12 +3,17 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/hooks"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/statusline"
 )
 
@@ -25,27 +30,42 @@ Hook Commands (called by hooks.json):
   session-end         Cleanup session state
 
 User Commands (called via bash in command files):
-  reset <session>   Reset baseline after review
-  pause <session>   Temporarily disable enforcement
-  resume <session>  Re-enable enforcement
-  view <session>    Set visualization mode
-  config            Show/set threshold configuration
+  reset <session>         Reset baseline after review [--soft: clear the trip, keep the baseline] [--confirm: skip require_reset_confirmation]
+  session-reset-all       Clear the trip on every session in this repo [--full: also move each baseline to the current tree] [--confirm]
+  undo <session>          Revert the most recent baseline reset
+  session-info <session>  Show baseline, score, and time since last reset
+  trend <session>         Show a sparkline of the session's recent scores
+  session-export <id>     Print the session state and resolved config as portable JSON
+  session-import          Recreate a session from session-export JSON on stdin [--id ID] [--force]
+  pause <session> [dur]   Temporarily disable enforcement, optionally auto-resuming after dur (e.g. 30m)
+  resume <session>        Re-enable enforcement
+  view <session>          Set visualization mode
+  diff <session>          Print the diff visualization at the session's view mode [--color=always|auto|never] [--no-untracked]
+                          [--threshold N: append OK / OVER by K and exit 1 when over; no session needed]
+  config                  Show/set threshold, unset <key> to restore a default
+  score-range <from> <to> Score the diff between two refs [--json]
+  check                   Exit 1 if staged score exceeds threshold [--working] [--quiet]
+  explain                 Score a hypothetical change [--new N] [--edit N] [--files N] [--deletions N]
+  doctor                  Check setup (git, checkpoint dir, status line, config)
+  metrics                 Print Prometheus metrics aggregated over all session files
+  size                    Show checkpoint dir disk usage (session vs lock files)
+  replay <log-file>       Rebuild a session's score/trip timeline from its debug log
 
 Status Line Widget:
   status [--widget=TYPE]  Output bumper-lanes status (reads JSON from stdin)
                           Types: all (default), indicator, diff-tree
+                          Without --widget, uses the session's /bumper-widget choice
                           Use --widget=indicator for just the threshold gauge
                           Use --widget=diff-tree for just the visualization
 `
 
 func main() {
+	// Point git at a pinned repo_root before anything shells out
+	config.ExportRepoRoot()
+
 	// No args: default to status command (for statusLine.command usage)
 	if len(os.Args) < 2 {
-		if err := cmdStatus(nil); err != nil {
-			fmt.Fprintf(os.Stderr, "error: %v\n", err)
-			os.Exit(1)
-		}
-		return
+		os.Exit(cmdStatus(nil))
 	}
 
 	cmd := os.Args[1]
@@ -67,6 +87,20 @@ func main() {
 		err = cmdSessionEnd()
 	case "reset":
 		err = cmdReset(args)
+	case "session-reset-all":
+		err = cmdSessionResetAll(args)
+	case "undo":
+		err = cmdUndo(args)
+	case "session-info":
+		err = cmdSessionInfo(args)
+	case "trend":
+		err = cmdTrend(args)
+	case "session-export":
+		err = cmdSessionExport(args)
+	case "session-import":
+		err = cmdSessionImport(args)
+	case "diff":
+		exitCode, err = cmdDiff(args)
 	case "pause":
 		err = cmdPause(args)
 	case "resume":
@@ -75,8 +109,22 @@ func main() {
 		err = cmdView(args)
 	case "config":
 		err = cmdConfig(args)
+	case "doctor":
+		err = hooks.Doctor()
+	case "metrics":
+		err = hooks.Metrics(os.Stdout)
+	case "size":
+		err = hooks.Size(os.Stdout)
+	case "replay":
+		err = cmdReplay(args)
+	case "score-range":
+		err = cmdScoreRange(args)
+	case "check":
+		exitCode = cmdCheck(args)
+	case "explain":
+		err = cmdExplain(args)
 	case "status":
-		err = cmdStatus(args)
+		exitCode = cmdStatus(args)
 	case "handle-prompt":
 		exitCode = cmdHandlePrompt()
 	case "-h", "--help", "help":
@@ -142,6 +190,64 @@ func cmdSessionEnd() error {
 // User command implementations
 
 func cmdReset(args []string) error {
+	sessionID := os.Getenv("CLAUDE_CODE_SESSION_ID")
+	soft, confirmed := false, false
+	for _, arg := range args {
+		if arg == "--soft" {
+			soft = true
+		} else if arg == "--confirm" {
+			confirmed = true
+		} else if !strings.HasPrefix(arg, "-") {
+			sessionID = arg
+		}
+	}
+	if sessionID == "" {
+		return fmt.Errorf("no session_id: set CLAUDE_CODE_SESSION_ID or pass as arg")
+	}
+	if soft {
+		return hooks.SoftReset(sessionID)
+	}
+	return hooks.Reset(sessionID, confirmed)
+}
+
+func cmdSessionResetAll(args []string) error {
+	full, confirmed := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--full":
+			full = true
+		case "--confirm":
+			confirmed = true
+		default:
+			return fmt.Errorf("usage: bumper-lanes session-reset-all [--full [--confirm]]")
+		}
+	}
+	return hooks.ResetAll(os.Stdout, full, confirmed)
+}
+
+func cmdUndo(args []string) error {
+	sessionID := os.Getenv("CLAUDE_CODE_SESSION_ID")
+	if len(args) >= 1 {
+		sessionID = args[0]
+	}
+	if sessionID == "" {
+		return fmt.Errorf("no session_id: set CLAUDE_CODE_SESSION_ID or pass as arg")
+	}
+	return hooks.Undo(sessionID)
+}
+
+func cmdSessionInfo(args []string) error {
+	sessionID := os.Getenv("CLAUDE_CODE_SESSION_ID")
+	if len(args) >= 1 {
+		sessionID = args[0]
+	}
+	if sessionID == "" {
+		return fmt.Errorf("no session_id: set CLAUDE_CODE_SESSION_ID or pass as arg")
+	}
+	return hooks.SessionInfo(sessionID)
+}
+
+func cmdTrend(args []string) error {
 	sessionID := os.Getenv("CLAUDE_CODE_SESSION_ID")
 	if len(args) >= 1 {
 		sessionID = args[0]
@@ -149,7 +255,103 @@ func cmdReset(args []string) error {
 	if sessionID == "" {
 		return fmt.Errorf("no session_id: set CLAUDE_CODE_SESSION_ID or pass as arg")
 	}
-	return hooks.Reset(sessionID)
+	return hooks.Trend(sessionID, os.Stdout)
+}
+
+func cmdSessionExport(args []string) error {
+	sessionID := os.Getenv("CLAUDE_CODE_SESSION_ID")
+	if len(args) >= 1 {
+		sessionID = args[0]
+	}
+	if sessionID == "" {
+		return fmt.Errorf("no session_id: set CLAUDE_CODE_SESSION_ID or pass as arg")
+	}
+	return hooks.SessionExport(sessionID, os.Stdout)
+}
+
+func cmdSessionImport(args []string) error {
+	fs := flag.NewFlagSet("session-import", flag.ContinueOnError)
+	sessionID := fs.String("id", "", "session ID to import as (default: the exported ID)")
+	force := fs.Bool("force", false, "replace an existing session with the same ID")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		return fmt.Errorf("usage: bumper-lanes session-import [--id ID] [--force] < export.json")
+	}
+	return hooks.SessionImport(os.Stdin, os.Stdout, *sessionID, *force)
+}
+
+func cmdDiff(args []string) (int, error) {
+	sessionID := os.Getenv("CLAUDE_CODE_SESSION_ID")
+	colorMode := colorAuto
+	noUntracked := false
+	threshold := 0
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--no-untracked":
+			noUntracked = true
+		case arg == "--threshold" || strings.HasPrefix(arg, "--threshold="):
+			value, ok := strings.CutPrefix(arg, "--threshold=")
+			if !ok && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("--threshold must be a positive number of points, got %q", value)
+			}
+			threshold = n
+		case arg == "--no-color": // Deprecated; same as --color=never
+			colorMode = colorNever
+		case arg == "--color":
+			colorMode = colorAlways
+		case strings.HasPrefix(arg, "--color="):
+			colorMode = strings.TrimPrefix(arg, "--color=")
+		case !strings.HasPrefix(arg, "-"):
+			sessionID = arg
+		}
+	}
+	// A threshold check is standalone: without a session, the config view mode is used
+	if sessionID == "" && threshold == 0 {
+		return 0, fmt.Errorf("no session_id: set CLAUDE_CODE_SESSION_ID or pass as arg")
+	}
+	useColor, err := resolveColor(colorMode, stdoutIsTerminal)
+	if err != nil {
+		return 0, err
+	}
+	if threshold > 0 {
+		return hooks.DiffCheck(os.Stdout, sessionID, useColor, noUntracked, threshold), nil
+	}
+	return 0, hooks.Diff(sessionID, useColor, noUntracked)
+}
+
+// --color values, matching git's.
+const (
+	colorAlways = "always"
+	colorAuto   = "auto"
+	colorNever  = "never"
+)
+
+// resolveColor decides whether to emit color for a --color value.
+// auto asks isTTY, so tests can stand in for the terminal check.
+func resolveColor(mode string, isTTY func() bool) (bool, error) {
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto:
+		return isTTY(), nil
+	}
+	return false, fmt.Errorf("invalid --color value %q (want always, auto, or never)", mode)
+}
+
+// stdoutIsTerminal reports whether stdout is a character device (TTY).
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
 
 func cmdPause(args []string) error {
@@ -160,7 +362,15 @@ func cmdPause(args []string) error {
 	if sessionID == "" {
 		return fmt.Errorf("no session_id: set CLAUDE_CODE_SESSION_ID or pass as arg")
 	}
-	return hooks.Pause(sessionID)
+	var duration time.Duration
+	if len(args) >= 2 {
+		d, err := time.ParseDuration(args[1])
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid pause duration %q (e.g. 30m, 2h)", args[1])
+		}
+		duration = d
+	}
+	return hooks.Pause(sessionID, duration)
 }
 
 func cmdResume(args []string) error {
@@ -210,7 +420,62 @@ func cmdConfig(args []string) error {
 	if args[0] == "set" && len(args) >= 2 {
 		return hooks.ConfigSet(args[1])
 	}
-	return fmt.Errorf("usage: bumper-lanes config [show|set <value>]")
+	if args[0] == "unset" && len(args) >= 2 {
+		return hooks.ConfigUnset(args[1])
+	}
+	return fmt.Errorf("usage: bumper-lanes config [show|set <value>|unset <key>]")
+}
+
+func cmdScoreRange(args []string) error {
+	var refs []string
+	jsonOutput := false
+	for _, arg := range args {
+		if arg == "--json" {
+			jsonOutput = true
+		} else {
+			refs = append(refs, arg)
+		}
+	}
+	if len(refs) != 2 {
+		return fmt.Errorf("usage: bumper-lanes score-range <from> <to> [--json]")
+	}
+	return hooks.ScoreRange(refs[0], refs[1], jsonOutput)
+}
+
+func cmdReplay(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: bumper-lanes replay <log-file>")
+	}
+	return hooks.Replay(args[0], os.Stdout)
+}
+
+func cmdCheck(args []string) int {
+	working, quiet := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--working":
+			working = true
+		case "--quiet", "-q":
+			quiet = true
+		default:
+			fmt.Fprintln(os.Stderr, "usage: bumper-lanes check [--working] [--quiet]")
+			return hooks.CheckError
+		}
+	}
+	return hooks.Check(os.Stdout, working, quiet)
+}
+
+func cmdExplain(args []string) error {
+	var in hooks.ExplainInput
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.IntVar(&in.NewLines, "new", 0, "lines added in new files")
+	fs.IntVar(&in.EditLines, "edit", 0, "lines added in existing files")
+	fs.IntVar(&in.Files, "files", 0, "files with additions (default: one per line kind)")
+	fs.IntVar(&in.Deletions, "deletions", 0, "deleted lines (scored only with deletion_weight)")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("usage: bumper-lanes explain [--new N] [--edit N] [--files N] [--deletions N]")
+	}
+	return hooks.Explain(in)
 }
 
 // Prompt handler (UserPromptSubmit hook)
@@ -225,9 +490,17 @@ func cmdHandlePrompt() int {
 
 // Status line widget command
 
-func cmdStatus(args []string) error {
-	// Parse --widget flag
-	widget := statusline.WidgetAll
+func cmdStatus(args []string) int {
+	return runStatus(os.Stdin, os.Stdout, args)
+}
+
+// runStatus renders the status line from stdin to stdout. It always returns
+// 0 and prints nothing on error: Claude Code can show the status command's
+// output in the status bar, so a broken status line stays blank instead of
+// showing error text. Errors go to the debug log.
+func runStatus(stdin io.Reader, stdout io.Writer, args []string) int {
+	// Parse --widget flag; empty defers to the session's /bumper-widget choice
+	var widget string
 	for i, arg := range args {
 		if strings.HasPrefix(arg, "--widget=") {
 			widget = strings.TrimPrefix(arg, "--widget=")
@@ -236,23 +509,30 @@ func cmdStatus(args []string) error {
 		}
 	}
 
-	// Read JSON from stdin
-	data, err := io.ReadAll(os.Stdin)
+	output, sessionID, err := renderStatus(stdin)
 	if err != nil {
-		return fmt.Errorf("reading stdin: %w", err)
+		logging.New(sessionID, "statusline").Debug("status line failed, printing nothing: %v", err)
+		return 0
 	}
 
-	input, err := statusline.ParseInput(data)
+	// Output the formatted widget
+	fmt.Fprint(stdout, statusline.FormatOutput(output, widget))
+	return 0
+}
+
+// renderStatus reads status line JSON from stdin and renders it. sessionID
+// is empty if the input couldn't be parsed.
+func renderStatus(stdin io.Reader) (output *statusline.StatusOutput, sessionID string, err error) {
+	data, err := io.ReadAll(stdin)
 	if err != nil {
-		return err
+		return nil, "", fmt.Errorf("reading stdin: %w", err)
 	}
 
-	output, err := statusline.Render(input)
+	input, err := statusline.ParseInput(data)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	// Output the formatted widget
-	fmt.Print(statusline.FormatOutput(output, widget))
-	return nil
+	output, err = statusline.Render(input)
+	return output, input.SessionID, err
 }
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveColor(t *testing.T) {
+	tests := []struct {
+		mode    string
+		tty     bool
+		want    bool
+		wantErr bool
+	}{
+		{colorAlways, false, true, false}, // Forced into a pipe
+		{colorAlways, true, true, false},
+		{colorAuto, true, true, false},
+		{colorAuto, false, false, false},
+		{colorNever, true, false, false},
+		{"sometimes", true, false, true},
+	}
+
+	for _, tt := range tests {
+		isTTY := func() bool { return tt.tty }
+		got, err := resolveColor(tt.mode, isTTY)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveColor(%q, tty=%v) error = %v, wantErr %v", tt.mode, tt.tty, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveColor(%q, tty=%v) = %v, want %v", tt.mode, tt.tty, got, tt.want)
+		}
+	}
+}
+
+func TestRunStatusInvalidInputIsSilent(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+	}{
+		{"invalid json", `{"session_id": `},
+		{"empty stdin", ""},
+		{"wrong shape", `["not", "an", "object"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logDir := t.TempDir()
+			t.Setenv("BUMPER_LANES_LOG_DIR", logDir)
+			t.Setenv("BUMPER_LANES_DEBUG", "1")
+
+			var stdout bytes.Buffer
+			if code := runStatus(strings.NewReader(tt.stdin), &stdout, []string{"--widget=indicator"}); code != 0 {
+				t.Errorf("runStatus() = %d, want 0", code)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("stdout = %q, want empty", stdout.String())
+			}
+
+			log, _ := os.ReadFile(filepath.Join(logDir, "session-unknown.log"))
+			if !strings.Contains(string(log), "status line failed") {
+				t.Errorf("debug log missing the error:\n%s", log)
+			}
+		})
+	}
+}
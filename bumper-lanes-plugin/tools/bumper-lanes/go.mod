@@ -2,4 +2,8 @@ module github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools
 
 go 1.25.5
 
-require github.com/kylesnowschwartz/diff-viz/v2 v2.5.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/kylesnowschwartz/diff-viz/v2 v2.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
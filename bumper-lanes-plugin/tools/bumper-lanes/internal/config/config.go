@@ -1,16 +1,21 @@
 // Package config handles configuration loading for bumper-lanes.
 // Config files (in precedence order):
-//  1. .bumper-lanes.json at repo root (highest priority)
+//  1. .bumper-lanes.json at repo root (highest priority); .yaml, .yml, or
+//     .toml are read instead when there is no .json (see formats.go)
 //  2. ~/.config/bumper-lanes/config.json (global fallback)
 //  3. Built-in defaults (lowest priority)
 package config
 
 import (
-	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -20,23 +25,116 @@ const (
 	// DefaultViewMode is the default visualization mode.
 	DefaultViewMode = "tree"
 
-	// ValidModes lists all valid visualization modes.
-	// This should match diff-viz v2.4.0 render.ValidModes.
-	ValidModes = "tree smart sparkline-tree hotpath icicle brackets gauge depth stat"
+	// ScoreScopeWorking scores baseline vs working tree (staged, unstaged, untracked).
+	ScoreScopeWorking = "working"
+
+	// ScoreScopeStaged scores HEAD vs index only.
+	ScoreScopeStaged = "staged"
+
+	// ScatterModeCount bases the scatter penalty on the number of files with additions.
+	ScatterModeCount = "count"
+
+	// ScatterModeWeighted discounts small and concentrated changes (see scoring.Options.WeightedScatter).
+	ScatterModeWeighted = "weighted"
+
+	// ScoreDisplayRaw shows scores as computed.
+	ScoreDisplayRaw = "raw"
+
+	// ScoreDisplayRounded shows scores rounded to the nearest 10.
+	ScoreDisplayRounded = "rounded"
+
+	// ScoreDisplayPercent shows scores on a 0-100 scale relative to the threshold.
+	ScoreDisplayPercent = "percent"
 )
 
 // Config represents bumper-lanes configuration.
 // Threshold: nil=use default (600), 0=disabled, 50-2000=active threshold
+// ThresholdFile: ""=off, else path to a file holding one integer that overrides Threshold (re-read on every load)
 // ShowDiffViz: nil=default (true), false=hide diff visualization
+// ShowSessionAge: nil=default (false), true=show time since last reset in status line
+// ShowRemaining: nil=default (false), true=show points left (or over) in status line
+// ShowBaselineAnchor: nil=default (false), true=show what the score is measured from ("since reset 12m ago")
+// StatuslineMaxDiffLines: nil=default (8), 0=unlimited, >0=max diff tree lines in the status line
+// StatuslineNBSP: nil=default (true), false=emit regular spaces in the status line diff tree instead of U+00A0
+// ShowExtensions: nil=default (false), true=show additions by file extension in status line
+// StopShowExtensions: nil=default (false), true=name the top 3 extensions by additions in the Stop message
+// DiscountComments: nil=default (false), true=score added comment lines at 0.25x
+// ScoreScope: ""=default ("working"), "staged"=score HEAD vs index only
+// DeletionWeight: nil=default (0, deletions free), >0=points per deleted line
+// HunkWeight: nil=default (0, off), >0=points per diff hunk (discontinuous change region)
+// HeadLinesWeight: nil/0/1=off, else multiplier on added lines within the first HeadLinesCount lines of a file (experimental)
+// HeadLinesCount: nil=default (50), >0=how many lines at the top of a file HeadLinesWeight covers
+// DisableScatter: nil=default (false), true=no scatter penalty
+// ScatterMode: ""=default ("count"), "weighted"=scatter file count discounted by change size and spread
+// ScoreDisplay: ""=default ("raw"), "rounded"=nearest 10, "percent"=0-100 of the threshold; display only, never enforcement
+// ScatterWeights: nil=every file counts 1 toward scatter, else file name suffix -> multiplier (e.g. "_test.go": 0.5)
+// ResetOnBranchSwitch: nil=default (true), false=keep baseline and score when the branch changes
+// CooldownScore: nil/0=off, >0=points the score must climb after a reset before Stop can trip again
+// MinEnforceScore: nil/0=off, >0=scores below this never block Stop or print a fuel gauge
+// CarryoverFraction: nil=default (0), 0-1=share of the pre-commit score carried into the new baseline on auto-reset after commit
+// GaugeQuietSeconds: nil=default (60), 0=off, >0=seconds a same-tier fuel gauge message is suppressed after it shows
+// CountUntracked: nil=default (true), false=leave untracked files out of baselines, scores, and the view
+// RespectGitattributes: nil=default (true), false=score files marked linguist-generated in .gitattributes
+// InheritBaseline: nil=default (false), true=a new session on the same branch inherits the last session's baseline
+// ObserveOnly: nil=default (false), true=hooks track scores but never block or print anything
+// ScoreSubmodules: nil=default (false), true=score line changes inside submodules, not just pointer bumps
+// SubmoduleWeight: nil=default (1), >=0=multiplier on the submodule score
+// RequireResetConfirmation: nil=default (false), true=the first /bumper-reset after a trip must be re-issued or passed --confirm
+// RepoRoot/GitDir: global config only; ""=ask git rev-parse, else pin the repo root and git dir (default <repo_root>/.git)
+// Include/Exclude: glob lists filtering which files are scored and shown (nil=all files)
+// DiffFlags: extra git diff flags for numstat, e.g. ["-M"] for rename detection (nil=none)
+// ReviewChecklist: review steps appended to the Stop message as a numbered list when a session trips (nil=none)
 type Config struct {
-	Threshold       *int   `json:"threshold,omitempty"`
-	DefaultViewMode string `json:"default_view_mode,omitempty"`
-	DefaultViewOpts string `json:"default_view_opts,omitempty"` // e.g., "--width 80 --depth 3"
-	ShowDiffViz     *bool  `json:"show_diff_viz,omitempty"`
+	Threshold                *int               `json:"threshold,omitempty"`
+	ThresholdFile            string             `json:"threshold_file,omitempty"`
+	DefaultViewMode          string             `json:"default_view_mode,omitempty"`
+	DefaultViewOpts          string             `json:"default_view_opts,omitempty"` // e.g., "--width 80 --depth 3"
+	ShowDiffViz              *bool              `json:"show_diff_viz,omitempty"`
+	ShowSessionAge           *bool              `json:"show_session_age,omitempty"`
+	ShowRemaining            *bool              `json:"show_remaining,omitempty"`
+	ShowBaselineAnchor       *bool              `json:"show_baseline_anchor,omitempty"`
+	StatuslineMaxDiffLines   *int               `json:"statusline_max_diff_lines,omitempty"`
+	StatuslineNBSP           *bool              `json:"statusline_nbsp,omitempty"`
+	ShowExtensions           *bool              `json:"show_extensions,omitempty"`
+	StopShowExtensions       *bool              `json:"stop_show_extensions,omitempty"`
+	DiscountComments         *bool              `json:"discount_comments,omitempty"`
+	ScoreScope               string             `json:"score_scope,omitempty"`
+	DeletionWeight           *float64           `json:"deletion_weight,omitempty"`
+	HunkWeight               *float64           `json:"hunk_weight,omitempty"`
+	HeadLinesWeight          *float64           `json:"head_lines_weight,omitempty"`
+	HeadLinesCount           *int               `json:"head_lines_count,omitempty"`
+	DisableScatter           *bool              `json:"disable_scatter,omitempty"`
+	ScatterMode              string             `json:"scatter_mode,omitempty"`
+	ScoreDisplay             string             `json:"score_display,omitempty"`
+	ScatterWeights           map[string]float64 `json:"scatter_weights,omitempty"`
+	ResetOnBranchSwitch      *bool              `json:"reset_on_branch_switch,omitempty"`
+	CooldownScore            *int               `json:"cooldown_score,omitempty"`
+	MinEnforceScore          *int               `json:"min_enforce_score,omitempty"`
+	CarryoverFraction        *float64           `json:"carryover_fraction,omitempty"`
+	GaugeQuietSeconds        *int               `json:"gauge_quiet_seconds,omitempty"`
+	CountUntracked           *bool              `json:"count_untracked,omitempty"`
+	RespectGitattributes     *bool              `json:"respect_gitattributes,omitempty"`
+	InheritBaseline          *bool              `json:"inherit_baseline,omitempty"`
+	ObserveOnly              *bool              `json:"observe_only,omitempty"`
+	ScoreSubmodules          *bool              `json:"score_submodules,omitempty"`
+	SubmoduleWeight          *float64           `json:"submodule_weight,omitempty"`
+	RequireResetConfirmation *bool              `json:"require_reset_confirmation,omitempty"`
+	RepoRoot                 string             `json:"repo_root,omitempty"`
+	GitDir                   string             `json:"git_dir,omitempty"`
+	Include                  []string           `json:"include,omitempty"`
+	Exclude                  []string           `json:"exclude,omitempty"`
+	DiffFlags                []string           `json:"diff_flags,omitempty"`
+	ReviewChecklist          []string           `json:"review_checklist,omitempty"`
 }
 
 // GetGitDir returns the absolute git directory path.
+// A repo_root override bypasses git rev-parse.
 func GetGitDir() (string, error) {
+	if root, err := RepoRootOverride(); err != nil {
+		return "", err
+	} else if root != nil {
+		return root.GitDir, nil
+	}
 	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
 	output, err := cmd.Output()
 	if err != nil {
@@ -46,7 +144,13 @@ func GetGitDir() (string, error) {
 }
 
 // getRepoRoot returns the repository root path.
+// A repo_root override bypasses git rev-parse.
 func getRepoRoot() (string, error) {
+	if root, err := RepoRootOverride(); err != nil {
+		return "", err
+	} else if root != nil {
+		return root.Root, nil
+	}
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
@@ -55,17 +159,13 @@ func getRepoRoot() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-// loadConfigFile reads and parses a JSON config file.
+// loadConfigFile reads and parses a config file (JSON, YAML, or TOML by extension).
 func loadConfigFile(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
-	}
-	return &cfg, nil
+	return decodeConfig(path, data)
 }
 
 // getGlobalConfigPath returns the path to the global config file.
@@ -82,6 +182,12 @@ func getGlobalConfigPath() string {
 	return filepath.Join(configDir, "bumper-lanes", "config.json")
 }
 
+// LoadMerged returns the merged global + repo config. Nil fields mean the
+// default applies; use the Load* functions for effective values.
+func LoadMerged() *Config {
+	return loadMergedConfig()
+}
+
 // loadMergedConfig loads config from global and repo locations, merging them.
 // Repo config values override global config values.
 // Returns an empty Config if neither file exists (never nil).
@@ -100,7 +206,7 @@ func loadMergedConfig() *Config {
 	if err != nil {
 		return merged
 	}
-	repoPath := filepath.Join(repoRoot, ".bumper-lanes.json")
+	repoPath := repoConfigPath(repoRoot)
 	repo, err := loadConfigFile(repoPath)
 	if err != nil {
 		return merged
@@ -110,6 +216,9 @@ func loadMergedConfig() *Config {
 	if repo.Threshold != nil {
 		merged.Threshold = repo.Threshold
 	}
+	if repo.ThresholdFile != "" {
+		merged.ThresholdFile = repo.ThresholdFile
+	}
 	if repo.DefaultViewMode != "" {
 		merged.DefaultViewMode = repo.DefaultViewMode
 	}
@@ -119,19 +228,206 @@ func loadMergedConfig() *Config {
 	if repo.ShowDiffViz != nil {
 		merged.ShowDiffViz = repo.ShowDiffViz
 	}
+	if repo.ShowSessionAge != nil {
+		merged.ShowSessionAge = repo.ShowSessionAge
+	}
+	if repo.ShowRemaining != nil {
+		merged.ShowRemaining = repo.ShowRemaining
+	}
+	if repo.ShowBaselineAnchor != nil {
+		merged.ShowBaselineAnchor = repo.ShowBaselineAnchor
+	}
+	if repo.StatuslineMaxDiffLines != nil {
+		merged.StatuslineMaxDiffLines = repo.StatuslineMaxDiffLines
+	}
+	if repo.StatuslineNBSP != nil {
+		merged.StatuslineNBSP = repo.StatuslineNBSP
+	}
+	if repo.ShowExtensions != nil {
+		merged.ShowExtensions = repo.ShowExtensions
+	}
+	if repo.StopShowExtensions != nil {
+		merged.StopShowExtensions = repo.StopShowExtensions
+	}
+	if repo.DiscountComments != nil {
+		merged.DiscountComments = repo.DiscountComments
+	}
+	if repo.ScoreScope != "" {
+		merged.ScoreScope = repo.ScoreScope
+	}
+	if repo.DeletionWeight != nil {
+		merged.DeletionWeight = repo.DeletionWeight
+	}
+	if repo.HunkWeight != nil {
+		merged.HunkWeight = repo.HunkWeight
+	}
+	if repo.HeadLinesWeight != nil {
+		merged.HeadLinesWeight = repo.HeadLinesWeight
+	}
+	if repo.HeadLinesCount != nil {
+		merged.HeadLinesCount = repo.HeadLinesCount
+	}
+	if repo.DisableScatter != nil {
+		merged.DisableScatter = repo.DisableScatter
+	}
+	if repo.ScatterMode != "" {
+		merged.ScatterMode = repo.ScatterMode
+	}
+	if repo.ScoreDisplay != "" {
+		merged.ScoreDisplay = repo.ScoreDisplay
+	}
+	if repo.ScatterWeights != nil {
+		merged.ScatterWeights = repo.ScatterWeights
+	}
+	if repo.ResetOnBranchSwitch != nil {
+		merged.ResetOnBranchSwitch = repo.ResetOnBranchSwitch
+	}
+	if repo.CooldownScore != nil {
+		merged.CooldownScore = repo.CooldownScore
+	}
+	if repo.MinEnforceScore != nil {
+		merged.MinEnforceScore = repo.MinEnforceScore
+	}
+	if repo.CarryoverFraction != nil {
+		merged.CarryoverFraction = repo.CarryoverFraction
+	}
+	if repo.GaugeQuietSeconds != nil {
+		merged.GaugeQuietSeconds = repo.GaugeQuietSeconds
+	}
+	if repo.CountUntracked != nil {
+		merged.CountUntracked = repo.CountUntracked
+	}
+	if repo.RespectGitattributes != nil {
+		merged.RespectGitattributes = repo.RespectGitattributes
+	}
+	if repo.InheritBaseline != nil {
+		merged.InheritBaseline = repo.InheritBaseline
+	}
+	if repo.ObserveOnly != nil {
+		merged.ObserveOnly = repo.ObserveOnly
+	}
+	if repo.ScoreSubmodules != nil {
+		merged.ScoreSubmodules = repo.ScoreSubmodules
+	}
+	if repo.SubmoduleWeight != nil {
+		merged.SubmoduleWeight = repo.SubmoduleWeight
+	}
+	if repo.RequireResetConfirmation != nil {
+		merged.RequireResetConfirmation = repo.RequireResetConfirmation
+	}
+	if repo.Include != nil {
+		merged.Include = repo.Include
+	}
+	if repo.Exclude != nil {
+		merged.Exclude = repo.Exclude
+	}
+	if repo.DiffFlags != nil {
+		merged.DiffFlags = repo.DiffFlags
+	}
+	if repo.ReviewChecklist != nil {
+		merged.ReviewChecklist = repo.ReviewChecklist
+	}
 
 	return merged
 }
 
+// Config layers reported by Source.
+const (
+	SourceRepo    = "repo"
+	SourceGlobal  = "global"
+	SourceDefault = "default"
+
+	// SourceThresholdFile marks a threshold read from threshold_file.
+	SourceThresholdFile = "threshold file"
+)
+
+// Source identifies which config layer supplied a setting.
+type Source struct {
+	Layer string // SourceRepo, SourceGlobal, or SourceDefault
+	Path  string // Config file path; empty for defaults
+}
+
+// String formats the source for display, e.g. "repo config at /x/.bumper-lanes.json".
+func (s Source) String() string {
+	if s.Path == "" {
+		return s.Layer
+	}
+	if s.Layer == SourceThresholdFile {
+		return fmt.Sprintf("%s at %s", s.Layer, s.Path)
+	}
+	return fmt.Sprintf("%s config at %s", s.Layer, s.Path)
+}
+
+// loadLayers loads the repo and global config files separately.
+// A layer is nil when its file is missing or unparseable.
+func loadLayers() (repo, global *Config, repoPath, globalPath string) {
+	globalPath = getGlobalConfigPath()
+	if globalPath != "" {
+		global, _ = loadConfigFile(globalPath)
+	}
+	if repoRoot, err := getRepoRoot(); err == nil {
+		repoPath = repoConfigPath(repoRoot)
+		repo, _ = loadConfigFile(repoPath)
+	}
+	return repo, global, repoPath, globalPath
+}
+
 // LoadThreshold returns the configured threshold value.
-// Checks repo config first, then global config, then returns DefaultThreshold.
-// Returns 0 if explicitly disabled.
+// A readable threshold_file wins; otherwise checks repo config first, then
+// global config, then returns DefaultThreshold. Returns 0 if explicitly disabled.
 func LoadThreshold() int {
-	cfg := loadMergedConfig()
-	if cfg.Threshold != nil {
-		return *cfg.Threshold
+	threshold, _ := LoadThresholdWithSource()
+	return threshold
+}
+
+// LoadThresholdWithSource is LoadThreshold plus the layer that supplied the value.
+func LoadThresholdWithSource() (int, Source) {
+	repo, global, repoPath, globalPath := loadLayers()
+	for _, layer := range []struct {
+		cfg  *Config
+		path string
+	}{{repo, repoPath}, {global, globalPath}} {
+		if layer.cfg == nil || layer.cfg.ThresholdFile == "" {
+			continue
+		}
+		path := layer.cfg.ThresholdFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(layer.path), path)
+		}
+		if threshold, err := readThresholdFile(path); err == nil {
+			return threshold, Source{SourceThresholdFile, path}
+		}
+		break // Unreadable budget file: fall back to the static threshold
+	}
+	if repo != nil && repo.Threshold != nil {
+		return *repo.Threshold, Source{SourceRepo, repoPath}
 	}
-	return DefaultThreshold
+	if global != nil && global.Threshold != nil {
+		return *global.Threshold, Source{SourceGlobal, globalPath}
+	}
+	return DefaultThreshold, Source{Layer: SourceDefault}
+}
+
+// readThresholdFile reads a budget file holding a single integer threshold,
+// e.g. written per-PR by CI. Values must pass validThreshold.
+func readThresholdFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	threshold, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("threshold file %s: %w", path, err)
+	}
+	if !validThreshold(threshold) {
+		return 0, fmt.Errorf("threshold file %s: must be 0 (disabled) or 50-2000, got %d", path, threshold)
+	}
+	return threshold, nil
+}
+
+// validThreshold reports whether n is 0 (disabled) or in the 50-2000 range.
+func validThreshold(n int) bool {
+	return n == 0 || (n >= 50 && n <= 2000)
 }
 
 // IsDisabled returns true if the given threshold means enforcement is disabled.
@@ -142,11 +438,27 @@ func IsDisabled(threshold int) bool {
 // LoadViewMode returns the configured default view mode.
 // Checks repo config first, then global config, then returns DefaultViewMode.
 func LoadViewMode() string {
-	cfg := loadMergedConfig()
-	if cfg.DefaultViewMode != "" && isValidMode(cfg.DefaultViewMode) {
-		return cfg.DefaultViewMode
+	mode, _ := LoadViewModeWithSource()
+	return mode
+}
+
+// LoadViewModeWithSource is LoadViewMode plus the layer that supplied the value.
+// An invalid mode in the winning layer falls through to the default, not the
+// next layer, matching the merged-config behavior.
+func LoadViewModeWithSource() (string, Source) {
+	repo, global, repoPath, globalPath := loadLayers()
+	var mode string
+	var source Source
+	switch {
+	case repo != nil && repo.DefaultViewMode != "":
+		mode, source = repo.DefaultViewMode, Source{SourceRepo, repoPath}
+	case global != nil && global.DefaultViewMode != "":
+		mode, source = global.DefaultViewMode, Source{SourceGlobal, globalPath}
+	}
+	if mode != "" && isValidMode(mode) {
+		return mode, source
 	}
-	return DefaultViewMode
+	return DefaultViewMode, Source{Layer: SourceDefault}
 }
 
 // LoadViewOpts returns the configured default view options (e.g., "--width 80").
@@ -156,9 +468,44 @@ func LoadViewOpts() string {
 	return cfg.DefaultViewOpts
 }
 
+// builtinModes are the visualization modes statusline ships a renderer
+// for, in the order /bumper-view lists them. They live here so config
+// validates modes without linking the statusline package; statusline's
+// tests check each one has a renderer.
+var builtinModes = []string{
+	"tree", "smart", "sparkline-tree", "hotpath", "icicle", "brackets",
+	"gauge", "depth", "stat", "oneline", "split",
+}
+
+// customModes are modes added by RegisterMode beyond the built-ins, in
+// registration order.
+var customModes []string
+
+// RegisterMode makes a custom visualization mode valid in config
+// validation and /bumper-view. statusline.RegisterRenderer calls this;
+// built-in modes are already valid, so it's a no-op for them.
+func RegisterMode(mode string) {
+	if !isValidMode(mode) {
+		customModes = append(customModes, mode)
+	}
+}
+
+// UnregisterMode removes a mode added by RegisterMode, so tests can
+// register a throwaway renderer without leaking it into later tests.
+// Built-in modes can't be removed.
+func UnregisterMode(mode string) {
+	customModes = slices.DeleteFunc(customModes, func(m string) bool { return m == mode })
+}
+
+// Modes returns the valid visualization modes: the built-ins, then custom
+// modes in registration order.
+func Modes() []string {
+	return append(slices.Clone(builtinModes), customModes...)
+}
+
 // isValidMode checks if the mode is in the valid modes list.
 func isValidMode(mode string) bool {
-	for _, valid := range strings.Fields(ValidModes) {
+	for _, valid := range Modes() {
 		if mode == valid {
 			return true
 		}
@@ -176,13 +523,463 @@ func LoadShowDiffViz() bool {
 	return true
 }
 
-// GetConfigPath returns the path to .bumper-lanes.json (or empty if not in a repo).
+// LoadShowSessionAge returns whether the status line should show time since last reset.
+// Checks repo config first, then global config, then returns false (default).
+func LoadShowSessionAge() bool {
+	cfg := loadMergedConfig()
+	if cfg.ShowSessionAge != nil {
+		return *cfg.ShowSessionAge
+	}
+	return false
+}
+
+// LoadShowRemaining returns whether the status line shows the points left in the budget.
+// Checks repo config first, then global config, then returns false (default).
+func LoadShowRemaining() bool {
+	cfg := loadMergedConfig()
+	if cfg.ShowRemaining != nil {
+		return *cfg.ShowRemaining
+	}
+	return false
+}
+
+// LoadShowBaselineAnchor returns whether the status line says what the score is measured from.
+// Checks repo config first, then global config, then returns false (default).
+func LoadShowBaselineAnchor() bool {
+	cfg := loadMergedConfig()
+	if cfg.ShowBaselineAnchor != nil {
+		return *cfg.ShowBaselineAnchor
+	}
+	return false
+}
+
+// DefaultStatuslineMaxDiffLines caps the status line diff tree when
+// statusline_max_diff_lines is unset.
+const DefaultStatuslineMaxDiffLines = 8
+
+// LoadStatuslineMaxDiffLines returns how many diff tree lines the status line
+// shows before truncating. Zero means unlimited; negative values fall back to
+// the default.
+func LoadStatuslineMaxDiffLines() int {
+	cfg := loadMergedConfig()
+	if cfg.StatuslineMaxDiffLines != nil && *cfg.StatuslineMaxDiffLines >= 0 {
+		return *cfg.StatuslineMaxDiffLines
+	}
+	return DefaultStatuslineMaxDiffLines
+}
+
+// LoadStatuslineNBSP returns whether status line diff tree spaces become
+// U+00A0, which Claude Code needs to keep the indentation. Defaults to true.
+func LoadStatuslineNBSP() bool {
+	cfg := loadMergedConfig()
+	if cfg.StatuslineNBSP != nil {
+		return *cfg.StatuslineNBSP
+	}
+	return true
+}
+
+// LoadShowExtensions returns whether the status line shows additions by file extension.
+// Checks repo config first, then global config, then returns false (default).
+func LoadShowExtensions() bool {
+	cfg := loadMergedConfig()
+	if cfg.ShowExtensions != nil {
+		return *cfg.ShowExtensions
+	}
+	return false
+}
+
+// LoadStopShowExtensions returns whether the Stop message names the top
+// extensions by added lines. Defaults to false to keep the message lean.
+func LoadStopShowExtensions() bool {
+	cfg := loadMergedConfig()
+	if cfg.StopShowExtensions != nil {
+		return *cfg.StopShowExtensions
+	}
+	return false
+}
+
+// LoadDiscountComments returns whether added comment lines are scored at a discount.
+// Opt-in because it requires reading full diff contents, not just line counts.
+func LoadDiscountComments() bool {
+	cfg := loadMergedConfig()
+	if cfg.DiscountComments != nil {
+		return *cfg.DiscountComments
+	}
+	return false
+}
+
+// LoadScoreScope returns which changes are scored: "working" or "staged".
+// Unknown values fall through to ScoreScopeWorking.
+func LoadScoreScope() string {
+	cfg := loadMergedConfig()
+	if cfg.ScoreScope == ScoreScopeStaged {
+		return ScoreScopeStaged
+	}
+	return ScoreScopeWorking
+}
+
+// LoadScatterMode returns how scatter counts files: "count" or "weighted".
+// Unknown values fall through to ScatterModeCount.
+func LoadScatterMode() string {
+	cfg := loadMergedConfig()
+	if cfg.ScatterMode == ScatterModeWeighted {
+		return ScatterModeWeighted
+	}
+	return ScatterModeCount
+}
+
+// LoadScoreDisplay returns how scores are shown: "raw", "rounded", or "percent".
+// Unknown values fall through to ScoreDisplayRaw.
+func LoadScoreDisplay() string {
+	cfg := loadMergedConfig()
+	switch cfg.ScoreDisplay {
+	case ScoreDisplayRounded, ScoreDisplayPercent:
+		return cfg.ScoreDisplay
+	}
+	return ScoreDisplayRaw
+}
+
+// LoadDeletionWeight returns points per deleted line.
+// Returns 0 (deletions free) by default or for negative values.
+func LoadDeletionWeight() float64 {
+	cfg := loadMergedConfig()
+	if cfg.DeletionWeight != nil && *cfg.DeletionWeight > 0 {
+		return *cfg.DeletionWeight
+	}
+	return 0
+}
+
+// LoadHunkWeight returns points per diff hunk (change region).
+// Returns 0 (hunks ignored) by default or for negative values.
+func LoadHunkWeight() float64 {
+	cfg := loadMergedConfig()
+	if cfg.HunkWeight != nil && *cfg.HunkWeight > 0 {
+		return *cfg.HunkWeight
+	}
+	return 0
+}
+
+// LoadHeadLinesWeight returns the multiplier on added lines near the top of
+// a file. Returns 0 (off) by default or for negative values; 1 is also a no-op.
+func LoadHeadLinesWeight() float64 {
+	cfg := loadMergedConfig()
+	if cfg.HeadLinesWeight != nil && *cfg.HeadLinesWeight > 0 {
+		return *cfg.HeadLinesWeight
+	}
+	return 0
+}
+
+// DefaultHeadLinesCount is how many lines at the top of a file
+// head_lines_weight covers when head_lines_count isn't set.
+const DefaultHeadLinesCount = 50
+
+// LoadHeadLinesCount returns how many leading lines of a file
+// head_lines_weight applies to. Non-positive values fall back to the default.
+func LoadHeadLinesCount() int {
+	cfg := loadMergedConfig()
+	if cfg.HeadLinesCount != nil && *cfg.HeadLinesCount > 0 {
+		return *cfg.HeadLinesCount
+	}
+	return DefaultHeadLinesCount
+}
+
+// LoadCarryoverFraction returns the share of the score kept when a commit
+// auto-resets the baseline. Values outside 0-1 fall back to 0 (no carry-over).
+func LoadCarryoverFraction() float64 {
+	cfg := loadMergedConfig()
+	if cfg.CarryoverFraction != nil && validCarryoverFraction(*cfg.CarryoverFraction) {
+		return *cfg.CarryoverFraction
+	}
+	return 0
+}
+
+// validCarryoverFraction reports whether f is a usable carryover_fraction.
+func validCarryoverFraction(f float64) bool {
+	return f >= 0 && f <= 1
+}
+
+// LoadDisableScatter returns whether the scatter penalty is turned off.
+// Useful in monorepos where legitimate changes touch many files.
+func LoadDisableScatter() bool {
+	cfg := loadMergedConfig()
+	if cfg.DisableScatter != nil {
+		return *cfg.DisableScatter
+	}
+	return false
+}
+
+// LoadScatterWeights returns the per-suffix scatter multipliers.
+// Negative weights are dropped. Returns nil (every file counts 1) when unset.
+func LoadScatterWeights() map[string]float64 {
+	weights := loadMergedConfig().ScatterWeights
+	if weights == nil {
+		return nil
+	}
+	valid := make(map[string]float64, len(weights))
+	for suffix, w := range weights {
+		if suffix != "" && w >= 0 {
+			valid[suffix] = w
+		}
+	}
+	return valid
+}
+
+// LoadResetOnBranchSwitch returns whether Stop resets the baseline when the branch changes.
+// Checks repo config first, then global config, then returns true (default).
+func LoadResetOnBranchSwitch() bool {
+	cfg := loadMergedConfig()
+	if cfg.ResetOnBranchSwitch != nil {
+		return *cfg.ResetOnBranchSwitch
+	}
+	return true
+}
+
+// LoadCooldownScore returns the post-reset cooldown delta in points.
+// Returns 0 (no cooldown) when unset or negative.
+func LoadCooldownScore() int {
+	cfg := loadMergedConfig()
+	if cfg.CooldownScore != nil && *cfg.CooldownScore > 0 {
+		return *cfg.CooldownScore
+	}
+	return 0
+}
+
+// LoadMinEnforceScore returns the score below which Stop and PostToolUse
+// stay silent. Returns 0 (off) when unset or negative.
+func LoadMinEnforceScore() int {
+	cfg := loadMergedConfig()
+	if cfg.MinEnforceScore != nil && *cfg.MinEnforceScore > 0 {
+		return *cfg.MinEnforceScore
+	}
+	return 0
+}
+
+// DefaultGaugeQuietSeconds is how long a repeated same-tier fuel gauge
+// message stays quiet when gauge_quiet_seconds is unset.
+const DefaultGaugeQuietSeconds = 60
+
+// LoadGaugeQuietWindow returns how long a same-tier fuel gauge message is
+// suppressed after it was shown. Zero disables suppression; negative values
+// fall back to the default.
+func LoadGaugeQuietWindow() time.Duration {
+	cfg := loadMergedConfig()
+	if cfg.GaugeQuietSeconds != nil && *cfg.GaugeQuietSeconds >= 0 {
+		return time.Duration(*cfg.GaugeQuietSeconds) * time.Second
+	}
+	return DefaultGaugeQuietSeconds * time.Second
+}
+
+// LoadCountUntracked returns whether untracked (not ignored) files are part of
+// captured trees and the diff view. Defaults to true.
+func LoadCountUntracked() bool {
+	cfg := loadMergedConfig()
+	if cfg.CountUntracked != nil {
+		return *cfg.CountUntracked
+	}
+	return true
+}
+
+// LoadRespectGitattributes returns whether files marked linguist-generated
+// in .gitattributes are left out of the score. Defaults to true.
+func LoadRespectGitattributes() bool {
+	cfg := loadMergedConfig()
+	if cfg.RespectGitattributes != nil {
+		return *cfg.RespectGitattributes
+	}
+	return true
+}
+
+// LoadInheritBaseline returns whether SessionStart may reuse the baseline of a
+// recent prior session on the same branch instead of capturing a fresh one.
+// Defaults to false.
+func LoadInheritBaseline() bool {
+	cfg := loadMergedConfig()
+	if cfg.InheritBaseline != nil {
+		return *cfg.InheritBaseline
+	}
+	return false
+}
+
+// LoadObserveOnly returns whether hooks run in observe-only mode: scores and
+// state are still recorded, but nothing is blocked or shown. Defaults to false.
+func LoadObserveOnly() bool {
+	cfg := loadMergedConfig()
+	if cfg.ObserveOnly != nil {
+		return *cfg.ObserveOnly
+	}
+	return false
+}
+
+// LoadScoreSubmodules returns whether line changes inside submodules count
+// toward the score. Defaults to false.
+func LoadScoreSubmodules() bool {
+	cfg := loadMergedConfig()
+	if cfg.ScoreSubmodules != nil {
+		return *cfg.ScoreSubmodules
+	}
+	return false
+}
+
+// DefaultSubmoduleWeight is the default multiplier on submodule scores.
+const DefaultSubmoduleWeight = 1.0
+
+// LoadSubmoduleWeight returns the multiplier applied to submodule scores.
+// Negative values fall back to the default.
+func LoadSubmoduleWeight() float64 {
+	cfg := loadMergedConfig()
+	if cfg.SubmoduleWeight != nil && *cfg.SubmoduleWeight >= 0 {
+		return *cfg.SubmoduleWeight
+	}
+	return DefaultSubmoduleWeight
+}
+
+// LoadRequireResetConfirmation returns whether the first /bumper-reset
+// after a trip asks to be confirmed instead of resetting. Defaults to false.
+func LoadRequireResetConfirmation() bool {
+	cfg := loadMergedConfig()
+	if cfg.RequireResetConfirmation != nil {
+		return *cfg.RequireResetConfirmation
+	}
+	return false
+}
+
+// LoadReviewChecklist returns the review steps listed in the Stop message
+// on a trip, skipping blank entries. Empty means no checklist.
+func LoadReviewChecklist() []string {
+	var steps []string
+	for _, step := range loadMergedConfig().ReviewChecklist {
+		if strings.TrimSpace(step) != "" {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// LoadInclude returns the include glob list. Empty means all files are included.
+func LoadInclude() []string {
+	return loadMergedConfig().Include
+}
+
+// LoadExclude returns the exclude glob list, applied after include.
+func LoadExclude() []string {
+	return loadMergedConfig().Exclude
+}
+
+// LoadDiffFlags returns the extra flags passed to git's numstat diffs.
+// Entries that fail validDiffFlag are dropped.
+func LoadDiffFlags() []string {
+	var flags []string
+	for _, f := range loadMergedConfig().DiffFlags {
+		if validDiffFlag(f) {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// validDiffFlag reports whether f is safe to pass to git diff: it must be an
+// option (so it can't be taken as a ref or path), and not --output, which
+// writes the diff to a file instead of stdout.
+func validDiffFlag(f string) bool {
+	return strings.HasPrefix(f, "-") && !strings.HasPrefix(f, "--output")
+}
+
+// ValidateConfigFile parses a config file and checks field values.
+// Load* functions silently ignore bad values; this surfaces them for diagnostics.
+// Returns os.ErrNotExist (wrapped) if the file is missing.
+func ValidateConfigFile(path string) error {
+	cfg, err := loadConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if cfg.Threshold != nil && !validThreshold(*cfg.Threshold) {
+		return fmt.Errorf("threshold must be 0 (disabled) or 50-2000, got %d", *cfg.Threshold)
+	}
+	if cfg.DefaultViewMode != "" && !isValidMode(cfg.DefaultViewMode) {
+		return fmt.Errorf("unknown default_view_mode %q", cfg.DefaultViewMode)
+	}
+	if cfg.ScoreScope != "" && cfg.ScoreScope != ScoreScopeWorking && cfg.ScoreScope != ScoreScopeStaged {
+		return fmt.Errorf("score_scope must be %q or %q, got %q", ScoreScopeWorking, ScoreScopeStaged, cfg.ScoreScope)
+	}
+	if cfg.ScatterMode != "" && cfg.ScatterMode != ScatterModeCount && cfg.ScatterMode != ScatterModeWeighted {
+		return fmt.Errorf("scatter_mode must be %q or %q, got %q", ScatterModeCount, ScatterModeWeighted, cfg.ScatterMode)
+	}
+	switch cfg.ScoreDisplay {
+	case "", ScoreDisplayRaw, ScoreDisplayRounded, ScoreDisplayPercent:
+	default:
+		return fmt.Errorf("score_display must be %q, %q, or %q, got %q", ScoreDisplayRaw, ScoreDisplayRounded, ScoreDisplayPercent, cfg.ScoreDisplay)
+	}
+	if cfg.CarryoverFraction != nil && !validCarryoverFraction(*cfg.CarryoverFraction) {
+		return fmt.Errorf("carryover_fraction must be between 0 and 1, got %g", *cfg.CarryoverFraction)
+	}
+	if cfg.HunkWeight != nil && *cfg.HunkWeight < 0 {
+		return fmt.Errorf("hunk_weight must be 0 or more, got %g", *cfg.HunkWeight)
+	}
+	if cfg.HeadLinesWeight != nil && *cfg.HeadLinesWeight < 0 {
+		return fmt.Errorf("head_lines_weight must be 0 or more, got %g", *cfg.HeadLinesWeight)
+	}
+	if cfg.HeadLinesCount != nil && *cfg.HeadLinesCount < 1 {
+		return fmt.Errorf("head_lines_count must be 1 or more, got %d", *cfg.HeadLinesCount)
+	}
+	if cfg.MinEnforceScore != nil && *cfg.MinEnforceScore < 0 {
+		return fmt.Errorf("min_enforce_score must be 0 or more, got %d", *cfg.MinEnforceScore)
+	}
+	if cfg.SubmoduleWeight != nil && *cfg.SubmoduleWeight < 0 {
+		return fmt.Errorf("submodule_weight must be 0 or more, got %g", *cfg.SubmoduleWeight)
+	}
+	if cfg.GitDir != "" && cfg.RepoRoot == "" {
+		return fmt.Errorf("git_dir requires repo_root")
+	}
+	if cfg.RepoRoot != "" {
+		if err := validateRepoRoot(RepoRoot{Root: cfg.RepoRoot, GitDir: resolveGitDir(cfg.RepoRoot, cfg.GitDir)}); err != nil {
+			return err
+		}
+	}
+	for _, f := range cfg.DiffFlags {
+		if !validDiffFlag(f) {
+			return fmt.Errorf("diff_flags entries must be options starting with \"-\" (not --output), got %q", f)
+		}
+	}
+	return nil
+}
+
+// GitignoreWarning returns a warning if .bumper-lanes.json is tracked by git
+// but also matches a .gitignore pattern. Git keeps committing a tracked file
+// regardless of .gitignore, so this usually means the ignore was a mistake.
+// Returns empty string if the file is fine, missing, or on any git error.
+func GitignoreWarning() string {
+	repoRoot, err := getRepoRoot()
+	if err != nil {
+		return ""
+	}
+
+	const name = ".bumper-lanes.json"
+	if _, err := os.Stat(filepath.Join(repoRoot, name)); err != nil {
+		return ""
+	}
+
+	// --no-index: check patterns even though the file is tracked
+	ignored := exec.Command("git", "-C", repoRoot, "check-ignore", "-q", "--no-index", name)
+	if ignored.Run() != nil {
+		return ""
+	}
+	tracked := exec.Command("git", "-C", repoRoot, "ls-files", "--error-unmatch", name)
+	if tracked.Run() != nil {
+		return "" // Ignored and untracked: intentional per-user config
+	}
+
+	return fmt.Sprintf("%s is tracked but matches .gitignore; changes will still be committed. "+
+		"Remove it from .gitignore, or run: git rm --cached %s", name, name)
+}
+
+// GetConfigPath returns the path to the repo config file in use, normally
+// .bumper-lanes.json (or empty if not in a repo).
 func GetConfigPath() string {
 	repoRoot, err := getRepoRoot()
 	if err != nil {
 		return ""
 	}
-	return filepath.Join(repoRoot, ".bumper-lanes.json")
+	return repoConfigPath(repoRoot)
 }
 
 // GetGlobalConfigPath returns the path to the global config file.
@@ -191,56 +988,184 @@ func GetGlobalConfigPath() string {
 	return getGlobalConfigPath()
 }
 
-// SaveRepoConfig writes threshold to repo config file.
+// SaveRepoConfig writes threshold to repo config file, preserving other fields.
 func SaveRepoConfig(threshold int) error {
-	repoRoot, err := getRepoRoot()
-	if err != nil {
-		return err
-	}
-
-	cfg := Config{Threshold: &threshold}
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	path := filepath.Join(repoRoot, ".bumper-lanes.json")
-	return os.WriteFile(path, data, 0644)
+	return SaveConfig(Config{Threshold: &threshold})
 }
 
 // SaveConfig writes the full config to .bumper-lanes.json, preserving existing values.
+// The read-modify-write runs under the config lock and the write is atomic.
 func SaveConfig(updates Config) error {
+	return updateRepoConfig(func(existing *Config) {
+		// Apply updates (non-nil pointers and non-empty strings override)
+		if updates.Threshold != nil {
+			existing.Threshold = updates.Threshold
+		}
+		if updates.ThresholdFile != "" {
+			existing.ThresholdFile = updates.ThresholdFile
+		}
+		if updates.DefaultViewMode != "" {
+			existing.DefaultViewMode = updates.DefaultViewMode
+		}
+		if updates.DefaultViewOpts != "" {
+			existing.DefaultViewOpts = updates.DefaultViewOpts
+		}
+		if updates.ShowDiffViz != nil {
+			existing.ShowDiffViz = updates.ShowDiffViz
+		}
+		if updates.ShowSessionAge != nil {
+			existing.ShowSessionAge = updates.ShowSessionAge
+		}
+		if updates.ShowRemaining != nil {
+			existing.ShowRemaining = updates.ShowRemaining
+		}
+		if updates.ShowBaselineAnchor != nil {
+			existing.ShowBaselineAnchor = updates.ShowBaselineAnchor
+		}
+		if updates.StatuslineMaxDiffLines != nil {
+			existing.StatuslineMaxDiffLines = updates.StatuslineMaxDiffLines
+		}
+		if updates.StatuslineNBSP != nil {
+			existing.StatuslineNBSP = updates.StatuslineNBSP
+		}
+		if updates.ShowExtensions != nil {
+			existing.ShowExtensions = updates.ShowExtensions
+		}
+		if updates.StopShowExtensions != nil {
+			existing.StopShowExtensions = updates.StopShowExtensions
+		}
+		if updates.DiscountComments != nil {
+			existing.DiscountComments = updates.DiscountComments
+		}
+		if updates.ScoreScope != "" {
+			existing.ScoreScope = updates.ScoreScope
+		}
+		if updates.DeletionWeight != nil {
+			existing.DeletionWeight = updates.DeletionWeight
+		}
+		if updates.HunkWeight != nil {
+			existing.HunkWeight = updates.HunkWeight
+		}
+		if updates.HeadLinesWeight != nil {
+			existing.HeadLinesWeight = updates.HeadLinesWeight
+		}
+		if updates.HeadLinesCount != nil {
+			existing.HeadLinesCount = updates.HeadLinesCount
+		}
+		if updates.DisableScatter != nil {
+			existing.DisableScatter = updates.DisableScatter
+		}
+		if updates.ScatterMode != "" {
+			existing.ScatterMode = updates.ScatterMode
+		}
+		if updates.ScoreDisplay != "" {
+			existing.ScoreDisplay = updates.ScoreDisplay
+		}
+		if updates.ScatterWeights != nil {
+			existing.ScatterWeights = updates.ScatterWeights
+		}
+		if updates.ResetOnBranchSwitch != nil {
+			existing.ResetOnBranchSwitch = updates.ResetOnBranchSwitch
+		}
+		if updates.CooldownScore != nil {
+			existing.CooldownScore = updates.CooldownScore
+		}
+		if updates.MinEnforceScore != nil {
+			existing.MinEnforceScore = updates.MinEnforceScore
+		}
+		if updates.CarryoverFraction != nil {
+			existing.CarryoverFraction = updates.CarryoverFraction
+		}
+		if updates.GaugeQuietSeconds != nil {
+			existing.GaugeQuietSeconds = updates.GaugeQuietSeconds
+		}
+		if updates.CountUntracked != nil {
+			existing.CountUntracked = updates.CountUntracked
+		}
+		if updates.RespectGitattributes != nil {
+			existing.RespectGitattributes = updates.RespectGitattributes
+		}
+		if updates.InheritBaseline != nil {
+			existing.InheritBaseline = updates.InheritBaseline
+		}
+		if updates.ObserveOnly != nil {
+			existing.ObserveOnly = updates.ObserveOnly
+		}
+		if updates.ScoreSubmodules != nil {
+			existing.ScoreSubmodules = updates.ScoreSubmodules
+		}
+		if updates.SubmoduleWeight != nil {
+			existing.SubmoduleWeight = updates.SubmoduleWeight
+		}
+		if updates.RequireResetConfirmation != nil {
+			existing.RequireResetConfirmation = updates.RequireResetConfirmation
+		}
+		if updates.Include != nil {
+			existing.Include = updates.Include
+		}
+		if updates.Exclude != nil {
+			existing.Exclude = updates.Exclude
+		}
+		if updates.DiffFlags != nil {
+			existing.DiffFlags = updates.DiffFlags
+		}
+		if updates.ReviewChecklist != nil {
+			existing.ReviewChecklist = updates.ReviewChecklist
+		}
+	})
+}
+
+// UnsetConfig removes a field (by JSON key, e.g. "threshold") from
+// .bumper-lanes.json so it falls back to the global config or default.
+// Other fields are preserved. A missing file or unset key is a no-op.
+func UnsetConfig(key string) error {
+	field, ok := configFieldByKey(key)
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+
 	repoRoot, err := getRepoRoot()
 	if err != nil {
 		return err
 	}
+	if _, err := os.Stat(repoConfigPath(repoRoot)); os.IsNotExist(err) {
+		return nil
+	}
 
-	path := filepath.Join(repoRoot, ".bumper-lanes.json")
+	return updateRepoConfig(func(existing *Config) {
+		v := reflect.ValueOf(existing).Elem().FieldByIndex(field.Index)
+		v.Set(reflect.Zero(v.Type()))
+	})
+}
 
-	// Load existing config to preserve other fields
-	existing, _ := loadConfigFile(path)
-	if existing == nil {
-		existing = &Config{}
+// ConfigKeys returns the JSON keys accepted in config files, in field order.
+func ConfigKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonKey(t.Field(i)); name != "" {
+			keys = append(keys, name)
+		}
 	}
+	return keys
+}
 
-	// Apply updates (non-nil pointers and non-empty strings override)
-	if updates.Threshold != nil {
-		existing.Threshold = updates.Threshold
-	}
-	if updates.DefaultViewMode != "" {
-		existing.DefaultViewMode = updates.DefaultViewMode
-	}
-	if updates.DefaultViewOpts != "" {
-		existing.DefaultViewOpts = updates.DefaultViewOpts
-	}
-	if updates.ShowDiffViz != nil {
-		existing.ShowDiffViz = updates.ShowDiffViz
+// configFieldByKey finds the Config field with the given JSON key.
+func configFieldByKey(key string) (reflect.StructField, bool) {
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); jsonKey(f) == key {
+			return f, true
+		}
 	}
+	return reflect.StructField{}, false
+}
 
-	data, err := json.MarshalIndent(existing, "", "  ")
-	if err != nil {
-		return err
+// jsonKey returns a struct field's JSON name, or "" if it isn't serialized.
+func jsonKey(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
 	}
-
-	return os.WriteFile(path, data, 0644)
+	return name
 }
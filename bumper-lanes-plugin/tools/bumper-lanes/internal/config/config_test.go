@@ -5,7 +5,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestConfigLoading verifies config loading from .bumper-lanes.json.
@@ -80,6 +82,25 @@ func TestConfigLoading(t *testing.T) {
 			t.Errorf("LoadViewOpts() = %q, want '--width 80 --depth 3' (config)", got)
 		}
 	})
+
+	t.Run("score scope loading", func(t *testing.T) {
+		os.Remove(repoPath)
+
+		if got := LoadScoreScope(); got != ScoreScopeWorking {
+			t.Errorf("LoadScoreScope() = %q, want %q (default)", got, ScoreScopeWorking)
+		}
+
+		os.WriteFile(repoPath, []byte(`{"score_scope": "staged"}`), 0644)
+		if got := LoadScoreScope(); got != ScoreScopeStaged {
+			t.Errorf("LoadScoreScope() = %q, want %q (config)", got, ScoreScopeStaged)
+		}
+
+		os.WriteFile(repoPath, []byte(`{"score_scope": "bogus"}`), 0644)
+		defer os.Remove(repoPath)
+		if got := LoadScoreScope(); got != ScoreScopeWorking {
+			t.Errorf("LoadScoreScope() = %q, want %q (invalid should use default)", got, ScoreScopeWorking)
+		}
+	})
 }
 
 // TestGitWorktreeDetection verifies GetGitDir works in worktrees.
@@ -114,6 +135,20 @@ func TestGitWorktreeDetection(t *testing.T) {
 			t.Errorf("GetGitDir() = %q, want path containing 'worktrees'", gitDir)
 		}
 	})
+
+	t.Run("each worktree has its own git dir", func(t *testing.T) {
+		worktreeGitDir, _ := GetGitDir()
+		os.Chdir(mainRepo)
+		defer os.Chdir(worktreeDir)
+		mainGitDir, err := GetGitDir()
+		if err != nil {
+			t.Fatalf("GetGitDir() error = %v", err)
+		}
+		// Checkpoints live under the git dir, so sessions don't cross worktrees
+		if mainGitDir == worktreeGitDir {
+			t.Errorf("main and worktree share git dir %q", mainGitDir)
+		}
+	})
 }
 
 // TestEmptyRepoNoHEAD verifies we don't crash on repos without commits.
@@ -157,6 +192,56 @@ func TestEmptyRepoNoHEAD(t *testing.T) {
 	})
 }
 
+func TestGitignoreWarning(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	gitEnv := append(os.Environ(),
+		"GIT_AUTHOR_NAME=test",
+		"GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=test",
+		"GIT_COMMITTER_EMAIL=test@test.com",
+	)
+
+	t.Run("no warning without config", func(t *testing.T) {
+		if got := GitignoreWarning(); got != "" {
+			t.Errorf("GitignoreWarning() = %q, want empty", got)
+		}
+	})
+
+	os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 300}`), 0644)
+	os.WriteFile(".gitignore", []byte(".bumper-lanes.json\n"), 0644)
+
+	t.Run("no warning when ignored and untracked", func(t *testing.T) {
+		if got := GitignoreWarning(); got != "" {
+			t.Errorf("GitignoreWarning() = %q, want empty (intentional local config)", got)
+		}
+	})
+
+	t.Run("warns when tracked and ignored", func(t *testing.T) {
+		exec.Command("git", "add", "-f", ".bumper-lanes.json").Run()
+		cmd := exec.Command("git", "commit", "-m", "add config")
+		cmd.Env = gitEnv
+		cmd.Run()
+
+		got := GitignoreWarning()
+		if !strings.Contains(got, "tracked but matches .gitignore") {
+			t.Errorf("GitignoreWarning() = %q, want tracked+ignored warning", got)
+		}
+	})
+
+	t.Run("no warning when tracked and not ignored", func(t *testing.T) {
+		os.WriteFile(".gitignore", []byte("other\n"), 0644)
+		if got := GitignoreWarning(); got != "" {
+			t.Errorf("GitignoreWarning() = %q, want empty", got)
+		}
+	})
+}
+
 func setupGitRepo(t *testing.T, dir string) {
 	t.Helper()
 	cmd := exec.Command("git", "init")
@@ -193,6 +278,8 @@ func TestIsValidMode(t *testing.T) {
 		{"gauge", true},
 		{"depth", true},
 		{"stat", true},
+		{"oneline", true},
+		{"split", true},
 		// Removed modes (no longer valid)
 		{"collapsed", false},
 		{"topn", false},
@@ -209,6 +296,19 @@ func TestIsValidMode(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("custom modes register and unregister", func(t *testing.T) {
+		RegisterMode("custom")
+		RegisterMode("tree") // Built-in: already valid, not listed twice
+		if modes := Modes(); !isValidMode("custom") || modes[len(modes)-1] != "custom" || len(modes) != len(builtinModes)+1 {
+			t.Errorf("Modes() = %v, want the built-ins then custom", modes)
+		}
+		UnregisterMode("custom")
+		UnregisterMode("tree")
+		if isValidMode("custom") || !isValidMode("tree") {
+			t.Errorf("after unregister Modes() = %v, want custom gone and built-ins kept", Modes())
+		}
+	})
 }
 
 func TestLoadConfigFile(t *testing.T) {
@@ -234,6 +334,203 @@ func TestLoadConfigFile(t *testing.T) {
 	}
 }
 
+func TestValidateConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{"valid", `{"threshold": 300, "default_view_mode": "tree", "score_scope": "staged"}`, false},
+		{"disabled threshold", `{"threshold": 0}`, false},
+		{"empty", `{}`, false},
+		{"threshold out of range", `{"threshold": 10}`, true},
+		{"unknown view mode", `{"default_view_mode": "bogus"}`, true},
+		{"unknown score scope", `{"score_scope": "all"}`, true},
+		{"diff flags", `{"diff_flags": ["-M", "--ignore-all-space"]}`, false},
+		{"diff flag not an option", `{"diff_flags": ["HEAD"]}`, true},
+		{"diff flag writes a file", `{"diff_flags": ["--output=x"]}`, true},
+		{"carryover fraction", `{"carryover_fraction": 0.25}`, false},
+		{"scatter mode weighted", `{"scatter_mode": "weighted"}`, false},
+		{"unknown scatter mode", `{"scatter_mode": "spread"}`, true},
+		{"score display percent", `{"score_display": "percent"}`, false},
+		{"unknown score display", `{"score_display": "stars"}`, true},
+		{"head lines weight", `{"head_lines_weight": 2, "head_lines_count": 30}`, false},
+		{"negative head lines weight", `{"head_lines_weight": -1}`, true},
+		{"zero head lines count", `{"head_lines_count": 0}`, true},
+		{"carryover fraction over 1", `{"carryover_fraction": 1.5}`, true},
+		{"submodule weight", `{"submodule_weight": 0.5}`, false},
+		{"negative submodule weight", `{"submodule_weight": -1}`, true},
+		{"min enforce score", `{"min_enforce_score": 40}`, false},
+		{"negative min enforce score", `{"min_enforce_score": -1}`, true},
+		{"relative repo root", `{"repo_root": "work"}`, true},
+		{"missing repo root", `{"repo_root": "/nonexistent/bumper-lanes"}`, true},
+		{"git dir without repo root", `{"git_dir": "/tmp"}`, true},
+		{"invalid json", `{not json`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			os.WriteFile(path, []byte(tt.json), 0644)
+
+			err := ValidateConfigFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfigFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnsetConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // No global config
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	repoPath := filepath.Join(tmpDir, ".bumper-lanes.json")
+
+	t.Run("missing file is a no-op", func(t *testing.T) {
+		if err := UnsetConfig("threshold"); err != nil {
+			t.Errorf("UnsetConfig() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("unset threshold falls back to default", func(t *testing.T) {
+		os.WriteFile(repoPath, []byte(`{"threshold": 200, "default_view_mode": "icicle"}`), 0644)
+		defer os.Remove(repoPath)
+
+		if err := UnsetConfig("threshold"); err != nil {
+			t.Fatalf("UnsetConfig() error = %v", err)
+		}
+		if got := LoadThreshold(); got != DefaultThreshold {
+			t.Errorf("LoadThreshold() = %d, want %d (default)", got, DefaultThreshold)
+		}
+		// Other fields preserved
+		if got := LoadViewMode(); got != "icicle" {
+			t.Errorf("LoadViewMode() = %q, want %q (preserved)", got, "icicle")
+		}
+	})
+
+	t.Run("unset show_diff_viz", func(t *testing.T) {
+		os.WriteFile(repoPath, []byte(`{"show_diff_viz": false}`), 0644)
+		defer os.Remove(repoPath)
+
+		if err := UnsetConfig("show_diff_viz"); err != nil {
+			t.Fatalf("UnsetConfig() error = %v", err)
+		}
+		if !LoadShowDiffViz() {
+			t.Error("LoadShowDiffViz() = false, want true (default)")
+		}
+	})
+
+	t.Run("unknown key errors", func(t *testing.T) {
+		if err := UnsetConfig("bogus"); err == nil {
+			t.Error("UnsetConfig(bogus) error = nil, want error")
+		}
+	})
+}
+
+func TestSaveConfigConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	threshold, width := 300, "--width 80"
+	yes, no := true, false
+	updates := []Config{
+		{Threshold: &threshold},
+		{DefaultViewMode: "icicle"},
+		{DefaultViewOpts: width},
+		{ShowDiffViz: &no},
+		{ShowSessionAge: &yes},
+		{ScoreScope: ScoreScopeStaged},
+		{DisableScatter: &yes},
+		{Exclude: []string{"vendor/"}},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(updates))
+	for _, u := range updates {
+		wg.Add(1)
+		go func(u Config) {
+			defer wg.Done()
+			errs <- SaveConfig(u)
+		}(u)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("SaveConfig() error = %v", err)
+		}
+	}
+
+	cfg, err := loadConfigFile(filepath.Join(tmpDir, ".bumper-lanes.json"))
+	if err != nil {
+		t.Fatalf("loadConfigFile() error = %v (file corrupted?)", err)
+	}
+	if cfg.Threshold == nil || *cfg.Threshold != 300 ||
+		cfg.DefaultViewMode != "icicle" ||
+		cfg.DefaultViewOpts != width ||
+		cfg.ShowDiffViz == nil || *cfg.ShowDiffViz ||
+		cfg.ShowSessionAge == nil || !*cfg.ShowSessionAge ||
+		cfg.ScoreScope != ScoreScopeStaged ||
+		cfg.DisableScatter == nil || !*cfg.DisableScatter ||
+		len(cfg.Exclude) != 1 {
+		data, _ := os.ReadFile(filepath.Join(tmpDir, ".bumper-lanes.json"))
+		t.Errorf("lost update, config = %s", data)
+	}
+
+	// Lock released and no temp files left behind
+	gitDir, _ := GetGitDir()
+	if _, err := os.Stat(filepath.Join(gitDir, "bumper-lanes-config.lock")); !os.IsNotExist(err) {
+		t.Errorf("config lock still held after writes")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(tmpDir, ".bumper-lanes-*.tmp")); len(matches) > 0 {
+		t.Errorf("temp files left behind: %v", matches)
+	}
+}
+
+func TestLockConfigStale(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	gitDir, _ := GetGitDir()
+	lockPath := filepath.Join(gitDir, "bumper-lanes-config.lock")
+	os.WriteFile(lockPath, nil, 0644)
+	old := time.Now().Add(-2 * configLockStale)
+	os.Chtimes(lockPath, old, old)
+
+	unlock, err := lockConfig()
+	if err != nil {
+		t.Fatalf("lockConfig() error = %v, want stale lock reclaimed", err)
+	}
+	unlock()
+}
+
+func TestConfigKeys(t *testing.T) {
+	keys := ConfigKeys()
+	if len(keys) == 0 || keys[0] != "threshold" {
+		t.Fatalf("ConfigKeys() = %v, want threshold first", keys)
+	}
+	for _, key := range keys {
+		if _, ok := configFieldByKey(key); !ok {
+			t.Errorf("configFieldByKey(%q) not found", key)
+		}
+	}
+}
+
 func TestLoadConfigFile_Missing(t *testing.T) {
 	_, err := loadConfigFile("/nonexistent/path/config.json")
 	if err == nil {
@@ -346,6 +643,193 @@ func TestGlobalConfigLoading(t *testing.T) {
 	})
 }
 
+func TestLoadWithSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	xdgDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+	os.MkdirAll(filepath.Join(xdgDir, "bumper-lanes"), 0755)
+	globalPath := filepath.Join(xdgDir, "bumper-lanes", "config.json")
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// Resolve symlinks (macOS /var -> /private/var) to match git's toplevel
+	resolvedDir, _ := filepath.EvalSymlinks(tmpDir)
+	repoPath := filepath.Join(resolvedDir, ".bumper-lanes.json")
+
+	tests := []struct {
+		name          string
+		repo, global  string
+		wantThreshold string
+		wantViewMode  string
+	}{
+		{"defaults", "", "", "default", "default"},
+		{"global only", "", `{"threshold": 100, "default_view_mode": "icicle"}`,
+			"global config at " + globalPath, "global config at " + globalPath},
+		{"repo overrides global", `{"threshold": 200}`, `{"threshold": 100, "default_view_mode": "icicle"}`,
+			"repo config at " + repoPath, "global config at " + globalPath},
+		{"invalid repo view mode falls to default", `{"default_view_mode": "bogus"}`, `{"default_view_mode": "icicle"}`,
+			"default", "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(repoPath)
+			os.Remove(globalPath)
+			if tt.repo != "" {
+				os.WriteFile(repoPath, []byte(tt.repo), 0644)
+			}
+			if tt.global != "" {
+				os.WriteFile(globalPath, []byte(tt.global), 0644)
+			}
+
+			threshold, source := LoadThresholdWithSource()
+			if source.String() != tt.wantThreshold {
+				t.Errorf("threshold source = %q, want %q", source, tt.wantThreshold)
+			}
+			if threshold != LoadThreshold() {
+				t.Errorf("LoadThresholdWithSource() = %d, LoadThreshold() = %d; want equal", threshold, LoadThreshold())
+			}
+
+			mode, source := LoadViewModeWithSource()
+			if source.String() != tt.wantViewMode {
+				t.Errorf("view mode source = %q, want %q", source, tt.wantViewMode)
+			}
+			if mode != LoadViewMode() {
+				t.Errorf("LoadViewModeWithSource() = %q, LoadViewMode() = %q; want equal", mode, LoadViewMode())
+			}
+		})
+	}
+}
+
+func TestThresholdFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	resolvedDir, _ := filepath.EvalSymlinks(tmpDir)
+	budgetPath := filepath.Join(resolvedDir, "budget.txt")
+	os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 300, "threshold_file": "budget.txt"}`), 0644)
+
+	tests := []struct {
+		name       string
+		budget     string // "" = no file
+		want       int
+		wantSource string
+	}{
+		{"valid budget overrides threshold", "750\n", 750, "threshold file at " + budgetPath},
+		{"zero disables", "0", 0, "threshold file at " + budgetPath},
+		{"missing file falls back", "", 300, "repo config at " + filepath.Join(resolvedDir, ".bumper-lanes.json")},
+		{"non-integer falls back", "lots", 300, "repo config at " + filepath.Join(resolvedDir, ".bumper-lanes.json")},
+		{"out of range falls back", "5", 300, "repo config at " + filepath.Join(resolvedDir, ".bumper-lanes.json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(budgetPath)
+			if tt.budget != "" {
+				os.WriteFile(budgetPath, []byte(tt.budget), 0644)
+			}
+
+			threshold, source := LoadThresholdWithSource()
+			if threshold != tt.want {
+				t.Errorf("LoadThresholdWithSource() = %d, want %d", threshold, tt.want)
+			}
+			if source.String() != tt.wantSource {
+				t.Errorf("source = %q, want %q", source, tt.wantSource)
+			}
+		})
+	}
+
+	t.Run("re-read on every load", func(t *testing.T) {
+		os.WriteFile(budgetPath, []byte("400"), 0644)
+		if got := LoadThreshold(); got != 400 {
+			t.Fatalf("LoadThreshold() = %d, want 400", got)
+		}
+		os.WriteFile(budgetPath, []byte("900"), 0644)
+		if got := LoadThreshold(); got != 900 {
+			t.Errorf("LoadThreshold() after update = %d, want 900", got)
+		}
+	})
+}
+
+func TestConfigFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"yaml", ".bumper-lanes.yaml", "threshold: 350\ndefault_view_mode: icicle\nscatter_weights:\n  _test.go: 0.5\nexclude:\n  - vendor/**\n"},
+		{"yml", ".bumper-lanes.yml", "threshold: 350\ndefault_view_mode: icicle\nscatter_weights: {_test.go: 0.5}\nexclude: [vendor/**]\n"},
+		{"toml", ".bumper-lanes.toml", "threshold = 350\ndefault_view_mode = \"icicle\"\nexclude = [\"vendor/**\"]\n\n[scatter_weights]\n\"_test.go\" = 0.5\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(tt.file, []byte(tt.content), 0644)
+			defer os.Remove(tt.file)
+
+			if got := LoadThreshold(); got != 350 {
+				t.Errorf("LoadThreshold() = %d, want 350", got)
+			}
+			if got := LoadViewMode(); got != "icicle" {
+				t.Errorf("LoadViewMode() = %q, want icicle", got)
+			}
+			if got := LoadScatterWeights()["_test.go"]; got != 0.5 {
+				t.Errorf("scatter weight = %v, want 0.5", got)
+			}
+			if got := LoadExclude(); len(got) != 1 || got[0] != "vendor/**" {
+				t.Errorf("LoadExclude() = %v, want [vendor/**]", got)
+			}
+			if err := ValidateConfigFile(GetConfigPath()); err != nil {
+				t.Errorf("ValidateConfigFile() = %v", err)
+			}
+			if err := SaveRepoConfig(500); err == nil {
+				t.Error("SaveRepoConfig() = nil, want error instead of rewriting a non-JSON config")
+			}
+			if ConfigFormatWarning() != "" {
+				t.Errorf("ConfigFormatWarning() = %q with a single file", ConfigFormatWarning())
+			}
+		})
+	}
+
+	t.Run("json wins and warns when several exist", func(t *testing.T) {
+		os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 450}`), 0644)
+		os.WriteFile(".bumper-lanes.yaml", []byte("threshold: 350\n"), 0644)
+
+		if got := LoadThreshold(); got != 450 {
+			t.Errorf("LoadThreshold() = %d, want 450 from JSON", got)
+		}
+		warning := ConfigFormatWarning()
+		if !strings.Contains(warning, ".bumper-lanes.json, .bumper-lanes.yaml") {
+			t.Errorf("ConfigFormatWarning() = %q, want both files listed", warning)
+		}
+	})
+
+	t.Run("invalid yaml falls back to defaults", func(t *testing.T) {
+		os.Remove(".bumper-lanes.json")
+		os.WriteFile(".bumper-lanes.yaml", []byte("threshold: [unclosed\n"), 0644)
+		if got := LoadThreshold(); got != DefaultThreshold {
+			t.Errorf("LoadThreshold() = %d, want default %d", got, DefaultThreshold)
+		}
+	})
+}
+
 func TestGetGlobalConfigPath(t *testing.T) {
 	t.Run("uses XDG_CONFIG_HOME when set", func(t *testing.T) {
 		origXDG := os.Getenv("XDG_CONFIG_HOME")
@@ -384,3 +868,147 @@ func TestGetGlobalConfigPath(t *testing.T) {
 		}
 	})
 }
+
+// setupRelocatedRepo creates a repo whose git dir lives outside the working
+// tree (as with some jj/sapling layouts), so git rev-parse can't find it.
+// Returns the working tree root and the git dir.
+func setupRelocatedRepo(t *testing.T) (root, gitDir string) {
+	t.Helper()
+	base := t.TempDir()
+	root = filepath.Join(base, "work")
+	gitDir = filepath.Join(base, "store", "git")
+	os.MkdirAll(root, 0755)
+	os.MkdirAll(filepath.Dir(gitDir), 0755)
+	setupGitRepo(t, root)
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("a\n"), 0644)
+	if err := exec.Command("git", "-C", root, "add", "a.txt").Run(); err != nil {
+		t.Fatalf("git add failed: %v", err)
+	}
+	if err := os.Rename(filepath.Join(root, ".git"), gitDir); err != nil {
+		t.Fatal(err)
+	}
+	return root, gitDir
+}
+
+func TestRepoRootOverride(t *testing.T) {
+	root, gitDir := setupRelocatedRepo(t)
+	t.Cleanup(ResetRepoRootOverride)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.MkdirAll(filepath.Join(root, "sub"), 0755)
+	os.Chdir(filepath.Join(root, "sub"))
+
+	writeGlobal := func(t *testing.T, json string) {
+		t.Helper()
+		xdg := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", xdg)
+		os.MkdirAll(filepath.Join(xdg, "bumper-lanes"), 0755)
+		os.WriteFile(filepath.Join(xdg, "bumper-lanes", "config.json"), []byte(json), 0644)
+		ResetRepoRootOverride()
+	}
+
+	t.Run("unset falls back to rev-parse", func(t *testing.T) {
+		writeGlobal(t, `{}`)
+		if got, err := RepoRootOverride(); err != nil || got != nil {
+			t.Errorf("RepoRootOverride() = %v, %v, want nil, nil", got, err)
+		}
+		if _, err := GetGitDir(); err == nil {
+			t.Error("GetGitDir() succeeded without an override; test repo should be unresolvable")
+		}
+	})
+
+	t.Run("pins git dir and repo root", func(t *testing.T) {
+		writeGlobal(t, `{"repo_root": "`+root+`", "git_dir": "../store/git"}`)
+		if got, err := GetGitDir(); err != nil || got != gitDir {
+			t.Errorf("GetGitDir() = %q, %v, want %q", got, err, gitDir)
+		}
+		if got, err := getRepoRoot(); err != nil || got != root {
+			t.Errorf("getRepoRoot() = %q, %v, want %q", got, err, root)
+		}
+	})
+
+	t.Run("resolved once per process", func(t *testing.T) {
+		writeGlobal(t, `{"repo_root": "`+root+`", "git_dir": "`+gitDir+`"}`)
+		if got, err := RepoRootOverride(); err != nil || got == nil || got.Root != root {
+			t.Fatalf("RepoRootOverride() = %v, %v, want root %s", got, err, root)
+		}
+		os.WriteFile(filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "bumper-lanes", "config.json"), []byte(`{}`), 0644)
+		if got, err := RepoRootOverride(); err != nil || got == nil || got.Root != root {
+			t.Errorf("RepoRootOverride() after config change = %v, %v, want cached root %s", got, err, root)
+		}
+		ResetRepoRootOverride()
+		if got, err := RepoRootOverride(); err != nil || got != nil {
+			t.Errorf("RepoRootOverride() after reset = %v, %v, want nil, nil", got, err)
+		}
+	})
+
+	t.Run("repo config resolves under the pinned root", func(t *testing.T) {
+		writeGlobal(t, `{"repo_root": "`+root+`", "git_dir": "`+gitDir+`", "threshold": 300}`)
+		os.WriteFile(filepath.Join(root, ".bumper-lanes.json"), []byte(`{"threshold": 450}`), 0644)
+		defer os.Remove(filepath.Join(root, ".bumper-lanes.json"))
+
+		if got := LoadThreshold(); got != 450 {
+			t.Errorf("LoadThreshold() = %d, want 450 from the repo config", got)
+		}
+		if got := GetConfigPath(); got != filepath.Join(root, ".bumper-lanes.json") {
+			t.Errorf("GetConfigPath() = %q, want repo config under %s", got, root)
+		}
+	})
+
+	t.Run("repo_root in the repo config is ignored", func(t *testing.T) {
+		writeGlobal(t, `{}`)
+		os.WriteFile(filepath.Join(root, ".bumper-lanes.json"), []byte(`{"repo_root": "`+root+`"}`), 0644)
+		defer os.Remove(filepath.Join(root, ".bumper-lanes.json"))
+
+		if got, err := RepoRootOverride(); err != nil || got != nil {
+			t.Errorf("RepoRootOverride() = %v, %v, want nil, nil", got, err)
+		}
+	})
+
+	t.Run("outside repo_root is not overridden", func(t *testing.T) {
+		writeGlobal(t, `{"repo_root": "`+root+`", "git_dir": "`+gitDir+`"}`)
+		os.Chdir(t.TempDir())
+		defer os.Chdir(filepath.Join(root, "sub"))
+
+		if got, err := RepoRootOverride(); err != nil || got != nil {
+			t.Errorf("RepoRootOverride() = %v, %v, want nil, nil", got, err)
+		}
+	})
+
+	t.Run("git dir without an index is an error", func(t *testing.T) {
+		writeGlobal(t, `{"repo_root": "`+root+`"}`)
+		if _, err := GetGitDir(); err == nil || !strings.Contains(err.Error(), "not a directory") {
+			t.Errorf("GetGitDir() error = %v, want missing git_dir error", err)
+		}
+
+		empty := t.TempDir()
+		writeGlobal(t, `{"repo_root": "`+root+`", "git_dir": "`+empty+`"}`)
+		if _, err := getRepoRoot(); err == nil || !strings.Contains(err.Error(), "has no index") {
+			t.Errorf("getRepoRoot() error = %v, want no index error", err)
+		}
+	})
+}
+
+func TestScaleScore(t *testing.T) {
+	tests := []struct {
+		mode                 string
+		score, limit         int
+		wantScore, wantLimit int
+	}{
+		{ScoreDisplayRaw, 387, 400, 387, 400},
+		{ScoreDisplayRounded, 387, 400, 390, 400},
+		{ScoreDisplayRounded, 384, 400, 380, 400},
+		{ScoreDisplayPercent, 387, 400, 96, 100},
+		{ScoreDisplayPercent, 500, 400, 125, 100},
+		{ScoreDisplayPercent, 387, 0, 387, 0}, // Disabled threshold: nothing to scale to
+		{"", 387, 400, 387, 400},
+	}
+	for _, tt := range tests {
+		gotScore, gotLimit := ScaleScore(tt.score, tt.limit, tt.mode)
+		if gotScore != tt.wantScore || gotLimit != tt.wantLimit {
+			t.Errorf("ScaleScore(%d, %d, %q) = %d/%d, want %d/%d",
+				tt.score, tt.limit, tt.mode, gotScore, gotLimit, tt.wantScore, tt.wantLimit)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+import "math"
+
+// DisplayScore scales score and limit for display under the configured
+// score_display, e.g. 387/400 stays 387/400 (raw), becomes 390/400
+// (rounded), or 96/100 (percent). Enforcement always compares raw scores;
+// this is for status lines and messages only.
+func DisplayScore(score, limit int) (int, int) {
+	return ScaleScore(score, limit, LoadScoreDisplay())
+}
+
+// ScaleScore is DisplayScore for an explicit mode. Percent needs a limit,
+// so a disabled threshold (0) shows raw scores.
+func ScaleScore(score, limit int, mode string) (int, int) {
+	switch {
+	case mode == ScoreDisplayRounded:
+		return int(math.Round(float64(score)/10) * 10), limit
+	case mode == ScoreDisplayPercent && limit > 0:
+		return score * 100 / limit, 100
+	}
+	return score, limit
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// repoConfigNames are the accepted repo config file names, in preference order.
+// JSON is what bumper-lanes writes; YAML and TOML are read-only alternatives.
+var repoConfigNames = []string{
+	".bumper-lanes.json",
+	".bumper-lanes.yaml",
+	".bumper-lanes.yml",
+	".bumper-lanes.toml",
+}
+
+// repoConfigPath returns the repo config file in use: the first of
+// repoConfigNames that exists, or the JSON path if none do.
+func repoConfigPath(repoRoot string) string {
+	for _, name := range repoConfigNames {
+		path := filepath.Join(repoRoot, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(repoRoot, repoConfigNames[0])
+}
+
+// isJSONConfig reports whether path is a JSON config, the only format bumper-lanes writes.
+func isJSONConfig(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// decodeConfig parses config data in the format given by the path's extension.
+// YAML and TOML are decoded generically and re-encoded as JSON, so every
+// format shares the Config JSON keys and types.
+func decodeConfig(path string, data []byte) (*Config, error) {
+	var cfg Config
+	var generic map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return nil, err
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &generic); err != nil {
+			return nil, err
+		}
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+		return &cfg, nil
+	}
+
+	converted, err := json.Marshal(generic)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(converted, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ConfigFormatWarning returns a warning if more than one repo config file
+// exists. Only the first in preference order (JSON first) is read.
+// Returns empty string if at most one exists or outside a repo.
+func ConfigFormatWarning() string {
+	repoRoot, err := getRepoRoot()
+	if err != nil {
+		return ""
+	}
+	var found []string
+	for _, name := range repoConfigNames {
+		if _, err := os.Stat(filepath.Join(repoRoot, name)); err == nil {
+			found = append(found, name)
+		}
+	}
+	if len(found) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("multiple repo config files (%s); only %s is read. Remove the others",
+		strings.Join(found, ", "), found[0])
+}
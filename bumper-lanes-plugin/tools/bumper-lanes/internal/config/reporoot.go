@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RepoRoot is a repository location pinned by the repo_root override.
+type RepoRoot struct {
+	Root   string // Working tree root
+	GitDir string // Git directory holding the index and objects
+}
+
+// RepoRootOverride returns the repo root and git dir pinned by the global
+// config's repo_root and git_dir, for layouts git rev-parse resolves badly
+// (e.g. Jujutsu or Sapling checkouts backed by git). Returns nil when
+// repo_root is unset or the working directory is outside it. repo_root is
+// only read from the global config: the repo config is found via the root.
+// An override that fails validation returns an error rather than falling
+// back to rev-parse, which would pick a different repository.
+//
+// The result is resolved once per process and reused: every hook reaches
+// this through GetGitDir and the state paths, and ExportRepoRoot has
+// already pinned git to the first answer.
+func RepoRootOverride() (*RepoRoot, error) {
+	repoRootOnce.Do(func() {
+		repoRoot, repoRootErr = resolveRepoRootOverride()
+	})
+	return repoRoot, repoRootErr
+}
+
+var (
+	repoRootOnce sync.Once
+	repoRoot     *RepoRoot
+	repoRootErr  error
+)
+
+// ResetRepoRootOverride drops the cached override so the next
+// RepoRootOverride call re-reads the global config. For tests that
+// change the config or working directory.
+func ResetRepoRootOverride() {
+	repoRootOnce = sync.Once{}
+	repoRoot, repoRootErr = nil, nil
+}
+
+// resolveRepoRootOverride reads and validates the override for RepoRootOverride.
+func resolveRepoRootOverride() (*RepoRoot, error) {
+	globalPath := getGlobalConfigPath()
+	if globalPath == "" {
+		return nil, nil
+	}
+	global, err := loadConfigFile(globalPath)
+	if err != nil || global.RepoRoot == "" {
+		return nil, nil
+	}
+	root := RepoRoot{Root: global.RepoRoot, GitDir: resolveGitDir(global.RepoRoot, global.GitDir)}
+	if err := validateRepoRoot(root); err != nil {
+		return nil, err
+	}
+	if !withinDir(root.Root) {
+		return nil, nil
+	}
+	return &root, nil
+}
+
+// ExportRepoRoot sets GIT_DIR and GIT_WORK_TREE to the repo_root override,
+// so the git commands bumper-lanes runs find the pinned repository too.
+// An existing GIT_DIR wins. No-op without a valid override.
+func ExportRepoRoot() {
+	if os.Getenv("GIT_DIR") != "" {
+		return
+	}
+	root, err := RepoRootOverride()
+	if err != nil || root == nil {
+		return
+	}
+	os.Setenv("GIT_DIR", root.GitDir)
+	os.Setenv("GIT_WORK_TREE", root.Root)
+}
+
+// resolveGitDir returns gitDir, defaulting to <root>/.git and resolving a
+// relative path against root.
+func resolveGitDir(root, gitDir string) string {
+	if gitDir == "" {
+		return filepath.Join(root, ".git")
+	}
+	if !filepath.IsAbs(gitDir) {
+		return filepath.Join(root, gitDir)
+	}
+	return gitDir
+}
+
+// validateRepoRoot checks that the override names an absolute root
+// directory and a git dir that has an index.
+func validateRepoRoot(r RepoRoot) error {
+	if !filepath.IsAbs(r.Root) {
+		return fmt.Errorf("repo_root must be an absolute path, got %q", r.Root)
+	}
+	if info, err := os.Stat(r.Root); err != nil || !info.IsDir() {
+		return fmt.Errorf("repo_root %s is not a directory", r.Root)
+	}
+	if info, err := os.Stat(r.GitDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("git_dir %s is not a directory", r.GitDir)
+	}
+	if _, err := os.Stat(filepath.Join(r.GitDir, "index")); err != nil {
+		return fmt.Errorf("git_dir %s has no index", r.GitDir)
+	}
+	return nil
+}
+
+// withinDir reports whether the working directory is dir or below it.
+// Symlinks are resolved on both sides (e.g. macOS /tmp -> /private/tmp).
+func withinDir(dir string) bool {
+	wd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(wd); err == nil {
+		wd = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(dir, wd)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,18 @@
+package config
+
+import "github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
+
+// LoadScoringOptions builds the scoring options that come from config alone:
+// weights, scatter settings, and multipliers. Per-diff data (comment, hunk,
+// and head-of-file counts) needs git and is left nil; callers with a real
+// diff fill it in (see hooks.loadScoringOptions).
+func LoadScoringOptions() scoring.Options {
+	return scoring.Options{
+		DeletionWeight:  LoadDeletionWeight(),
+		DisableScatter:  LoadDisableScatter(),
+		ScatterWeights:  LoadScatterWeights(),
+		WeightedScatter: LoadScatterMode() == ScatterModeWeighted,
+		HunkWeight:      LoadHunkWeight(),
+		HeadLinesWeight: LoadHeadLinesWeight(),
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Config lock tuning. The lock only guards a read-modify-write of a small
+// JSON file, so anything held longer than configLockStale is a crashed writer.
+const (
+	configLockTimeout = 2 * time.Second
+	configLockStale   = 10 * time.Second
+	configLockPoll    = 10 * time.Millisecond
+)
+
+// ErrConfigLocked is returned when the config lock can't be acquired in time.
+var ErrConfigLocked = errors.New("config is locked by another writer")
+
+// updateRepoConfig applies mutate to .bumper-lanes.json under the config lock
+// and writes the result atomically. A missing or unreadable file starts from
+// an empty Config. YAML and TOML repo configs are never rewritten.
+func updateRepoConfig(mutate func(cfg *Config)) error {
+	repoRoot, err := getRepoRoot()
+	if err != nil {
+		return err
+	}
+	path := repoConfigPath(repoRoot)
+	if !isJSONConfig(path) {
+		return fmt.Errorf("%s is read-only for bumper-lanes; edit it directly", filepath.Base(path))
+	}
+
+	unlock, err := lockConfig()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Load existing config to preserve other fields
+	existing, _ := loadConfigFile(path)
+	if existing == nil {
+		existing = &Config{}
+	}
+
+	mutate(existing)
+	return writeConfigFile(path, existing)
+}
+
+// lockConfig takes an exclusive lock file in the git dir, so two sessions
+// editing the same repo config serialize. Returns the release func.
+// Lock files older than configLockStale are assumed abandoned and removed.
+func lockConfig() (func(), error) {
+	gitDir, err := GetGitDir()
+	if err != nil {
+		return nil, err
+	}
+	lockPath := filepath.Join(gitDir, "bumper-lanes-config.lock")
+
+	deadline := time.Now().Add(configLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("creating config lock: %w", err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > configLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrConfigLocked
+		}
+		time.Sleep(configLockPoll)
+	}
+}
+
+// writeConfigFile writes cfg as indented JSON via temp file + rename,
+// so readers never see a partially written config.
+func writeConfigFile(path string, cfg *Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".bumper-lanes-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tempPath := tempFile.Name()
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	// CreateTemp uses 0600; config files are shared like any repo file
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("renaming temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+)
+
+// Check exit codes, following the diff/grep convention.
+const (
+	CheckUnder = 0 // Score within threshold (or threshold disabled)
+	CheckOver  = 1 // Score over threshold
+	CheckError = 2 // Score couldn't be computed
+)
+
+// Check scores HEAD against the index (or the working tree when working is
+// true) and compares it to the configured threshold. Standalone: no session
+// is read or written, so it works as a git pre-commit hook or in CI.
+// Prints a one-line summary to w unless quiet; errors are always reported.
+func Check(w io.Writer, working, quiet bool) int {
+	scope := "staged"
+	if working {
+		scope = "working"
+	}
+
+	score, err := checkScore(working, config.LoadCountUntracked())
+	if err != nil {
+		fmt.Fprintf(w, "bumper-lanes check: %v\n", err)
+		return CheckError
+	}
+
+	threshold := config.LoadThreshold()
+	if config.IsDisabled(threshold) {
+		if !quiet {
+			shown, _ := config.DisplayScore(score, threshold)
+			fmt.Fprintf(w, "✓ bumper-lanes: %d pts (%s), threshold disabled\n", shown, scope)
+		}
+		return CheckUnder
+	}
+
+	pct := (score * 100) / threshold
+	if score > threshold {
+		if !quiet {
+			fmt.Fprintf(w, "✗ bumper-lanes: %s pts (%d%%, %s) - over threshold. Split the change or raise the threshold.\n",
+				displayScore(score, threshold), pct, scope)
+		}
+		return CheckOver
+	}
+
+	if !quiet {
+		fmt.Fprintf(w, "✓ bumper-lanes: %s pts (%d%%, %s)\n", displayScore(score, threshold), pct, scope)
+	}
+	return CheckUnder
+}
+
+// checkScore scores HEAD vs the index or working tree, counting untracked
+// files in the working tree when untracked is true.
+// An unborn HEAD (first commit) is scored against the empty tree.
+func checkScore(working, untracked bool) (int, error) {
+	fromTree := GetHeadTree()
+	if fromTree == "" {
+		empty, err := emptyTree()
+		if err != nil {
+			return 0, err
+		}
+		fromTree = empty
+	}
+
+	var toTree string
+	var err error
+	if working {
+		toTree, err = captureTree(untracked) // Same capture calculateScore uses
+	} else {
+		toTree, err = getIndexTree()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("capturing tree: %w", err)
+	}
+
+	score, err := scoreTrees(fromTree, toTree)
+	if err != nil {
+		return 0, fmt.Errorf("diffing trees: %w", err)
+	}
+	return score.Score, nil
+}
+
+// emptyTree returns the empty tree SHA for the repo's hash algorithm.
+func emptyTree() (string, error) {
+	output, err := exec.Command("git", "hash-object", "-t", "tree", "/dev/null").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,89 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // No global config
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// Config stays untracked, so it doesn't count toward the staged score
+	os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 50}`), 0644)
+
+	// 40 staged lines, 30 unstaged
+	os.WriteFile("staged.go", []byte(strings.Repeat("x\n", 40)), 0644)
+	exec.Command("git", "add", "staged.go").Run()
+	os.WriteFile("unstaged.go", []byte(strings.Repeat("y\n", 30)), 0644)
+
+	tests := []struct {
+		name     string
+		working  bool
+		quiet    bool
+		wantExit int
+		wantOut  string
+	}{
+		{"staged under threshold", false, false, CheckUnder, "40/50 pts"},
+		{"working over threshold", true, false, CheckOver, "over threshold"},
+		{"quiet prints nothing", true, true, CheckOver, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if got := Check(&buf, tt.working, tt.quiet); got != tt.wantExit {
+				t.Errorf("Check() = %d, want %d (output: %q)", got, tt.wantExit, buf.String())
+			}
+			if tt.wantOut == "" && buf.Len() != 0 {
+				t.Errorf("output = %q, want empty", buf.String())
+			}
+			if !strings.Contains(buf.String(), tt.wantOut) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), tt.wantOut)
+			}
+		})
+	}
+
+	t.Run("disabled threshold always passes", func(t *testing.T) {
+		os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 0}`), 0644)
+		defer os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 50}`), 0644)
+
+		var buf bytes.Buffer
+		if got := Check(&buf, true, false); got != CheckUnder {
+			t.Errorf("Check() = %d, want %d", got, CheckUnder)
+		}
+	})
+}
+
+func TestCheckUnbornHead(t *testing.T) {
+	tmpDir := t.TempDir()
+	exec.Command("git", "init", "-q", tmpDir).Run()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile("first.go", []byte(strings.Repeat("x\n", 10)), 0644)
+	exec.Command("git", "add", "first.go").Run()
+
+	var buf bytes.Buffer
+	if got := Check(&buf, false, false); got != CheckUnder {
+		t.Errorf("Check() = %d, want %d (output: %q)", got, CheckUnder, buf.String())
+	}
+	if !strings.Contains(buf.String(), "10/600 pts") {
+		t.Errorf("output = %q, want 10/600 pts scored against the empty tree", buf.String())
+	}
+}
@@ -2,14 +2,20 @@
 package hooks
 
 import (
-	"bufio"
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"slices"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
 // HookInput represents the JSON input from Claude Code hooks.
@@ -59,15 +65,132 @@ func ReadInput() (*HookInput, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading stdin: %w", err)
 	}
+	return parseInput(data)
+}
 
+// parseInput parses hook JSON input and logs diagnostics for it, so payload
+// shape changes in Claude Code show up in the session log instead of failing
+// open silently. See logInputSchema.
+func parseInput(data []byte) (*HookInput, error) {
 	var input HookInput
 	if err := json.Unmarshal(data, &input); err != nil {
+		logging.New("", "input").Warn("unparseable hook input: %v (raw: %s)", err, truncateForLog(data))
 		return nil, fmt.Errorf("parsing input: %w", err)
 	}
-
+	logInputSchema(data, &input)
 	return &input, nil
 }
 
+// requiredInputFields are present in every hook payload bumper-lanes handles.
+var requiredInputFields = []string{"session_id", "hook_event_name"}
+
+// expectedInputFields lists the event-specific fields each hook reads.
+var expectedInputFields = map[string][]string{
+	"PreToolUse":       {"tool_name", "tool_input"},
+	"PostToolUse":      {"tool_name", "tool_input"},
+	"Stop":             {"stop_hook_active"},
+	"UserPromptSubmit": {"prompt"},
+}
+
+// maxLoggedInput caps how much raw input a log line carries.
+const maxLoggedInput = 4096
+
+// logInputSchema warns when required fields are missing and, with
+// BUMPER_LANES_DEBUG=1, logs the raw input plus which fields the event's
+// handler reads were populated or missing, and any fields it ignores.
+func logInputSchema(data []byte, input *HookInput) {
+	log := logging.New(input.SessionID, "input")
+
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(data, &raw) != nil {
+		log.Warn("hook input is not a JSON object: %s", truncateForLog(data))
+		return
+	}
+
+	var missingRequired []string
+	for _, field := range requiredInputFields {
+		if !inputFieldSet(raw, field) {
+			missingRequired = append(missingRequired, field)
+		}
+	}
+	if len(missingRequired) > 0 {
+		log.Warn("hook input missing required fields: %s", strings.Join(missingRequired, ", "))
+	}
+
+	if !logging.DebugEnabled() {
+		return
+	}
+	expected := append(append([]string{}, requiredInputFields...), expectedInputFields[input.HookEventName]...)
+	known := make(map[string]bool, len(expected))
+	var populated, missing, other []string
+	for _, field := range expected {
+		known[field] = true
+		if inputFieldSet(raw, field) {
+			populated = append(populated, field)
+		} else {
+			missing = append(missing, field)
+		}
+	}
+	for field := range raw {
+		if !known[field] {
+			other = append(other, field)
+		}
+	}
+	sort.Strings(other)
+	log.Debug("hook input event=%s populated=[%s] missing=[%s] other=[%s] raw=%s",
+		input.HookEventName, strings.Join(populated, " "), strings.Join(missing, " "), strings.Join(other, " "), truncateForLog(data))
+}
+
+// inputFieldSet reports whether field is present and not null.
+func inputFieldSet(raw map[string]json.RawMessage, field string) bool {
+	v, ok := raw[field]
+	return ok && string(v) != "null"
+}
+
+// truncateForLog trims raw input to maxLoggedInput bytes for a log line.
+func truncateForLog(data []byte) string {
+	s := strings.TrimSpace(string(data))
+	if len(s) > maxLoggedInput {
+		return s[:maxLoggedInput] + "...(truncated)"
+	}
+	return s
+}
+
+// resolveSessionID returns the input's session ID. Some hook payloads omit
+// it; then the single session active within inheritMaxAge is used, so state
+// left behind by ended sessions doesn't count. Returns "" when there are no
+// such sessions or several, since picking one would guess.
+func resolveSessionID(input *HookInput) string {
+	if input.SessionID != "" {
+		return input.SessionID
+	}
+	sessions, err := state.LoadRecent(inheritMaxAge, time.Now())
+	if err != nil || len(sessions) != 1 || sessions[0].SessionID == "" {
+		return ""
+	}
+	logging.New(sessions[0].SessionID, "input").Info("hook input has no session_id, using the only active session")
+	return sessions[0].SessionID
+}
+
+// refreshThreshold re-reads a configured threshold_file into the session's
+// ThresholdLimit, which SessionStart otherwise freezes, so an external
+// budget update takes effect without restarting. Sessions keep their limit
+// when no threshold file is configured or it can't be read.
+func refreshThreshold(sess *state.SessionState) {
+	if threshold, source := config.LoadThresholdWithSource(); source.Layer == config.SourceThresholdFile {
+		sess.ThresholdLimit = threshold
+	}
+}
+
+// displayScore formats score/limit under the configured score_display.
+// Every score shown against a limit in hook and CLI messages goes through
+// it (or config.DisplayScore, for single numbers on the same scale). The
+// Stop breakdown's component points and replay's log audit stay raw.
+func displayScore(score, limit int) string {
+	shownScore, shownLimit := config.DisplayScore(score, limit)
+	return fmt.Sprintf("%d/%d", shownScore, shownLimit)
+}
+
 // WriteResponse writes JSON response to stdout.
 func WriteResponse(resp interface{}) error {
 	data, err := json.Marshal(resp)
@@ -79,14 +202,32 @@ func WriteResponse(resp interface{}) error {
 }
 
 // IsGitRepo checks if current directory is in a git repository.
+// A valid repo_root override counts without asking git.
 func IsGitRepo() bool {
+	if root, err := config.RepoRootOverride(); err != nil {
+		return false
+	} else if root != nil {
+		return true
+	}
 	cmd := exec.Command("git", "rev-parse", "--git-dir")
 	return cmd.Run() == nil
 }
 
 // CaptureTree captures the current working tree as a git tree SHA.
 // Uses a temporary index to avoid modifying the real staging area.
+// Untracked files are included unless count_untracked is false.
 func CaptureTree() (string, error) {
+	return captureTree(config.LoadCountUntracked())
+}
+
+// captureTree is CaptureTree with untracked-file inclusion chosen by the caller.
+func captureTree(includeUntracked bool) (string, error) {
+	return captureTreeIn("", includeUntracked)
+}
+
+// captureTreeIn is captureTree for the repository at dir ("" = current
+// directory), e.g. a submodule checkout.
+func captureTreeIn(dir string, includeUntracked bool) (string, error) {
 	// Create temp index file
 	tmpIndex, err := os.CreateTemp("", "git-index-*")
 	if err != nil {
@@ -98,38 +239,29 @@ func CaptureTree() (string, error) {
 
 	// Helper to run git commands with GIT_INDEX_FILE set
 	gitWithTempIndex := func(args ...string) *exec.Cmd {
-		cmd := exec.Command("git", args...)
-		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+tmpIndexPath)
+		cmd := gitCommand(dir, args...)
+		cmd.Env = append(cmd.Env, "GIT_INDEX_FILE="+tmpIndexPath)
 		return cmd
 	}
 
 	// Initialize temp index with HEAD tree (or empty if no commits)
-	headRef, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	headRef, err := gitCommand(dir, "rev-parse", "HEAD").Output()
 	if err == nil && len(headRef) > 0 {
 		gitWithTempIndex("read-tree", strings.TrimSpace(string(headRef))).Run()
 	} else {
 		gitWithTempIndex("read-tree", "--empty").Run()
 	}
 
-	// Add tracked file changes (staged and unstaged)
-	gitWithTempIndex("add", "-u", ".").Run()
-
-	// Add untracked files (respecting .gitignore)
-	lsCmd := exec.Command("git", "ls-files", "--others", "--exclude-standard")
-	untrackedOutput, _ := lsCmd.Output()
-	if len(untrackedOutput) > 0 {
-		scanner := bufio.NewScanner(bytes.NewReader(untrackedOutput))
-		for scanner.Scan() {
-			path := scanner.Text()
-			if path != "" {
-				gitWithTempIndex("add", path).Run()
-			}
-		}
+	// Add tracked file changes (staged and unstaged), plus untracked files
+	// (respecting .gitignore) in one command when they count
+	if includeUntracked {
+		gitWithTempIndex("add", "-A", ".").Run()
+	} else {
+		gitWithTempIndex("add", "-u", ".").Run()
 	}
 
 	// Write tree from temp index
-	writeCmd := gitWithTempIndex("write-tree")
-	output, err := writeCmd.Output()
+	output, err := writeTreeWithRetry(dir, tmpIndexPath)
 	if err != nil {
 		return "", err
 	}
@@ -142,6 +274,68 @@ func CaptureTree() (string, error) {
 	return treeSHA, nil
 }
 
+// writeTreeAttempts bounds write-tree tries when git reports a lock held by
+// another process; writeTreeBackoff is the first delay, doubled each retry.
+const (
+	writeTreeAttempts = 4
+	writeTreeBackoff  = 25 * time.Millisecond
+)
+
+// writeTree runs git write-tree in dir against indexPath. A variable so
+// tests can inject failures.
+var writeTree = func(dir, indexPath string) ([]byte, error) {
+	cmd := gitCommand(dir, "write-tree")
+	cmd.Env = append(cmd.Env, "GIT_INDEX_FILE="+indexPath)
+	return cmd.Output()
+}
+
+// gitCommand builds a git command for the repository at dir ("" = current
+// directory). For another repository, such as a submodule checkout, the
+// GIT_DIR and GIT_WORK_TREE exported for a repo_root override are dropped
+// so git resolves dir itself.
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Env = slices.DeleteFunc(cmd.Env, func(kv string) bool {
+			return strings.HasPrefix(kv, "GIT_DIR=") || strings.HasPrefix(kv, "GIT_WORK_TREE=")
+		})
+	}
+	return cmd
+}
+
+// writeTreeWithRetry runs writeTree, retrying with backoff while the failure
+// is a transient lock held by the user's own git operations. Other errors
+// return immediately. CaptureTree has no session, so retries log to the
+// "unknown" session log.
+func writeTreeWithRetry(dir, indexPath string) ([]byte, error) {
+	delay := writeTreeBackoff
+	for attempt := 1; ; attempt++ {
+		output, err := writeTree(dir, indexPath)
+		if err == nil || attempt == writeTreeAttempts || !isGitLockError(err) {
+			return output, err
+		}
+		logging.New("", "capture_tree").Warn("write-tree hit a git lock (attempt %d/%d), retrying in %s: %v",
+			attempt, writeTreeAttempts, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// isGitLockError reports whether a git failure is lock contention
+// ("Unable to create '.../index.lock': File exists", "unable to create
+// temporary file"), which clears once the other git process finishes.
+func isGitLockError(err error) bool {
+	msg := err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		msg += " " + string(exitErr.Stderr)
+	}
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, ".lock") || strings.Contains(msg, "unable to create")
+}
+
 // GetCurrentBranch returns the current branch name, or empty string if detached.
 func GetCurrentBranch() string {
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
@@ -156,6 +350,17 @@ func GetCurrentBranch() string {
 	return branch
 }
 
+// GetHeadCommit returns the commit SHA of HEAD.
+// Returns empty string if HEAD doesn't exist (empty repo) or on error.
+func GetHeadCommit() string {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // GetHeadTree returns the tree SHA of HEAD.
 // Returns empty string if HEAD doesn't exist (empty repo) or on error.
 func GetHeadTree() string {
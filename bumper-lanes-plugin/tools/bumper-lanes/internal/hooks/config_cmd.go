@@ -10,15 +10,15 @@ import (
 
 // ConfigShow displays the current threshold configuration.
 func ConfigShow() error {
-	threshold := config.LoadThreshold()
-	viewMode := config.LoadViewMode()
+	threshold, thresholdSource := config.LoadThresholdWithSource()
+	viewMode, viewModeSource := config.LoadViewModeWithSource()
 
 	fmt.Printf("Threshold: %d points", threshold)
 	if config.IsDisabled(threshold) {
 		fmt.Print(" (disabled)")
 	}
-	fmt.Println()
-	fmt.Printf("Default view mode: %s\n", viewMode)
+	fmt.Printf(" [%s]\n", thresholdSource)
+	fmt.Printf("Default view mode: %s [%s]\n", viewMode, viewModeSource)
 
 	// Show source with helpful paths
 	repoPath := config.GetConfigPath()
@@ -42,6 +42,13 @@ func ConfigShow() error {
 		fmt.Printf("Global: %s (create for viz-only mode)\n", globalPath)
 	}
 
+	if warning := config.GitignoreWarning(); warning != "" {
+		fmt.Printf("\n⚠ %s\n", warning)
+	}
+	if warning := config.ConfigFormatWarning(); warning != "" {
+		fmt.Printf("\n⚠ %s\n", warning)
+	}
+
 	return nil
 }
 
@@ -68,3 +75,13 @@ func ConfigSet(value string) error {
 	fmt.Printf("Threshold set to %d (saved to .bumper-lanes.json)\n", threshold)
 	return nil
 }
+
+// ConfigUnset removes a key from .bumper-lanes.json so it falls back to defaults.
+func ConfigUnset(key string) error {
+	if err := config.UnsetConfig(key); err != nil {
+		return fmt.Errorf("failed to unset %s: %w", key, err)
+	}
+
+	fmt.Printf("Unset %s (removed from .bumper-lanes.json)\n", key)
+	return nil
+}
@@ -0,0 +1,85 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/statusline"
+)
+
+// Diff prints the current diff visualization using the session's view mode.
+// Falls back to the config default mode when no session exists.
+// The caller resolves --color against the terminal (see cmdDiff).
+// noUntracked hides untracked files even when count_untracked is on.
+func Diff(sessionID string, useColor, noUntracked bool) error {
+	fmt.Println(renderSessionDiff(sessionID, useColor, !noUntracked && config.LoadCountUntracked()))
+	return nil
+}
+
+// DiffCheck is Diff followed by a trip verdict against threshold, for
+// standalone checks: "OK (120/400 pts)" or "OVER by 30 (430/400 pts)". The
+// score is HEAD vs the working tree, the same diff that is rendered, with
+// untracked files counted only when they are shown. A session only picks
+// the view mode: the verdict never uses its baseline, so it can differ from
+// the session's own score when the baseline isn't HEAD.
+// Returns the Check exit codes: CheckOver when over, CheckError when the
+// diff can't be scored.
+func DiffCheck(w io.Writer, sessionID string, useColor, noUntracked bool, threshold int) int {
+	untracked := !noUntracked && config.LoadCountUntracked()
+	fmt.Fprintln(w, renderSessionDiff(sessionID, useColor, untracked))
+
+	score, err := checkScore(true, untracked)
+	if err != nil {
+		fmt.Fprintf(w, "bumper-lanes diff: %v\n", err)
+		return CheckError
+	}
+	line, over := formatVerdict(score, threshold)
+	fmt.Fprintln(w, line)
+	if over {
+		return CheckOver
+	}
+	return CheckUnder
+}
+
+// formatVerdict renders a score against threshold and reports whether it's
+// over. The verdict compares raw points; the numbers shown follow
+// score_display.
+func formatVerdict(score, threshold int) (string, bool) {
+	if score > threshold {
+		shownScore, shownLimit := config.DisplayScore(score, threshold)
+		return fmt.Sprintf("OVER by %d (%s pts)", shownScore-shownLimit, displayScore(score, threshold)), true
+	}
+	return fmt.Sprintf("OK (%s pts)", displayScore(score, threshold)), false
+}
+
+// renderSessionDiff renders the diff at the session's view mode, opts, and
+// threshold limit, falling back to config without a session. Score-aware
+// modes show the session's fresh score from the same scorer the Stop hook
+// uses; without a session, the HEAD vs working tree score check reports.
+func renderSessionDiff(sessionID string, useColor, untracked bool) string {
+	viewMode, viewOpts := "", ""
+	var score, limit int
+	if sess, err := state.Load(sessionID); err == nil {
+		viewMode = sess.GetViewMode()
+		viewOpts = sess.GetViewOpts()
+		limit = sess.ThresholdLimit
+		score = sess.Score
+		if result := calculateSessionScore(sess); result != nil {
+			score = result.Score
+		}
+	} else {
+		limit = config.LoadThreshold()
+		score, _ = checkScore(true, untracked)
+	}
+	if viewMode == "" {
+		viewMode = config.LoadViewMode()
+	}
+
+	tree := statusline.RenderDiffTree(viewMode, viewOpts, score, limit, useColor, untracked)
+	if tree == "" {
+		return "No changes"
+	}
+	return tree
+}
@@ -0,0 +1,134 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestRenderSessionDiff(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.MkdirAll("src", 0755)
+	os.WriteFile("src/app.go", []byte("one\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "add app").Run()
+
+	sessionID := "test-diff"
+	sess, _ := state.New(sessionID, GetHeadTree(), "main", 400)
+	sess.SetViewMode("tree")
+	sess.Save()
+
+	t.Run("no changes", func(t *testing.T) {
+		if got := renderSessionDiff(sessionID, false, true); got != "No changes" {
+			t.Errorf("renderSessionDiff() = %q, want %q", got, "No changes")
+		}
+	})
+
+	t.Run("tree mode renders changed file", func(t *testing.T) {
+		os.WriteFile("src/app.go", []byte("one\ntwo\nthree\n"), 0644)
+
+		got := renderSessionDiff(sessionID, false, true)
+		if !strings.Contains(got, "src/") || !strings.Contains(got, "app.go") {
+			t.Errorf("expected src/ and app.go in tree output, got:\n%s", got)
+		}
+		if !strings.Contains(got, "+2") {
+			t.Errorf("expected +2 in tree output, got:\n%s", got)
+		}
+		if strings.Contains(got, "\033[") {
+			t.Errorf("expected no ANSI codes with useColor=false, got:\n%q", got)
+		}
+	})
+
+	t.Run("oneline mode prints one uncolored line", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		// The session's limit wins over the configured threshold
+		os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 500, "exclude": [".bumper-lanes.json"]}`), 0644)
+		defer os.Remove(".bumper-lanes.json")
+		os.WriteFile("src/big.go", []byte(strings.Repeat("x\n", 40)), 0644)
+		defer os.Remove("src/big.go")
+		sess.SetViewMode("oneline")
+		sess.ThresholdLimit = 20
+		sess.Save()
+
+		got := renderSessionDiff(sessionID, false, true)
+		if strings.Contains(got, "\n") || strings.Contains(got, "\033[") {
+			t.Fatalf("expected a single uncolored line, got:\n%q", got)
+		}
+		if !strings.HasPrefix(got, "SCORE ") || !strings.Contains(got, "/20 ") || !strings.Contains(got, "TRIPPED") {
+			t.Errorf("expected tripped score against threshold 20, got %q", got)
+		}
+		if !strings.HasSuffix(got, "top: src/big.go(40)") {
+			t.Errorf("expected src/big.go as hotspot, got %q", got)
+		}
+
+		// The score is the one Stop enforces, carry-over included, not a
+		// re-score of the rendered 40 lines
+		sess.ThresholdLimit = 200
+		sess.Carryover = 100
+		sess.Save()
+		if got := renderSessionDiff(sessionID, false, true); !strings.HasPrefix(got, "SCORE 142/200 (71%) OK") {
+			t.Errorf("expected the enforced score 142 (42 from the diff + 100 carried), got %q", got)
+		}
+	})
+}
+
+func TestDiffCheck(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// 30 new lines = 30 pts
+	os.WriteFile("new.go", []byte(strings.Repeat("x\n", 30)), 0644)
+
+	tests := []struct {
+		name      string
+		threshold int
+		wantCode  int
+		wantLine  string
+	}{
+		{"under threshold", 50, CheckUnder, "OK (30/50 pts)"},
+		{"over threshold", 20, CheckOver, "OVER by 10 (30/20 pts)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			code := DiffCheck(&buf, "", false, false, tt.threshold)
+			if code != tt.wantCode {
+				t.Errorf("DiffCheck() = %d, want %d", code, tt.wantCode)
+			}
+			out := buf.String()
+			if !strings.Contains(out, "new.go") {
+				t.Errorf("expected the rendered diff before the verdict, got:\n%s", out)
+			}
+			if !strings.HasSuffix(out, tt.wantLine+"\n") {
+				t.Errorf("expected verdict %q as the last line, got:\n%s", tt.wantLine, out)
+			}
+		})
+	}
+
+	// --no-untracked hides new.go from the render and from the score
+	var buf strings.Builder
+	if code := DiffCheck(&buf, "", false, true, 20); code != CheckUnder {
+		t.Errorf("DiffCheck(noUntracked) = %d, want %d", code, CheckUnder)
+	}
+	if out := buf.String(); strings.Contains(out, "new.go") || !strings.HasSuffix(out, "OK (0/20 pts)\n") {
+		t.Errorf("expected new.go hidden and scored 0, got:\n%s", out)
+	}
+}
@@ -0,0 +1,198 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// ErrDoctorFailed is returned when a critical doctor check fails.
+var ErrDoctorFailed = errors.New("critical checks failed")
+
+// doctorCheck is one line of the doctor checklist.
+type doctorCheck struct {
+	Name     string
+	OK       bool
+	Critical bool   // Failing critical checks makes doctor exit non-zero
+	Detail   string // Shown after the name (path, error, etc.)
+	Hint     string // Remediation shown on failure
+}
+
+// Doctor prints a setup checklist and returns ErrDoctorFailed if a
+// critical check fails.
+func Doctor() error {
+	checks := runDoctorChecks()
+	printDoctorChecks(os.Stdout, checks)
+	for _, c := range checks {
+		if !c.OK && c.Critical {
+			return ErrDoctorFailed
+		}
+	}
+	return nil
+}
+
+// runDoctorChecks runs all checks. Repo-dependent checks are skipped
+// outside a git repo since the repo check already explains the failure.
+func runDoctorChecks() []doctorCheck {
+	var checks []doctorCheck
+
+	git := doctorCheck{Name: "git installed", Critical: true, Hint: "install git and ensure it is on PATH"}
+	if path, err := exec.LookPath("git"); err == nil {
+		git.OK, git.Detail = true, path
+	}
+	checks = append(checks, git)
+
+	repo := doctorCheck{Name: "git repository", Critical: true, Hint: "run from inside a git repository"}
+	repo.OK = git.OK && IsGitRepo()
+	if repoPath, err := state.GetRepoPath(); err == nil {
+		repo.Detail = repoPath
+	}
+	checks = append(checks, repo)
+
+	if repo.OK {
+		checks = append(checks, checkCheckpointDir())
+	}
+
+	statusLine := doctorCheck{
+		Name: "status line configured",
+		Hint: "start a new Claude Code session to auto-configure, or see README \"Status Line Setup\"",
+	}
+	statusLine.OK = hasStatusLineConfigured()
+	checks = append(checks, statusLine)
+
+	bin := doctorCheck{Name: "bumper-lanes binary", Critical: true, Hint: "rebuild with `just build` or reinstall the plugin"}
+	if exe := getBumperLanesBinPath(); fileExists(exe) {
+		bin.OK, bin.Detail = true, exe
+	}
+	checks = append(checks, bin)
+
+	if repo.OK {
+		checks = append(checks, checkConfigFile("repo config", config.GetConfigPath()))
+
+		ignore := doctorCheck{Name: "repo config gitignore status", OK: true}
+		if warning := config.GitignoreWarning(); warning != "" {
+			ignore.OK, ignore.Detail = false, warning
+		}
+		checks = append(checks, ignore)
+
+		formats := doctorCheck{Name: "single repo config file", OK: true}
+		if warning := config.ConfigFormatWarning(); warning != "" {
+			formats.OK, formats.Detail = false, warning
+		}
+		checks = append(checks, formats)
+		checks = append(checks, checkLeakedCheckpoints())
+	}
+	checks = append(checks, checkConfigFile("global config", config.GetGlobalConfigPath()))
+
+	return checks
+}
+
+// checkCheckpointDir verifies session state can be written.
+// A missing dir is fine (created on session start) as long as its parent is writable.
+func checkCheckpointDir() doctorCheck {
+	check := doctorCheck{Name: "checkpoint dir writable", Critical: true, Hint: "check permissions on the .git directory"}
+
+	dir, err := state.GetCheckpointDir()
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	check.Detail = dir
+
+	target := dir
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		target = filepath.Dir(dir)
+		check.Detail += " (not yet created)"
+	}
+
+	f, err := os.CreateTemp(target, "doctor-*.tmp")
+	if err != nil {
+		check.Detail = fmt.Sprintf("%s: %v", dir, err)
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+	check.OK = true
+	return check
+}
+
+// checkLeakedCheckpoints warns when bumper-checkpoints/ files appear in git
+// status, which means session state ended up inside the work tree and could be
+// committed. Scoring already ignores these paths.
+func checkLeakedCheckpoints() doctorCheck {
+	check := doctorCheck{
+		Name: "checkpoints outside work tree",
+		OK:   true,
+		Hint: "remove bumper-checkpoints/ from the work tree (git rm -r --cached if committed) and add it to .gitignore",
+	}
+
+	output, err := exec.Command("git", "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		return check
+	}
+	var leaked []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		if path := line[3:]; scoring.IsInternalPath(path) {
+			leaked = append(leaked, path)
+		}
+	}
+	if len(leaked) > 0 {
+		check.OK = false
+		check.Detail = fmt.Sprintf("%d file(s) in git status, e.g. %s", len(leaked), leaked[0])
+	}
+	return check
+}
+
+// checkConfigFile validates a config file. A missing file passes (defaults apply).
+func checkConfigFile(name, path string) doctorCheck {
+	check := doctorCheck{Name: name + " valid", Critical: true, Detail: path, Hint: "fix or remove " + path}
+	if path == "" {
+		check.OK = true
+		return check
+	}
+
+	err := config.ValidateConfigFile(path)
+	switch {
+	case err == nil:
+		check.OK = true
+	case errors.Is(err, os.ErrNotExist):
+		check.OK = true
+		check.Detail = path + " (not found, using defaults)"
+	default:
+		check.Detail = fmt.Sprintf("%s: %v", path, err)
+	}
+	return check
+}
+
+// printDoctorChecks writes the checklist with pass/fail marks and hints.
+func printDoctorChecks(w io.Writer, checks []doctorCheck) {
+	for _, c := range checks {
+		mark := "✓"
+		if !c.OK {
+			mark = "✗"
+			if !c.Critical {
+				mark = "⚠"
+			}
+		}
+
+		if c.Detail != "" {
+			fmt.Fprintf(w, "%s %s: %s\n", mark, c.Name, c.Detail)
+		} else {
+			fmt.Fprintf(w, "%s %s\n", mark, c.Name)
+		}
+		if !c.OK && c.Hint != "" {
+			fmt.Fprintf(w, "    → %s\n", c.Hint)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func findCheck(checks []doctorCheck, name string) *doctorCheck {
+	for i := range checks {
+		if checks[i].Name == name {
+			return &checks[i]
+		}
+	}
+	return nil
+}
+
+func TestDoctorChecks(t *testing.T) {
+	t.Run("not in git repo", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		t.Setenv("HOME", tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		checks := runDoctorChecks()
+
+		repo := findCheck(checks, "git repository")
+		if repo == nil || repo.OK {
+			t.Fatalf("git repository check = %+v, want failing", repo)
+		}
+		if !repo.Critical {
+			t.Error("git repository check should be critical")
+		}
+		if findCheck(checks, "checkpoint dir writable") != nil {
+			t.Error("checkpoint dir check should be skipped outside a repo")
+		}
+
+		var buf bytes.Buffer
+		printDoctorChecks(&buf, checks)
+		if !strings.Contains(buf.String(), "✗ git repository") {
+			t.Errorf("output missing failed repo line:\n%s", buf.String())
+		}
+		if !strings.Contains(buf.String(), "→ run from inside a git repository") {
+			t.Errorf("output missing remediation hint:\n%s", buf.String())
+		}
+	})
+
+	t.Run("statusline not configured", func(t *testing.T) {
+		if !IsGitRepo() {
+			t.Skip("Not in a git repo")
+		}
+
+		tmpHome := t.TempDir()
+		t.Setenv("HOME", tmpHome)
+
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		checks := runDoctorChecks()
+
+		sl := findCheck(checks, "status line configured")
+		if sl == nil || sl.OK {
+			t.Fatalf("status line check = %+v, want failing", sl)
+		}
+		if sl.Critical {
+			t.Error("status line check should be a warning, not critical")
+		}
+
+		var buf bytes.Buffer
+		printDoctorChecks(&buf, checks)
+		if !strings.Contains(buf.String(), "⚠ status line configured") {
+			t.Errorf("output missing warning line:\n%s", buf.String())
+		}
+
+		// Everything else passes in a fresh repo, so doctor shouldn't fail
+		for _, c := range checks {
+			if !c.OK && c.Critical {
+				t.Errorf("unexpected critical failure: %+v", c)
+			}
+		}
+
+		// Configure it and the check passes
+		os.MkdirAll(filepath.Join(tmpHome, ".claude"), 0755)
+		os.WriteFile(filepath.Join(tmpHome, ".claude", "settings.json"),
+			[]byte(`{"statusLine": {"command": "bumper-lanes status"}}`), 0644)
+		if sl := findCheck(runDoctorChecks(), "status line configured"); !sl.OK {
+			t.Error("status line check should pass once configured")
+		}
+	})
+
+	t.Run("invalid repo config is critical", func(t *testing.T) {
+		if !IsGitRepo() {
+			t.Skip("Not in a git repo")
+		}
+
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 5}`), 0644)
+
+		cfg := findCheck(runDoctorChecks(), "repo config valid")
+		if cfg == nil || cfg.OK || !cfg.Critical {
+			t.Errorf("repo config check = %+v, want critical failure", cfg)
+		}
+	})
+
+	t.Run("tracked and gitignored config warns", func(t *testing.T) {
+		if !IsGitRepo() {
+			t.Skip("Not in a git repo")
+		}
+
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 300}`), 0644)
+		exec.Command("git", "add", ".bumper-lanes.json").Run()
+		exec.Command("git", "commit", "-m", "add config").Run()
+		os.WriteFile(".gitignore", []byte(".bumper-lanes.json\n"), 0644)
+
+		checks := runDoctorChecks()
+		ignore := findCheck(checks, "repo config gitignore status")
+		if ignore == nil || ignore.OK {
+			t.Fatalf("gitignore check = %+v, want warning", ignore)
+		}
+		if ignore.Critical {
+			t.Error("gitignore check should be a warning, not critical")
+		}
+
+		var buf bytes.Buffer
+		printDoctorChecks(&buf, checks)
+		if !strings.Contains(buf.String(), "tracked but matches .gitignore") {
+			t.Errorf("output missing gitignore warning:\n%s", buf.String())
+		}
+	})
+
+	t.Run("checkpoint files in git status warn", func(t *testing.T) {
+		if !IsGitRepo() {
+			t.Skip("Not in a git repo")
+		}
+
+		t.Setenv("HOME", t.TempDir())
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		if c := findCheck(runDoctorChecks(), "checkpoints outside work tree"); c == nil || !c.OK {
+			t.Fatalf("clean repo check = %+v, want OK", c)
+		}
+
+		os.MkdirAll("bumper-checkpoints", 0755)
+		os.WriteFile("bumper-checkpoints/session-abc", []byte("{}"), 0644)
+
+		c := findCheck(runDoctorChecks(), "checkpoints outside work tree")
+		if c == nil || c.OK || c.Critical {
+			t.Fatalf("leaked checkpoint check = %+v, want non-critical warning", c)
+		}
+		if !strings.Contains(c.Detail, "bumper-checkpoints/session-abc") {
+			t.Errorf("Detail = %q, want leaked path", c.Detail)
+		}
+	})
+}
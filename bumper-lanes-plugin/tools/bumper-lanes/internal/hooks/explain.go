@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// ExplainInput describes a hypothetical change for the explain calculator.
+type ExplainInput struct {
+	NewLines  int // Lines added in new files
+	EditLines int // Lines added in existing files
+	Files     int // Files with additions; 0 = one per non-zero line kind
+	Deletions int // Deleted lines (only scored with deletion_weight)
+}
+
+// Explain prints the score a hypothetical change would get, using the same
+// math and config options as the hooks. Offline: no git or session needed.
+func Explain(in ExplainInput) error {
+	result, err := explainScore(in)
+	if err != nil {
+		return err
+	}
+	fmt.Print(formatExplain(result, config.LoadThreshold()))
+	return nil
+}
+
+// explainScore builds synthetic stats matching in and scores them with the
+// config-derived options. A hypothetical change has no patch, so comment,
+// hunk, and head-of-file adjustments don't apply.
+func explainScore(in ExplainInput) (*scoring.WeightedScore, error) {
+	stats, err := explainStats(in)
+	if err != nil {
+		return nil, err
+	}
+	return scoring.CalculateWithOptions(stats, config.LoadScoringOptions()), nil
+}
+
+// explainStats spreads the line counts over the requested number of files.
+// Weights are per line, so only the new/edit split and file count matter:
+// new lines go in one new file, edit lines fill the rest.
+func explainStats(in ExplainInput) (*diff.StatsJSON, error) {
+	if in.NewLines < 0 || in.EditLines < 0 || in.Files < 0 || in.Deletions < 0 {
+		return nil, fmt.Errorf("counts must be non-negative")
+	}
+
+	newFiles, editFiles := 0, 0
+	if in.NewLines > 0 {
+		newFiles = 1
+	}
+	if in.EditLines > 0 {
+		editFiles = 1
+	}
+	if in.Files > 0 {
+		if in.Files < newFiles+editFiles {
+			return nil, fmt.Errorf("--files %d is too few for both new and edited lines", in.Files)
+		}
+		if in.EditLines > 0 {
+			editFiles = in.Files - newFiles
+		} else {
+			newFiles = in.Files
+		}
+	}
+	if in.NewLines < newFiles || in.EditLines < editFiles {
+		return nil, fmt.Errorf("each of the %d files needs at least one added line", newFiles+editFiles)
+	}
+
+	stats := &diff.StatsJSON{}
+	add := func(name string, lines, count int, isNew bool) {
+		for i := 0; i < count; i++ {
+			share := lines / count
+			if i < lines%count {
+				share++
+			}
+			stats.Files = append(stats.Files, diff.FileStatJSON{Path: fmt.Sprintf("%s%d", name, i), Adds: share, New: isNew})
+		}
+	}
+	add("new", in.NewLines, newFiles, true)
+	add("edit", in.EditLines, editFiles, false)
+
+	// Deletions ride on an extra pure-deletion file so they never affect scatter
+	if in.Deletions > 0 {
+		stats.Files = append(stats.Files, diff.FileStatJSON{Path: "deleted", Dels: in.Deletions})
+	}
+
+	for _, f := range stats.Files {
+		stats.Totals.Adds += f.Adds
+		stats.Totals.Dels += f.Dels
+	}
+	stats.Totals.FileCount = len(stats.Files)
+	return stats, nil
+}
+
+// formatExplain formats the breakdown like the Stop hook's threshold message.
+func formatExplain(r *scoring.WeightedScore, threshold int) string {
+	verdict := "threshold disabled"
+	if !config.IsDisabled(threshold) {
+		verdict = fmt.Sprintf("%d%% of %d threshold", (r.Score*100)/threshold, threshold)
+	}
+	return fmt.Sprintf(`Score: %d points (%s)
+- New file additions: %d lines (1.0×)
+- Edit additions: %d lines (1.3×)
+- Files touched: %d
+- Scatter penalty: %s%s
+`, r.Score, verdict, r.NewAdditions, r.EditAdditions, r.FilesTouched, formatScatter(r), formatOptionalBreakdown(r))
+}
@@ -0,0 +1,122 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+func TestExplainMatchesCalculate(t *testing.T) {
+	// Outside any repo so only defaults apply
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		name  string
+		in    ExplainInput
+		stats []diff.FileStatJSON
+	}{
+		{
+			"new and edit, default files",
+			ExplainInput{NewLines: 50, EditLines: 80},
+			[]diff.FileStatJSON{{Path: "a", Adds: 50, New: true}, {Path: "b", Adds: 80}},
+		},
+		{
+			"7 files triggers low scatter",
+			ExplainInput{NewLines: 50, EditLines: 80, Files: 7},
+			[]diff.FileStatJSON{
+				{Path: "a", Adds: 50, New: true},
+				{Path: "b", Adds: 14}, {Path: "c", Adds: 14}, {Path: "d", Adds: 13},
+				{Path: "e", Adds: 13}, {Path: "f", Adds: 13}, {Path: "g", Adds: 13},
+			},
+		},
+		{
+			"12 new files triggers high scatter",
+			ExplainInput{NewLines: 120, Files: 12},
+			func() []diff.FileStatJSON {
+				var files []diff.FileStatJSON
+				for i := 0; i < 12; i++ {
+					files = append(files, diff.FileStatJSON{Path: string(rune('a' + i)), Adds: 10, New: true})
+				}
+				return files
+			}(),
+		},
+		{
+			"deletions are free by default",
+			ExplainInput{EditLines: 10, Deletions: 500},
+			[]diff.FileStatJSON{{Path: "a", Adds: 10, Dels: 500}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := explainScore(tt.in)
+			if err != nil {
+				t.Fatalf("explainScore() error = %v", err)
+			}
+			want := scoring.Calculate(&diff.StatsJSON{Files: tt.stats})
+			if *got != *want {
+				t.Errorf("explainScore() = %+v, want %+v", *got, *want)
+			}
+		})
+	}
+}
+
+func TestExplainScatterMode(t *testing.T) {
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(t.TempDir())
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	// 12 one-line files: raw scatter counts 12 files, weighted about 1
+	in := ExplainInput{NewLines: 12, Files: 12}
+	flat, err := explainScore(in)
+	if err != nil {
+		t.Fatalf("explainScore() error = %v", err)
+	}
+
+	os.MkdirAll(filepath.Join(configHome, "bumper-lanes"), 0755)
+	os.WriteFile(filepath.Join(configHome, "bumper-lanes", "config.json"), []byte(`{"scatter_mode": "weighted"}`), 0644)
+	weighted, err := explainScore(in)
+	if err != nil {
+		t.Fatalf("explainScore() error = %v", err)
+	}
+	if weighted.ScatterPenalty >= flat.ScatterPenalty {
+		t.Errorf("weighted scatter penalty = %d, want below flat %d", weighted.ScatterPenalty, flat.ScatterPenalty)
+	}
+}
+
+func TestExplainInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ExplainInput
+	}{
+		{"negative lines", ExplainInput{NewLines: -1}},
+		{"more files than lines", ExplainInput{EditLines: 3, Files: 5}},
+		{"one file for two kinds", ExplainInput{NewLines: 5, EditLines: 5, Files: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := explainStats(tt.in); err == nil {
+				t.Error("explainStats() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestFormatExplain(t *testing.T) {
+	out := formatExplain(&scoring.WeightedScore{Score: 300, EditAdditions: 230}, 600)
+	if !strings.Contains(out, "Score: 300 points (50% of 600 threshold)") {
+		t.Errorf("formatExplain() = %q, want score and threshold percentage", out)
+	}
+	if out := formatExplain(&scoring.WeightedScore{Score: 300}, 0); !strings.Contains(out, "threshold disabled") {
+		t.Errorf("formatExplain() = %q, want threshold disabled", out)
+	}
+}
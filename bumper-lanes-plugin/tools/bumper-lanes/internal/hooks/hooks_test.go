@@ -1,7 +1,15 @@
 package hooks
 
 import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
 func TestIsGitRepo(t *testing.T) {
@@ -19,3 +27,324 @@ func TestGetCurrentBranch(t *testing.T) {
 	}
 	t.Logf("Current branch: %s", branch)
 }
+
+func TestCaptureTreeRetriesGitLock(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BUMPER_LANES_LOG_DIR", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	lockErr := errors.New("fatal: Unable to create '/repo/.git/index.lock': File exists.")
+	tests := []struct {
+		name      string
+		failures  int   // leading failures before the real write-tree runs
+		failErr   error // error returned by each failure
+		wantCalls int
+		wantErr   bool
+	}{
+		{"no contention", 0, nil, 1, false},
+		{"lock clears after retries", 2, lockErr, 3, false},
+		{"lock never clears", 10, lockErr, writeTreeAttempts, true},
+		{"other errors are not retried", 10, errors.New("fatal: not a git repository"), 1, true},
+	}
+
+	realWriteTree := writeTree
+	defer func() { writeTree = realWriteTree }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			writeTree = func(dir, indexPath string) ([]byte, error) {
+				calls++
+				if calls <= tt.failures {
+					return nil, tt.failErr
+				}
+				return realWriteTree(dir, indexPath)
+			}
+
+			tree, err := CaptureTree()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CaptureTree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tree == "" {
+				t.Error("CaptureTree() returned an empty tree")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("write-tree ran %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+
+	// The scoring capture, with untracked files counted, retries the same way
+	t.Run("scoring capture retries", func(t *testing.T) {
+		os.WriteFile("untracked.txt", []byte("x\n"), 0644)
+		defer os.Remove("untracked.txt")
+		calls := 0
+		writeTree = func(dir, indexPath string) ([]byte, error) {
+			if calls++; calls <= 2 {
+				return nil, lockErr
+			}
+			return realWriteTree(dir, indexPath)
+		}
+		if _, err := captureCurrentTree(); err != nil || calls != 3 {
+			t.Errorf("captureCurrentTree() error = %v after %d write-tree runs, want success on the 3rd", err, calls)
+		}
+	})
+}
+
+func TestGetIndexTreeLeavesIndexAlone(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("BUMPER_LANES_LOG_DIR", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	indexPath := filepath.Join(tmpDir, ".git", "index")
+	t.Run("no index yet is the empty tree", func(t *testing.T) {
+		os.Remove(indexPath) // The initial commit is empty, so nothing is lost
+		tree, err := getIndexTree()
+		if err != nil || tree != "4b825dc642cb6eb9a060e54bf8d69288fbee4904" {
+			t.Errorf("getIndexTree() = %q, %v, want the empty tree", tree, err)
+		}
+		if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+			t.Errorf("getIndexTree() created the index: %v", err)
+		}
+	})
+
+	os.WriteFile("staged.go", []byte("package x\n"), 0644)
+	gitOutput(t, tmpDir, "add", "staged.go")
+	os.WriteFile("unstaged.go", []byte("package x\n"), 0644)
+	before, _ := os.ReadFile(indexPath)
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(indexPath, old, old)
+
+	// The user's own git operation holds the real index lock
+	lock := indexPath + ".lock"
+	os.WriteFile(lock, nil, 0644)
+	defer os.Remove(lock)
+
+	tree, err := getIndexTree()
+	if err != nil {
+		t.Fatalf("getIndexTree() with index.lock held: %v", err)
+	}
+	if files := gitOutput(t, tmpDir, "ls-tree", "--name-only", tree); strings.TrimSpace(files) != "staged.go" {
+		t.Errorf("index tree files = %q, want only staged.go", files)
+	}
+	after, _ := os.ReadFile(indexPath)
+	info, _ := os.Stat(indexPath)
+	if string(after) != string(before) || !info.ModTime().Equal(old) {
+		t.Error("getIndexTree() rewrote the real index")
+	}
+}
+
+func TestParseInputDiagnostics(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		debug     bool
+		wantErr   bool
+		logFile   string
+		wantLog   []string
+		unwantLog []string
+	}{
+		{
+			name:    "malformed JSON",
+			input:   `{"session_id": "abc",`,
+			wantErr: true,
+			logFile: "session-unknown.log",
+			wantLog: []string{"[WARN] [input] unparseable hook input", `raw: {"session_id": "abc",`},
+		},
+		{
+			name:    "missing hook_event_name",
+			input:   `{"session_id": "abc"}`,
+			logFile: "session-abc.log",
+			wantLog: []string{"[WARN] [input] hook input missing required fields: hook_event_name"},
+		},
+		{
+			name:    "missing both required fields",
+			input:   `{"tool_name": "Bash"}`,
+			logFile: "session-unknown.log",
+			wantLog: []string{"missing required fields: session_id, hook_event_name"},
+		},
+		{
+			name:      "complete input logs nothing without debug",
+			input:     `{"session_id": "abc", "hook_event_name": "Stop", "stop_hook_active": false}`,
+			logFile:   "session-abc.log",
+			unwantLog: []string{"[WARN]", "[DEBUG]"},
+		},
+		{
+			name:    "debug logs populated and missing fields",
+			input:   `{"session_id": "abc", "hook_event_name": "PostToolUse", "tool_name": "Bash", "cwd": "/tmp"}`,
+			debug:   true,
+			logFile: "session-abc.log",
+			wantLog: []string{
+				"[DEBUG] [input] hook input event=PostToolUse",
+				"populated=[session_id hook_event_name tool_name]",
+				"missing=[tool_input]",
+				"other=[cwd]",
+				`raw={"session_id": "abc"`,
+			},
+			unwantLog: []string{"[WARN]"},
+		},
+		{
+			name:    "debug treats null as missing",
+			input:   `{"session_id": "abc", "hook_event_name": null}`,
+			debug:   true,
+			logFile: "session-abc.log",
+			wantLog: []string{"missing required fields: hook_event_name", "populated=[session_id] missing=[hook_event_name]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logDir := t.TempDir()
+			t.Setenv("BUMPER_LANES_LOG_DIR", logDir)
+			if tt.debug {
+				t.Setenv("BUMPER_LANES_DEBUG", "1")
+			} else {
+				t.Setenv("BUMPER_LANES_DEBUG", "")
+			}
+
+			_, err := parseInput([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseInput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			data, _ := os.ReadFile(filepath.Join(logDir, tt.logFile))
+			log := string(data)
+			for _, want := range tt.wantLog {
+				if !strings.Contains(log, want) {
+					t.Errorf("log missing %q:\n%s", want, log)
+				}
+			}
+			for _, unwant := range tt.unwantLog {
+				if strings.Contains(log, unwant) {
+					t.Errorf("log should not contain %q:\n%s", unwant, log)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveSessionID(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	if got := resolveSessionID(&HookInput{SessionID: "explicit"}); got != "explicit" {
+		t.Errorf("explicit session_id: got %q, want %q", got, "explicit")
+	}
+	if got := resolveSessionID(&HookInput{}); got != "" {
+		t.Errorf("no sessions: got %q, want empty", got)
+	}
+
+	first, _ := state.New("only-session", GetHeadTree(), "main", 400)
+	first.Save()
+	if got := resolveSessionID(&HookInput{}); got != "only-session" {
+		t.Errorf("single session: got %q, want %q", got, "only-session")
+	}
+
+	// The fallback reaches the prompt handlers
+	out, _ := captureOutput(t, func() {
+		HandlePrompt(&HookInput{HookEventName: "UserPromptSubmit", Prompt: "/bumper-info"})
+	})
+	if strings.Contains(out, "No session ID available") {
+		t.Errorf("/bumper-info with one session should fall back, got %q", out)
+	}
+
+	second, _ := state.New("other-session", GetHeadTree(), "main", 400)
+	second.Save()
+	if got := resolveSessionID(&HookInput{}); got != "" {
+		t.Errorf("ambiguous sessions: got %q, want empty", got)
+	}
+	out, _ = captureOutput(t, func() {
+		HandlePrompt(&HookInput{HookEventName: "UserPromptSubmit", Prompt: "/bumper-info"})
+	})
+	if !strings.Contains(out, "No session ID available") {
+		t.Errorf("/bumper-info with several sessions should keep the error, got %q", out)
+	}
+
+	// A session whose state hasn't been written in a day no longer competes
+	checkpointDir, _ := state.GetCheckpointDir()
+	stale := time.Now().Add(-2 * inheritMaxAge)
+	os.Chtimes(filepath.Join(checkpointDir, "session-other-session"), stale, stale)
+	if got := resolveSessionID(&HookInput{}); got != "only-session" {
+		t.Errorf("one live and one stale session: got %q, want %q", got, "only-session")
+	}
+}
+
+// TestWorktreeIndependentBaselines checks that two worktrees sharing one
+// object store keep separate sessions: work and commits in one never move
+// the other's baseline or score.
+func TestWorktreeIndependentBaselines(t *testing.T) {
+	mainRepo := t.TempDir()
+	setupTempGitRepo(t, mainRepo)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(mainRepo)
+
+	worktree := filepath.Join(t.TempDir(), "wt")
+	if out, err := exec.Command("git", "worktree", "add", worktree, "-b", "feature").CombinedOutput(); err != nil {
+		t.Skipf("git worktree not supported: %v\n%s", err, out)
+	}
+	defer exec.Command("git", "worktree", "remove", "--force", worktree).Run()
+
+	runStop := func(sessionID string) *state.SessionState {
+		t.Helper()
+		captureOutput(t, func() { Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"}) })
+		sess, err := state.Load(sessionID)
+		if err != nil {
+			t.Fatalf("state.Load(%s): %v", sessionID, err)
+		}
+		return sess
+	}
+
+	// Session A in the main worktree, with 10 uncommitted lines
+	baselineA, _ := CaptureTree()
+	sessA, _ := state.New("wt-a", baselineA, "main", 400)
+	sessA.Save()
+	os.WriteFile("a.txt", []byte(strings.Repeat("a\n", 10)), 0644)
+
+	// Session B in the linked worktree: committed work, then 5 more lines
+	os.Chdir(worktree)
+	if _, err := state.Load("wt-a"); err == nil {
+		t.Fatal("worktree B sees worktree A's session; checkpoint dirs should be separate")
+	}
+	baselineB, _ := CaptureTree()
+	sessB, _ := state.New("wt-b", baselineB, "feature", 400)
+	sessB.Save()
+	os.WriteFile("b.txt", []byte(strings.Repeat("b\n", 50)), 0644)
+	exec.Command("git", "add", "b.txt").Run()
+	exec.Command("git", "commit", "-m", "feature work").Run()
+	captureStderr(t, func() {
+		PostToolUse(&HookInput{SessionID: "wt-b", HookEventName: "PostToolUse", ToolName: "Bash", ToolInput: &ToolInput{Command: "git commit -m 'feature work'"}})
+	})
+	os.WriteFile("c.txt", []byte(strings.Repeat("c\n", 5)), 0644)
+
+	if got := runStop("wt-b"); got.Score != 5 || len(got.ResetHistory) != 1 {
+		t.Errorf("B: score %d, %d resets; want 5 after its own commit reset", got.Score, len(got.ResetHistory))
+	}
+
+	// Back in A: B's commit moved B's HEAD only
+	os.Chdir(mainRepo)
+	got := runStop("wt-a")
+	if got.Score != 10 {
+		t.Errorf("A: score %d, want 10 (only A's own changes)", got.Score)
+	}
+	if got.BaselineTree != baselineA || len(got.ResetHistory) != 0 {
+		t.Errorf("A: baseline %s with %d resets; want the original baseline, untouched", shortSHA(got.BaselineTree), len(got.ResetHistory))
+	}
+}
@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// Session states reported by the bumper_lanes_sessions gauge.
+var metricStates = []string{"active", "tripped", "paused", "disabled"}
+
+// Metrics writes Prometheus text-format metrics aggregated over every
+// session file in the repo's checkpoint dir, for an external scraper.
+func Metrics(w io.Writer) error {
+	sessions, err := state.LoadAll()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, formatMetrics(sessions))
+	return err
+}
+
+// sessionMetricState classifies a session the same way the status line does.
+func sessionMetricState(sess *state.SessionState) string {
+	switch {
+	case sess.ThresholdLimit == 0:
+		return "disabled"
+	case sess.IsPaused(time.Now()):
+		return "paused"
+	case sess.StopTriggered:
+		return "tripped"
+	default:
+		return "active"
+	}
+}
+
+// formatMetrics renders the Prometheus exposition text.
+// Reset history entries are a gauge, not a counter: each session keeps at
+// most state.MaxResetHistory, undo pops one, and SessionEnd deletes them.
+func formatMetrics(sessions []*state.SessionState) string {
+	counts := make(map[string]int)
+	var scoreSum, resets int
+	for _, sess := range sessions {
+		counts[sessionMetricState(sess)]++
+		scoreSum += sess.Score
+		resets += len(sess.ResetHistory)
+	}
+
+	var avg float64
+	if len(sessions) > 0 {
+		avg = float64(scoreSum) / float64(len(sessions))
+	}
+
+	var b strings.Builder
+	b.WriteString("# HELP bumper_lanes_sessions Sessions with a state file, by enforcement state.\n")
+	b.WriteString("# TYPE bumper_lanes_sessions gauge\n")
+	for _, st := range metricStates {
+		fmt.Fprintf(&b, "bumper_lanes_sessions{state=%q} %d\n", st, counts[st])
+	}
+	b.WriteString("# HELP bumper_lanes_score_average Mean current score across sessions.\n")
+	b.WriteString("# TYPE bumper_lanes_score_average gauge\n")
+	fmt.Fprintf(&b, "bumper_lanes_score_average %g\n", avg)
+	b.WriteString("# HELP bumper_lanes_reset_history_entries Baseline resets currently held in session reset history.\n")
+	b.WriteString("# TYPE bumper_lanes_reset_history_entries gauge\n")
+	fmt.Fprintf(&b, "bumper_lanes_reset_history_entries %d\n", resets)
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestMetrics(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	t.Run("no checkpoint dir", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Metrics(&buf); err != nil {
+			t.Fatalf("Metrics() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), `bumper_lanes_sessions{state="active"} 0`) {
+			t.Errorf("expected zero sessions, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("synthetic sessions", func(t *testing.T) {
+		checkpointDir, _ := state.GetCheckpointDir()
+		os.MkdirAll(checkpointDir, 0755)
+
+		sessions := []state.SessionState{
+			{SessionID: "a", Score: 100, ThresholdLimit: 400},
+			{SessionID: "b", Score: 500, ThresholdLimit: 400, StopTriggered: true, ResetHistory: make([]state.ResetEntry, 3)},
+			{SessionID: "c", Score: 60, ThresholdLimit: 400, Paused: true, ResetHistory: make([]state.ResetEntry, 1)},
+			{SessionID: "d", Score: 0, ThresholdLimit: 0},
+		}
+		for _, sess := range sessions {
+			data, _ := json.Marshal(sess)
+			os.WriteFile(filepath.Join(checkpointDir, "session-"+sess.SessionID), data, 0644)
+		}
+		// Ignored: temp files, non-session files, and corrupt state
+		os.WriteFile(filepath.Join(checkpointDir, "session-x.tmp"), []byte("{}"), 0644)
+		os.WriteFile(filepath.Join(checkpointDir, "stats-cache.json"), []byte("{}"), 0644)
+		os.WriteFile(filepath.Join(checkpointDir, "session-corrupt"), []byte("not json"), 0644)
+
+		var buf bytes.Buffer
+		if err := Metrics(&buf); err != nil {
+			t.Fatalf("Metrics() error = %v", err)
+		}
+		got := buf.String()
+
+		for _, want := range []string{
+			"# TYPE bumper_lanes_sessions gauge\n",
+			`bumper_lanes_sessions{state="active"} 1` + "\n",
+			`bumper_lanes_sessions{state="tripped"} 1` + "\n",
+			`bumper_lanes_sessions{state="paused"} 1` + "\n",
+			`bumper_lanes_sessions{state="disabled"} 1` + "\n",
+			"bumper_lanes_score_average 165\n",
+			"# TYPE bumper_lanes_reset_history_entries gauge\n",
+			"bumper_lanes_reset_history_entries 4\n",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("metrics missing %q in:\n%s", want, got)
+			}
+		}
+	})
+}
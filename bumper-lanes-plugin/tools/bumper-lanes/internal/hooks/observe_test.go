@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// captureOutput runs fn with stdout and stderr redirected, returning both.
+func captureOutput(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+	stdout = captureFile(t, &os.Stdout, func() {
+		stderr = captureStderr(t, fn)
+	})
+	return stdout, stderr
+}
+
+func TestObserveOnlyHooksSilent(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    string
+	}{
+		{"config", `{"threshold": 50, "observe_only": true}`, ""},
+		{"env var", `{"threshold": 50}`, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			setupTempGitRepo(t, tmpDir)
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("BUMPER_LANES_OBSERVE", tt.env)
+
+			origDir, _ := os.Getwd()
+			defer os.Chdir(origDir)
+			os.Chdir(tmpDir)
+
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			exec.Command("git", "add", ".bumper-lanes.json").Run()
+			exec.Command("git", "commit", "-m", "config").Run()
+
+			sessionID := "test-observe-" + strings.ReplaceAll(tt.name, " ", "-")
+			run := func(hook string, fn func() int) {
+				t.Helper()
+				var code int
+				stdout, stderr := captureOutput(t, func() { code = fn() })
+				if code != 0 || stdout != "" || stderr != "" {
+					t.Errorf("%s: exit %d, stdout %q, stderr %q; want exit 0 and no output", hook, code, stdout, stderr)
+				}
+			}
+
+			// SessionStart would otherwise warn about and set up the status line
+			run("SessionStart", func() int { return SessionStart(&HookInput{SessionID: sessionID}) })
+
+			// Well over the 50-point threshold, past every fuel gauge tier
+			os.WriteFile(filepath.Join(tmpDir, "big.go"), []byte(strings.Repeat("x\n", 200)), 0644)
+			run("PostToolUse Write", func() int {
+				return PostToolUse(&HookInput{SessionID: sessionID, HookEventName: "PostToolUse", ToolName: "Write"})
+			})
+			run("Stop", func() int {
+				if err := Stop(&HookInput{SessionID: sessionID}); err != nil {
+					return 1
+				}
+				return 0
+			})
+
+			sess, err := state.Load(sessionID)
+			if err != nil {
+				t.Fatalf("state.Load() error = %v", err)
+			}
+			if sess.Score <= 50 {
+				t.Errorf("score = %d, want it still recorded over the threshold", sess.Score)
+			}
+			if sess.StopTriggered {
+				t.Error("Stop should never trip in observe mode")
+			}
+
+			// A session tripped before observe mode was turned on still isn't blocked
+			sess.SetStopTriggered(true)
+			sess.Save()
+			run("PreToolUse", func() int {
+				return PreToolUse(&HookInput{SessionID: sessionID, HookEventName: "PreToolUse", ToolName: "Write"})
+			})
+
+			exec.Command("git", "add", "big.go").Run()
+			exec.Command("git", "commit", "-m", "big").Run()
+			run("PostToolUse Bash", func() int {
+				return PostToolUse(&HookInput{
+					SessionID:     sessionID,
+					HookEventName: "PostToolUse",
+					ToolName:      "Bash",
+					ToolInput:     &ToolInput{Command: "git commit -m big"},
+				})
+			})
+
+			sess, _ = state.Load(sessionID)
+			if sess.Score != 0 {
+				t.Errorf("after commit: score = %d, want the baseline still auto-reset to 0", sess.Score)
+			}
+		})
+	}
+}
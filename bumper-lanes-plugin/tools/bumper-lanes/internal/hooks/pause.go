@@ -2,24 +2,34 @@ package hooks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
 // Pause handles the pause user command.
-// It sets paused=true to temporarily disable enforcement.
-func Pause(sessionID string) error {
+// It sets paused=true to temporarily disable enforcement; a positive
+// duration auto-resumes enforcement after it elapses.
+func Pause(sessionID string, duration time.Duration) error {
 	sess, err := state.Load(sessionID)
 	if err != nil {
 		return fmt.Errorf("no session state for %s", sessionID)
 	}
 
-	sess.SetPaused(true)
+	if duration > 0 {
+		sess.PauseFor(duration, time.Now())
+	} else {
+		sess.SetPaused(true)
+	}
 
 	if err := sess.Save(); err != nil {
 		return fmt.Errorf("failed to save state: %w", err)
 	}
 
+	if duration > 0 {
+		fmt.Printf("Enforcement paused for %s. Use 'resume' to re-enable sooner.\n", duration)
+		return nil
+	}
 	fmt.Println("Enforcement paused. Use 'resume' to re-enable.")
 	return nil
 }
@@ -4,27 +4,144 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
-	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
-// gitCommitPattern matches git commit commands with optional flags.
+// gitCommitPattern matches a single git commit command with optional flags.
 // Matches: git commit, git -C /path commit, git --git-dir=/x commit
 // Rejects: prose like "use git to commit"
-var gitCommitPattern = regexp.MustCompile(`git\s+(-{1,2}[A-Za-z-]+([ =]("[^"]*"|\S+))?\s+)*commit\b`)
+// Anchored at the start: apply it per command via isGitCommitCommand.
+var gitCommitPattern = regexp.MustCompile(`^git\s+(-{1,2}[A-Za-z-]+([ =]("[^"]*"|\S+))?\s+)*commit\b`)
+
+// envAssignPattern matches a leading VAR=value prefix, e.g. "GIT_AUTHOR_NAME=x ".
+var envAssignPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=("[^"]*"|'[^']*'|\S*)\s+`)
+
+// isGitCommitCommand reports whether any command in a shell line is a git commit.
+// The line is split on unquoted separators (&&, ||, ;, |, &, newline) and each
+// segment must itself start with git (after env assignments or a subshell paren),
+// so "npm test && git commit" matches and "echo 'git commit'" does not.
+func isGitCommitCommand(line string) bool {
+	_, ok := gitCommitSegment(line)
+	return ok
+}
+
+// isAmendCommand reports whether the git commit in a shell line uses --amend.
+func isAmendCommand(line string) bool {
+	segment, ok := gitCommitSegment(line)
+	if !ok {
+		return false
+	}
+	for _, word := range shellWords(segment) {
+		if word == "--amend" {
+			return true
+		}
+	}
+	return false
+}
+
+// shellWords splits a command on unquoted whitespace, dropping the quotes,
+// so a quoted commit message stays one word.
+func shellWords(command string) []string {
+	var words []string
+	var current strings.Builder
+	var quote rune
+	inWord := false
+
+	for _, c := range command {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words
+}
+
+// gitCommitSegment returns the first git commit command in a shell line,
+// with leading env assignments and subshell parens stripped.
+func gitCommitSegment(line string) (string, bool) {
+	for _, segment := range splitShellCommands(line) {
+		segment = strings.TrimLeft(strings.TrimSpace(segment), "({ ")
+		for {
+			loc := envAssignPattern.FindStringIndex(segment)
+			if loc == nil {
+				break
+			}
+			segment = segment[loc[1]:]
+		}
+		if gitCommitPattern.MatchString(segment) {
+			return segment, true
+		}
+	}
+	return "", false
+}
+
+// splitShellCommands splits a shell line on separators outside quotes.
+// Quoted text stays in its segment verbatim. Not a full shell parser: it
+// only needs to find command boundaries.
+func splitShellCommands(line string) []string {
+	var segments []string
+	var current strings.Builder
+	var quote rune
+	escaped := false
+
+	for _, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ';' || c == '|' || c == '&' || c == '\n':
+			segments = append(segments, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	return append(segments, current.String())
+}
 
 // PostToolUse handles the PostToolUse hook event.
 // For Write/Edit: provides fuel gauge warnings
 // For Bash: detects git commits and auto-resets baseline
-// Returns exit code 2 to ensure stderr reaches Claude.
+//
+// Exit code convention: handlers return 2 if and only if they wrote a message
+// to stderr that Claude should see (exit 2 is what surfaces stderr), else 0.
+// Use notifyClaude for every stderr message so the two never drift apart.
 func PostToolUse(input *HookInput) (exitCode int) {
 	// Validate hook event
 	if input.HookEventName != "PostToolUse" {
 		return 0
 	}
+	input.SessionID = resolveSessionID(input)
 
 	// Route based on tool type
 	switch input.ToolName {
@@ -38,6 +155,10 @@ func PostToolUse(input *HookInput) (exitCode int) {
 }
 
 // handleBashCommit detects git commits and auto-resets baseline.
+// Only resets when HEAD moved: a commit that failed ("nothing to commit",
+// rejected by a pre-commit hook) leaves HEAD where PreToolUse recorded it
+// just before the command, or where the session last saw it if PreToolUse
+// didn't run.
 func handleBashCommit(input *HookInput) int {
 	log := logging.New(input.SessionID, "post_tool_use")
 
@@ -47,7 +168,7 @@ func handleBashCommit(input *HookInput) int {
 	}
 
 	// Check if this is a git commit command
-	if !gitCommitPattern.MatchString(input.ToolInput.Command) {
+	if !isGitCommitCommand(input.ToolInput.Command) {
 		return 0
 	}
 
@@ -58,6 +179,21 @@ func handleBashCommit(input *HookInput) int {
 		return 0 // No session - fail open
 	}
 
+	// Sessions from before LastHead was tracked have no reference point;
+	// keep the old behavior and reset on any commit command.
+	head := GetHeadCommit()
+	before := sess.PreCommitHead
+	if before == "" {
+		before = sess.LastHead
+	}
+	sess.PreCommitHead = ""
+	if before != "" && head == before {
+		traceDecision(log, "skip auto-reset (HEAD unchanged)", sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+		sess.LastHead = head
+		sess.Save()
+		return 0
+	}
+
 	// Capture current tree including untracked files
 	// Must use CaptureTree() (same as manual reset) so pre-existing
 	// untracked files are included in baseline and don't get re-counted
@@ -69,15 +205,36 @@ func handleBashCommit(input *HookInput) int {
 
 	// Reset baseline
 	currentBranch := GetCurrentBranch()
+	// Amending rewrites the previous commit rather than adding work,
+	// so record it distinctly in reset history and messaging
+	amend := isAmendCommand(input.ToolInput.Command)
+	traceDecision(log, "auto-reset (commit)", sess.BaselineTree, currentTree, sess.Score, sess.ThresholdLimit)
+	prevScore := sess.Score
 	sess.ResetBaseline(currentTree, currentBranch)
+	if amend {
+		// No new commit, so nothing to carry over: the budget starts fresh
+		sess.SetLastResetEvent(state.ResetEventAmend)
+	} else {
+		sess.CarryOver(prevScore, config.LoadCarryoverFraction())
+		sess.SetLastResetEvent(state.ResetEventCommit)
+	}
+	sess.LastHead = head
+	startCooldown(sess)
 	if err := sess.Save(); err != nil {
 		return 0
 	}
 
 	// Output feedback
 	threshold := config.LoadThreshold()
-	fmt.Fprintf(os.Stderr, "✓ Bumper lanes: Auto-reset after commit. Fresh budget: %d pts.\n", threshold)
-	return 2
+	carried, budget := config.DisplayScore(sess.Carryover, threshold)
+	if amend {
+		return notifyClaude("✓ Bumper lanes: Amended commit — baseline re-synced. Fresh budget: %d pts.\n", budget)
+	}
+	if sess.Carryover > 0 {
+		return notifyClaude("✓ Bumper lanes: Auto-reset after commit. %d pts carried over, %d of %d pts left.\n",
+			carried, max(0, budget-carried), budget)
+	}
+	return notifyClaude("✓ Bumper lanes: Auto-reset after commit. Fresh budget: %d pts.\n", budget)
 }
 
 // handleWriteEdit provides fuel gauge warnings after file modifications.
@@ -90,9 +247,10 @@ func handleWriteEdit(input *HookInput) int {
 		log.Warn("failed to load session (write/edit): %v (failing open)", err)
 		return 0 // Fail open
 	}
+	refreshThreshold(sess)
 
 	// If paused, exit silently
-	if sess.Paused {
+	if sess.IsPaused(time.Now()) {
 		return 0
 	}
 
@@ -103,33 +261,68 @@ func handleWriteEdit(input *HookInput) int {
 
 	// Get diff stats from baseline (fresh calculation, not incremental)
 	// This allows score to decrease when user manually deletes/reverts changes
-	stats := getStatsJSON(sess.BaselineTree)
-	if stats == nil {
+	result := calculateSessionScore(sess)
+	if result == nil {
 		return 0
 	}
-
-	// Calculate fresh score from baseline
-	result := scoring.Calculate(stats)
 	freshScore := result.Score
 
 	// Update state with fresh score
 	sess.SetScore(freshScore)
-	sess.Save()
+
+	// Below the enforcement floor - silent regardless of tier
+	if freshScore < config.LoadMinEnforceScore() {
+		sess.Save()
+		return 0
+	}
 
 	// Calculate percentage
 	pct := (freshScore * 100) / sess.ThresholdLimit
 
 	// Output fuel gauge to stderr based on threshold tier
-	// Exit 2 ensures stderr reaches Claude (per docs)
 	// Tiers: 70% NOTICE, 90% WARNING
-	if pct >= 90 {
-		fmt.Fprintf(os.Stderr, "WARNING: Review budget at %d%% (%d/%d pts). Complete current work, then ask user about checkpoint.\n", pct, freshScore, sess.ThresholdLimit)
-		return 2
-	} else if pct >= 70 {
-		fmt.Fprintf(os.Stderr, "NOTICE: %d%% budget used (%d/%d pts). Wrap up current task soon.\n", pct, freshScore, sess.ThresholdLimit)
-		return 2
+	tier := gaugeTier(pct)
+	if tier == "" {
+		// Under 70% - silent
+		sess.Save()
+		return 0
 	}
 
-	// Under 70% - silent
-	return 0
+	// Repeating the same tier on every edit is noise; stay quiet for a while
+	// unless the tier escalated.
+	now := time.Now()
+	if !sess.ShouldShowGauge(tier, now, config.LoadGaugeQuietWindow()) {
+		sess.Save()
+		return 0
+	}
+	sess.RecordGauge(tier, now)
+	sess.Save()
+
+	if tier == state.GaugeTierWarning {
+		return notifyClaude("WARNING: Review budget at %d%% (%s pts). Complete current work, then ask user about checkpoint.\n", pct, displayScore(freshScore, sess.ThresholdLimit))
+	}
+	return notifyClaude("NOTICE: %d%% budget used (%s pts). Wrap up current task soon.\n", pct, displayScore(freshScore, sess.ThresholdLimit))
+}
+
+// gaugeTier returns the fuel gauge tier for a budget percentage,
+// or "" below 70%.
+func gaugeTier(pct int) string {
+	switch {
+	case pct >= 90:
+		return state.GaugeTierWarning
+	case pct >= 70:
+		return state.GaugeTierNotice
+	}
+	return ""
+}
+
+// notifyClaude writes a message to stderr and returns exit code 2,
+// which Claude Code requires before it shows PostToolUse stderr to Claude.
+// In observe-only mode it writes nothing and returns 0.
+func notifyClaude(format string, args ...any) int {
+	if isObserveOnly() {
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, format, args...)
+	return 2
 }
@@ -1,6 +1,8 @@
 package hooks
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -193,6 +195,60 @@ func TestGitCommitPattern(t *testing.T) {
 	}
 }
 
+func TestIsGitCommitCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    bool
+	}{
+		// Compound commands: should match
+		{"plain commit", "git commit -m x", true},
+		{"after &&", "npm test && git commit -m x", true},
+		{"after semicolon", "git add . ; git commit -m x", true},
+		{"after ||", "false || git commit -m x", true},
+		{"after newline", "git add .\ngit commit -m x", true},
+		{"before pipe", "git commit -m x | tee log", true},
+		{"env prefix", "GIT_AUTHOR_NAME=bot git commit -m x", true},
+		{"subshell", "(cd sub && git commit -m x)", true},
+		{"separator inside message", `git add . && git commit -m "fix: a; b && c"`, true},
+
+		// Quoted prose: should NOT match
+		{"echo single quotes", "echo 'git commit'", false},
+		{"echo double quotes", `echo "run git commit later"`, false},
+		{"quoted separator then prose", `echo "a && git commit"`, false},
+		{"grep for commit", "git log --oneline | grep commit", false},
+		{"git add only", "git add . && git status", false},
+		{"escaped quote stays quoted", `echo "say \"x\"; git commit"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isGitCommitCommand(tt.command); got != tt.want {
+				t.Errorf("isGitCommitCommand(%q) = %v, want %v", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAmendCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"git commit --amend", true},
+		{"git commit --amend --no-edit", true},
+		{"git add . && git commit --amend -m 'x'", true},
+		{"git commit -m 'x'", false},
+		{`git commit -m "use --amend next time"`, false},
+		{"echo 'git commit --amend'", false},
+	}
+	for _, tt := range tests {
+		if got := isAmendCommand(tt.command); got != tt.want {
+			t.Errorf("isAmendCommand(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
 func TestPostToolUseRouting(t *testing.T) {
 	t.Run("Write routes to file handler", func(t *testing.T) {
 		input := &HookInput{
@@ -368,6 +424,121 @@ func TestHandleBashCommit(t *testing.T) {
 		}
 	})
 
+	t.Run("failed commit leaves baseline alone", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		sessionID := "test-bash-failed-commit"
+		sess, _ := state.New(sessionID, "original-tree", "main", 400)
+		sess.Score = 80
+		sess.LastHead = GetHeadCommit()
+		sess.Save()
+
+		// "nothing to commit": the command ran but HEAD didn't move
+		exec.Command("git", "commit", "-m", "nothing here").Run()
+
+		input := &HookInput{
+			HookEventName: "PostToolUse",
+			ToolName:      "Bash",
+			SessionID:     sessionID,
+			ToolInput:     &ToolInput{Command: "git commit -m 'nothing here'"},
+		}
+		if exitCode := PostToolUse(input); exitCode != 0 {
+			t.Errorf("PostToolUse(failed commit) = %d, want 0", exitCode)
+		}
+
+		reloaded, _ := state.Load(sessionID)
+		if reloaded.BaselineTree != "original-tree" || reloaded.Score != 80 {
+			t.Errorf("baseline = %q, score = %d; want original-tree, 80 (no reset)", reloaded.BaselineTree, reloaded.Score)
+		}
+		if len(reloaded.ResetHistory) != 0 {
+			t.Errorf("ResetHistory has %d entries, want none", len(reloaded.ResetHistory))
+		}
+	})
+
+	t.Run("successful commit resets and records HEAD", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		sessionID := "test-bash-real-commit"
+		sess, _ := state.New(sessionID, "original-tree", "main", 400)
+		sess.Score = 80
+		sess.LastHead = GetHeadCommit()
+		sess.Save()
+
+		os.WriteFile("feature.go", []byte("package main\n"), 0644)
+		exec.Command("git", "add", "feature.go").Run()
+		exec.Command("git", "commit", "-m", "add feature").Run()
+
+		input := &HookInput{
+			HookEventName: "PostToolUse",
+			ToolName:      "Bash",
+			SessionID:     sessionID,
+			ToolInput:     &ToolInput{Command: "git add feature.go && git commit -m 'add feature'"},
+		}
+		if exitCode := PostToolUse(input); exitCode != 2 {
+			t.Errorf("PostToolUse(commit) = %d, want 2", exitCode)
+		}
+
+		reloaded, _ := state.Load(sessionID)
+		if reloaded.BaselineTree != GetHeadTree() || reloaded.Score != 0 {
+			t.Errorf("baseline = %q, score = %d; want HEAD tree, 0", reloaded.BaselineTree, reloaded.Score)
+		}
+		if reloaded.LastHead != GetHeadCommit() {
+			t.Errorf("LastHead = %q, want %q", reloaded.LastHead, GetHeadCommit())
+		}
+	})
+
+	t.Run("amend re-syncs baseline with its own message", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		sessionID := "test-bash-amend"
+		sess, _ := state.New(sessionID, GetHeadTree(), "main", 400)
+		sess.Score = 30
+		sess.LastHead = GetHeadCommit()
+		sess.Save()
+
+		os.WriteFile("fixup.txt", []byte("forgot this\n"), 0644)
+		exec.Command("git", "add", "fixup.txt").Run()
+		exec.Command("git", "commit", "--amend", "--no-edit").Run()
+
+		input := &HookInput{
+			HookEventName: "PostToolUse",
+			ToolName:      "Bash",
+			SessionID:     sessionID,
+			ToolInput:     &ToolInput{Command: "git add fixup.txt && git commit --amend --no-edit"},
+		}
+		var exitCode int
+		stderr := captureStderr(t, func() { exitCode = PostToolUse(input) })
+		if exitCode != 2 {
+			t.Errorf("PostToolUse(amend) = %d, want 2", exitCode)
+		}
+		if !strings.Contains(stderr, "Amended commit — baseline re-synced") {
+			t.Errorf("stderr = %q, want amend message", stderr)
+		}
+
+		reloaded, _ := state.Load(sessionID)
+		if reloaded.BaselineTree != GetHeadTree() {
+			t.Errorf("BaselineTree = %q, want amended HEAD tree %q", reloaded.BaselineTree, GetHeadTree())
+		}
+		if n := len(reloaded.ResetHistory); n != 1 || reloaded.ResetHistory[0].Event != state.ResetEventAmend {
+			t.Errorf("ResetHistory = %+v, want one %q entry", reloaded.ResetHistory, state.ResetEventAmend)
+		}
+	})
+
 	t.Run("missing command fails open", func(t *testing.T) {
 		input := &HookInput{
 			HookEventName: "PostToolUse",
@@ -382,3 +553,310 @@ func TestHandleBashCommit(t *testing.T) {
 		}
 	})
 }
+
+// captureStderr runs fn and returns what it wrote to stderr.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stderr, fn)
+}
+
+// captureFile runs fn with *f redirected to a pipe and returns what fn
+// wrote. The pipe is drained while fn runs, so output bigger than the pipe
+// buffer can't block fn.
+func captureFile(t *testing.T, f **os.File, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	done := make(chan []byte)
+	go func() {
+		output, _ := io.ReadAll(r)
+		r.Close()
+		done <- output
+	}()
+
+	old := *f
+	*f = w
+	defer func() { *f = old }()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+func TestCaptureOutputLargerThanPipeBuffer(t *testing.T) {
+	big := strings.Repeat("x", 1<<20) // Well past a 64 KiB pipe buffer
+	stdout, stderr := captureOutput(t, func() {
+		fmt.Fprint(os.Stdout, big)
+		fmt.Fprint(os.Stderr, big)
+	})
+	if len(stdout) != len(big) || len(stderr) != len(big) {
+		t.Errorf("captured %d stdout / %d stderr bytes, want %d each", len(stdout), len(stderr), len(big))
+	}
+}
+
+func TestPostToolUseExitCodeMatchesStderr(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	// 50 lines in a new file = 50 points
+	os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte(strings.Repeat("line\n", 50)), 0644)
+
+	tests := []struct {
+		name      string
+		threshold int
+		toolName  string
+		command   string
+		wantExit  int
+	}{
+		{"write under 70% is silent", 2000, "Write", "", 0},
+		{"write at 70% notices", 70, "Write", "", 2},
+		{"edit at 90% warns", 50, "Edit", "", 2},
+		{"disabled threshold is silent", 0, "Write", "", 0},
+		{"non-commit bash is silent", 400, "Bash", "git status", 0},
+		{"commit bash reports reset", 400, "Bash", "git commit -m 'test'", 2},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionID := fmt.Sprintf("test-exit-contract-%d", i)
+			sess, err := state.New(sessionID, baseline, "main", tt.threshold)
+			if err != nil {
+				t.Fatalf("state.New: %v", err)
+			}
+			if err := sess.Save(); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			input := &HookInput{HookEventName: "PostToolUse", ToolName: tt.toolName, SessionID: sessionID}
+			if tt.command != "" {
+				input.ToolInput = &ToolInput{Command: tt.command}
+			}
+
+			var exitCode int
+			stderr := captureStderr(t, func() { exitCode = PostToolUse(input) })
+
+			wroteStderr := stderr != ""
+			if wroteStderr != (exitCode == 2) {
+				t.Errorf("exit code %d with stderr %q: want 2 iff stderr written", exitCode, stderr)
+			}
+			if exitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", exitCode, tt.wantExit)
+			}
+		})
+	}
+}
+
+func TestFuelGaugeQuietWindow(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	sess, err := state.New("test-gauge-quiet", baseline, "main", 60)
+	if err != nil {
+		t.Fatalf("state.New: %v", err)
+	}
+	if err := sess.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	input := &HookInput{HookEventName: "PostToolUse", ToolName: "Write", SessionID: "test-gauge-quiet"}
+	target := filepath.Join(tmpDir, "new.txt")
+
+	steps := []struct {
+		name       string
+		lines      int
+		wantPrefix string // "" = suppressed
+	}{
+		{"first notice shows", 50, "NOTICE"},           // 50/60 = 83%
+		{"repeat notice is quiet", 52, ""},             // 52/60 = 86%
+		{"escalation to warning shows", 58, "WARNING"}, // 58/60 = 96%
+		{"repeat warning is quiet", 59, ""},
+	}
+	for _, step := range steps {
+		os.WriteFile(target, []byte(strings.Repeat("line\n", step.lines)), 0644)
+		var exitCode int
+		stderr := captureStderr(t, func() { exitCode = PostToolUse(input) })
+		if step.wantPrefix == "" {
+			if exitCode != 0 || stderr != "" {
+				t.Errorf("%s: exit %d, stderr %q; want suppressed", step.name, exitCode, stderr)
+			}
+			continue
+		}
+		if exitCode != 2 || !strings.HasPrefix(stderr, step.wantPrefix) {
+			t.Errorf("%s: exit %d, stderr %q; want %s", step.name, exitCode, stderr, step.wantPrefix)
+		}
+	}
+}
+
+func TestHandleBashCommitCarryover(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		prevScore     int
+		amend         bool
+		wantCarryover int
+	}{
+		{"default carries nothing", `{}`, 333, false, 0},
+		{"quarter floors", `{"carryover_fraction": 0.25}`, 333, false, 83},
+		{"half", `{"carryover_fraction": 0.5}`, 200, false, 100},
+		{"full keeps score", `{"carryover_fraction": 1}`, 200, false, 200},
+		{"out of range ignored", `{"carryover_fraction": 1.5}`, 200, false, 0},
+		// An amend adds no commit, so its budget really is fresh
+		{"amend carries nothing", `{"carryover_fraction": 0.5}`, 200, true, 0},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			setupTempGitRepo(t, tmpDir)
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+			origDir, _ := os.Getwd()
+			defer os.Chdir(origDir)
+			os.Chdir(tmpDir)
+
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+
+			sessionID := fmt.Sprintf("test-bash-commit-carryover-%d", i)
+			sess, _ := state.New(sessionID, "old-tree-sha", "main", 400)
+			sess.Score = tt.prevScore
+			sess.Save()
+
+			command := "git commit -m 'test commit'"
+			if tt.amend {
+				command = "git commit --amend --no-edit"
+			}
+			msg := captureStderr(t, func() {
+				handleBashCommit(&HookInput{
+					SessionID: sessionID,
+					ToolInput: &ToolInput{Command: command},
+				})
+			})
+			if tt.amend && !strings.Contains(msg, "Amended commit") {
+				t.Errorf("amend message = %q", msg)
+			}
+
+			reloaded, _ := state.Load(sessionID)
+			if reloaded.Carryover != tt.wantCarryover || reloaded.Score != tt.wantCarryover {
+				t.Fatalf("after commit: Carryover = %d, Score = %d, want both %d",
+					reloaded.Carryover, reloaded.Score, tt.wantCarryover)
+			}
+
+			// Fresh scores keep the carry-over on top of new work
+			os.WriteFile(filepath.Join(tmpDir, "new.go"), []byte(strings.Repeat("x\n", 10)), 0644)
+			result := calculateSessionScore(reloaded)
+			if result == nil {
+				t.Fatal("calculateSessionScore() = nil")
+			}
+			if want := tt.wantCarryover + 10; result.Score != want {
+				t.Errorf("fresh Score = %d, want %d (carry-over + 10 new lines)", result.Score, want)
+			}
+		})
+	}
+}
+
+func TestFuelGaugeMinEnforceScore(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	// 55 lines against a 60 pt threshold is a WARNING without a floor
+	os.WriteFile(filepath.Join(tmpDir, "new.txt"), []byte(strings.Repeat("line\n", 55)), 0644)
+
+	tests := []struct {
+		name       string
+		floor      int
+		wantPrefix string // "" = silent
+	}{
+		{"below floor is silent", 100, ""},
+		{"above floor warns", 40, "WARNING"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(filepath.Join(tmpDir, ".bumper-lanes.json"), []byte(fmt.Sprintf(`{"min_enforce_score": %d}`, tt.floor)), 0644)
+			sessionID := fmt.Sprintf("test-gauge-floor-%d", i)
+			sess, _ := state.New(sessionID, baseline, "main", 60)
+			sess.Save()
+
+			input := &HookInput{HookEventName: "PostToolUse", ToolName: "Write", SessionID: sessionID}
+			var exitCode int
+			stderr := captureStderr(t, func() { exitCode = PostToolUse(input) })
+			if tt.wantPrefix == "" {
+				if exitCode != 0 || stderr != "" {
+					t.Errorf("exit %d, stderr %q; want silent", exitCode, stderr)
+				}
+				return
+			}
+			if exitCode != 2 || !strings.HasPrefix(stderr, tt.wantPrefix) {
+				t.Errorf("exit %d, stderr %q; want %s", exitCode, stderr, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestBashCommitAfterExternalHeadMove(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	sessionID := "test-bash-external-head"
+	sess, _ := state.New(sessionID, "original-tree", "main", 400)
+	sess.Score = 80
+	sess.LastHead = GetHeadCommit()
+	sess.Save()
+
+	// The user commits outside the agent, so HEAD no longer matches LastHead
+	os.WriteFile("user.go", []byte("package main\n"), 0644)
+	exec.Command("git", "add", "user.go").Run()
+	exec.Command("git", "commit", "-m", "user commit").Run()
+
+	// The agent's own commit then fails with "nothing to commit"
+	command := "git commit -m 'nothing here'"
+	pre := &HookInput{HookEventName: "PreToolUse", ToolName: "Bash", SessionID: sessionID, ToolInput: &ToolInput{Command: command}}
+	if exitCode := PreToolUse(pre); exitCode != 0 {
+		t.Fatalf("PreToolUse(Bash) = %d, want 0", exitCode)
+	}
+	exec.Command("git", "commit", "-m", "nothing here").Run()
+
+	post := &HookInput{HookEventName: "PostToolUse", ToolName: "Bash", SessionID: sessionID, ToolInput: &ToolInput{Command: command}}
+	if exitCode := PostToolUse(post); exitCode != 0 {
+		t.Errorf("PostToolUse(failed commit) = %d, want 0", exitCode)
+	}
+
+	reloaded, _ := state.Load(sessionID)
+	if reloaded.BaselineTree != "original-tree" || reloaded.Score != 80 || len(reloaded.ResetHistory) != 0 {
+		t.Errorf("baseline = %q, score = %d, resets = %d; want original-tree, 80, 0 (no reset)",
+			reloaded.BaselineTree, reloaded.Score, len(reloaded.ResetHistory))
+	}
+	if reloaded.PreCommitHead != "" {
+		t.Errorf("PreCommitHead = %q, want cleared after PostToolUse", reloaded.PreCommitHead)
+	}
+}
@@ -3,9 +3,9 @@ package hooks
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
-	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
@@ -41,8 +41,6 @@ type HookSpecificOutput struct {
 //
 // Returns exit code 0 for JSON output (even when blocking).
 func PreToolUse(input *HookInput) (exitCode int) {
-	log := logging.New(input.SessionID, "pre_tool_use")
-
 	// Validate hook event
 	if input.HookEventName != "PreToolUse" {
 		return 0
@@ -52,6 +50,9 @@ func PreToolUse(input *HookInput) (exitCode int) {
 	switch input.ToolName {
 	case "Write", "Edit", "MultiEdit", "NotebookEdit":
 		// Proceed with threshold check
+	case "Bash":
+		recordPreCommitHead(input)
+		return 0 // Never blocks
 	default:
 		return 0
 	}
@@ -61,20 +62,32 @@ func PreToolUse(input *HookInput) (exitCode int) {
 		return 0
 	}
 
+	input.SessionID = resolveSessionID(input)
+	log := logging.New(input.SessionID, "pre_tool_use")
+
 	// Load session state
 	sess, err := state.Load(input.SessionID)
 	if err != nil {
 		log.Warn("failed to load session: %v (failing open)", err)
 		return 0 // Fail open
 	}
+	refreshThreshold(sess)
+
+	// Observe only: never block
+	if isObserveOnly() {
+		traceDecision(log, "allow (observe)", sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+		return 0
+	}
 
 	// If paused, allow tool
-	if sess.Paused {
+	if sess.IsPaused(time.Now()) {
+		traceDecision(log, "allow (paused)", sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
 		return 0
 	}
 
 	// If threshold is 0 (disabled), allow tool
 	if sess.ThresholdLimit == 0 {
+		traceDecision(log, "allow (disabled)", sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
 		return 0
 	}
 
@@ -102,8 +115,10 @@ func PreToolUse(input *HookInput) (exitCode int) {
 
 		if currentTree == headTree {
 			// Tree is clean - auto-reset baseline and clear flag
+			traceDecision(log, "auto-reset (clean tree)", sess.BaselineTree, currentTree, sess.Score, sess.ThresholdLimit)
 			currentBranch := GetCurrentBranch()
 			sess.ResetBaseline(currentTree, currentBranch)
+			startCooldown(sess)
 			sess.Save()
 
 			// Provide feedback to user and Claude
@@ -113,17 +128,16 @@ func PreToolUse(input *HookInput) (exitCode int) {
 
 		// Tree is dirty - recalculate score to check if below threshold
 		// This mirrors the Stop hook's auto-recovery logic (stop.go:123-154)
-		stats := getStatsJSON(sess.BaselineTree)
-		if stats == nil {
+		result := calculateSessionScore(sess)
+		if result == nil {
 			log.Warn("failed to get diff stats for auto-recovery (failing open)")
 			return 0 // Fail open
 		}
-
-		result := scoring.Calculate(stats)
 		freshScore := result.Score
 
 		if freshScore <= sess.ThresholdLimit {
 			// Score at or below threshold - auto-recover
+			traceDecision(log, "auto-recover", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
 			sess.SetStopTriggered(false)
 			sess.SetScore(freshScore)
 			sess.Save()
@@ -134,8 +148,8 @@ func PreToolUse(input *HookInput) (exitCode int) {
 			}
 
 			// Provide feedback to user and Claude
-			fmt.Fprintf(os.Stderr, "✓ Threshold auto-recovered: %d/%d pts (%d%%). External changes reduced diff.\n",
-				freshScore, sess.ThresholdLimit, pct)
+			fmt.Fprintf(os.Stderr, "✓ Threshold auto-recovered: %s pts (%d%%). External changes reduced diff.\n",
+				displayScore(freshScore, sess.ThresholdLimit), pct)
 			return 0
 		}
 
@@ -147,10 +161,12 @@ func PreToolUse(input *HookInput) (exitCode int) {
 	// KEY CHECK: Only block if Stop hook has already triggered
 	// This ensures we don't prematurely block before the user sees the threshold warning
 	if !sess.StopTriggered {
+		traceDecision(log, "allow (stop not triggered)", sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
 		return 0
 	}
 
 	// Stop was triggered and not reset - block the tool
+	traceDecision(log, "block", sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
 	pct := 0
 	if sess.ThresholdLimit > 0 {
 		pct = (sess.Score * 100) / sess.ThresholdLimit
@@ -173,6 +189,22 @@ func PreToolUse(input *HookInput) (exitCode int) {
 	return 0 // Exit 0 for JSON output
 }
 
+// recordPreCommitHead saves HEAD before a git commit command runs, so
+// PostToolUse can tell whether that commit moved HEAD even when HEAD also
+// moved outside the agent (a user commit or pull) since the last reset.
+func recordPreCommitHead(input *HookInput) {
+	if input.ToolInput == nil || !isGitCommitCommand(input.ToolInput.Command) || !IsGitRepo() {
+		return
+	}
+	input.SessionID = resolveSessionID(input)
+	sess, err := state.Load(input.SessionID)
+	if err != nil {
+		return // Fail open: PostToolUse falls back to LastHead
+	}
+	sess.PreCommitHead = GetHeadCommit()
+	sess.Save()
+}
+
 // formatBlockReason creates the denial message shown to Claude.
 func formatBlockReason(score, limit, pct int) string {
 	return `Bumper lanes: File modifications blocked.
@@ -187,7 +219,7 @@ The Stop hook has already fired. To continue:
 This prevents unbounded changes without review.`
 }
 
-// formatScore formats the score display.
+// formatScore formats the score display under the configured score_display.
 func formatScore(score, limit, pct int) string {
-	return fmt.Sprintf("%d/%d pts (%d%%)", score, limit, pct)
+	return fmt.Sprintf("%s pts (%d%%)", displayScore(score, limit), pct)
 }
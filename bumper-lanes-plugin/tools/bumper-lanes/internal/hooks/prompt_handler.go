@@ -10,9 +10,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/statusline"
 )
 
 // Command patterns - regex only for commands that need capture groups.
@@ -20,6 +23,10 @@ import (
 var (
 	viewCmdPattern   = regexp.MustCompile(`^/(?:claude-bumper-lanes:)?bumper-view\s*(.*)$`)
 	configCmdPattern = regexp.MustCompile(`^/(?:claude-bumper-lanes:)?bumper-config\s*(.*)$`)
+	tagCmdPattern    = regexp.MustCompile(`^/(?:claude-bumper-lanes:)?bumper-tag\s*(.*)$`)
+	widgetCmdPattern = regexp.MustCompile(`^/(?:claude-bumper-lanes:)?bumper-widget\s*(.*)$`)
+	resetCmdPattern  = regexp.MustCompile(`^/(?:claude-bumper-lanes:)?bumper-reset\s+(.+)$`)
+	pauseCmdPattern  = regexp.MustCompile(`^/(?:claude-bumper-lanes:)?bumper-pause\s+(.+)$`)
 )
 
 // matchCommand checks if prompt matches a bumper-lanes command.
@@ -52,14 +59,29 @@ func HandlePrompt(input *HookInput) int {
 		return 0 // Pass through - not in a git repo
 	}
 
-	sessionID := input.SessionID
+	sessionID := resolveSessionID(input)
 
 	// Simple commands (no args) - use string matching for performance
 	if matchCommand(prompt, "bumper-reset") {
-		return handleReset(sessionID)
+		return handleReset(sessionID, "", false)
+	}
+	if matchCommand(prompt, "bumper-ack") {
+		return handleAck(sessionID)
+	}
+	if matchCommand(prompt, "bumper-undo") {
+		return handleUndo(sessionID)
+	}
+	if matchCommand(prompt, "bumper-info") {
+		return handleSessionInfo(sessionID)
+	}
+	if matchCommand(prompt, "bumper-diff") {
+		return handleDiff(sessionID)
 	}
 	if matchCommand(prompt, "bumper-pause") {
-		return handlePause(sessionID)
+		return handlePause(sessionID, "")
+	}
+	if matchCommand(prompt, "bumper-allow-once") {
+		return handleAllowOnce(sessionID)
 	}
 	if matchCommand(prompt, "bumper-resume") {
 		return handleResume(sessionID)
@@ -72,6 +94,26 @@ func HandlePrompt(input *HookInput) int {
 	if m := configCmdPattern.FindStringSubmatch(prompt); m != nil {
 		return handleConfig(sessionID, strings.TrimSpace(m[1]))
 	}
+	if m := tagCmdPattern.FindStringSubmatch(prompt); m != nil {
+		return handleTag(sessionID, strings.TrimSpace(m[1]))
+	}
+	if m := widgetCmdPattern.FindStringSubmatch(prompt); m != nil {
+		return handleWidget(sessionID, strings.TrimSpace(m[1]))
+	}
+	if m := pauseCmdPattern.FindStringSubmatch(prompt); m != nil {
+		return handlePause(sessionID, strings.TrimSpace(m[1]))
+	}
+	if m := resetCmdPattern.FindStringSubmatch(prompt); m != nil {
+		args, err := parseResetArgs(m[1])
+		if err != nil {
+			blockPrompt(fmt.Sprintf("Invalid /bumper-reset arguments: %v\n%s", err, resetUsage))
+			return 0
+		}
+		if args.soft {
+			return handleAck(sessionID)
+		}
+		return handleReset(sessionID, args.tag, args.confirmed)
+	}
 
 	// Per-mode commands (no-arg = immediate statusline refresh in Claude Code)
 	// Matches diff-viz v2.4.0 modes: tree, smart, sparkline-tree, hotpath, icicle, brackets, gauge, depth, stat
@@ -107,16 +149,66 @@ func HandlePrompt(input *HookInput) int {
 	return 0
 }
 
+// resetUsage lists the /bumper-reset forms, shown when arguments don't parse.
+const resetUsage = "Usage: /bumper-reset [--confirm] [tag] or /bumper-reset --soft"
+
+// resetArgs are parsed /bumper-reset arguments.
+type resetArgs struct {
+	tag       string
+	confirmed bool // --confirm
+	soft      bool // --soft: clear the trip, keep the baseline
+}
+
+// parseResetArgs parses /bumper-reset arguments. Words that aren't flags
+// form the tag. Any other word starting with "-" is an error rather than
+// part of the tag, so a mistyped flag like --sfot can't silently turn into
+// a hard reset; --soft takes no other arguments.
+func parseResetArgs(arg string) (resetArgs, error) {
+	var args resetArgs
+	var words []string
+	for _, w := range strings.Fields(arg) {
+		switch {
+		case w == "--confirm":
+			args.confirmed = true
+		case w == "--soft":
+			args.soft = true
+		case strings.HasPrefix(w, "-"):
+			return resetArgs{}, fmt.Errorf("unknown option %s", w)
+		default:
+			words = append(words, w)
+		}
+	}
+	args.tag = strings.Join(words, " ")
+	if args.soft && (args.confirmed || args.tag != "") {
+		return resetArgs{}, fmt.Errorf("--soft takes no other arguments")
+	}
+	return args, nil
+}
+
 // handleReset captures new baseline and resets score.
-func handleReset(sessionID string) int {
+// The fresh baseline is unnamed unless tag is given. With
+// require_reset_confirmation on, the first reset of a tripped session only
+// asks to be re-issued (or passed --confirm) within resetConfirmWindow.
+func handleReset(sessionID, tag string, confirmed bool) int {
 	sess := loadSessionOrBlock(sessionID)
 	if sess == nil {
 		return 0
 	}
 
-	// Reset score FIRST for immediate statusline update
-	sess.Score = 0
-	sess.StopTriggered = false
+	if resetNeedsConfirmation(sess, confirmed, time.Now()) {
+		if !saveOrBlock(sess) {
+			return 0
+		}
+		blockPrompt(fmt.Sprintf("Threshold tripped (score %s). Review the changes before resetting.\nRe-issue /bumper-reset within %s (or run /bumper-reset --confirm) to reset the baseline.",
+			displayScore(sess.Score, sess.ThresholdLimit), resetConfirmWindow))
+		return 0
+	}
+
+	// Reset score FIRST for immediate statusline update.
+	// Keeps the old baseline for now (records it in reset history for undo).
+	traceDecision(logging.New(sessionID, "user_prompt"), traceManualReset, sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+	sess.ResetBaseline(sess.BaselineTree, "")
+	sess.SetTag(tag)
 	if !saveOrBlock(sess) {
 		return 0
 	}
@@ -134,28 +226,180 @@ func handleReset(sessionID string) int {
 	if branch := GetCurrentBranch(); branch != "" {
 		sess.BaselineBranch = branch
 	}
+	startCooldown(sess)
 	sess.Save() // Best-effort save of baseline
 
-	blockPrompt(fmt.Sprintf("Baseline reset. Score: 0/%d", sess.ThresholdLimit))
+	// Name the new baseline so the user can confirm where they reset to
+	target := shortSHA(newTree)
+	if sess.BaselineBranch != "" {
+		target += " on " + sess.BaselineBranch
+	}
+	if tag != "" {
+		blockPrompt(fmt.Sprintf("Baseline reset for %s to %s. Score: %s", tag, target, displayScore(0, sess.ThresholdLimit)))
+		return 0
+	}
+	blockPrompt(fmt.Sprintf("Baseline reset to %s. Score: %s", target, displayScore(0, sess.ThresholdLimit)))
+	return 0
+}
+
+// handleTag names the current baseline. With no name, shows the current tag.
+func handleTag(sessionID, tag string) int {
+	sess := loadSessionOrBlock(sessionID)
+	if sess == nil {
+		return 0
+	}
+
+	if tag == "" {
+		if sess.Tag == "" {
+			blockPrompt("No tag set. Usage: /bumper-tag <name>")
+		} else {
+			blockPrompt(fmt.Sprintf("Tag: %s", sess.Tag))
+		}
+		return 0
+	}
+
+	sess.SetTag(tag)
+	if !saveOrBlock(sess) {
+		return 0
+	}
+
+	blockPrompt(fmt.Sprintf("Tagged baseline: %s", tag))
+	return 0
+}
+
+// handleWidget sets which status line widget a bare `status` prints for
+// this session. No argument shows the current choice.
+func handleWidget(sessionID, widget string) int {
+	sess := loadSessionOrBlock(sessionID)
+	if sess == nil {
+		return 0
+	}
+
+	if widget == "" {
+		current := sess.Widget
+		if current == "" {
+			current = statusline.WidgetAll
+		}
+		blockPrompt(fmt.Sprintf("Widget: %s. Usage: /bumper-widget <%s|%s|%s>", current,
+			statusline.WidgetAll, statusline.WidgetIndicator, statusline.WidgetDiffTree))
+		return 0
+	}
+	if !statusline.ValidWidget(widget) {
+		blockPrompt(fmt.Sprintf("Unknown widget: %s. Valid: %s, %s, %s", widget,
+			statusline.WidgetAll, statusline.WidgetIndicator, statusline.WidgetDiffTree))
+		return 0
+	}
+
+	sess.SetWidget(widget)
+	if !saveOrBlock(sess) {
+		return 0
+	}
+
+	blockPrompt(fmt.Sprintf("Widget set to: %s", widget))
+	return 0
+}
+
+// handleAck clears the trip without moving the baseline (reset --soft).
+// require_reset_confirmation doesn't apply: the baseline is kept, so the
+// next stop re-trips if the diff is still over.
+func handleAck(sessionID string) int {
+	sess := loadSessionOrBlock(sessionID)
+	if sess == nil {
+		return 0
+	}
+
+	traceDecision(logging.New(sessionID, "user_prompt"), traceAck, sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+	sess.SetStopTriggered(false)
+	if !saveOrBlock(sess) {
+		return 0
+	}
+
+	blockPrompt(fmt.Sprintf("Trip acknowledged. Baseline kept, score: %s\nThe next stop re-trips if still over.", displayScore(sess.Score, sess.ThresholdLimit)))
+	return 0
+}
+
+// handleUndo reverts the most recent baseline reset.
+func handleUndo(sessionID string) int {
+	sess := loadSessionOrBlock(sessionID)
+	if sess == nil {
+		return 0
+	}
+
+	if _, err := sess.UndoReset(); err != nil {
+		blockPrompt("Nothing to undo: no baseline resets recorded.")
+		return 0
+	}
+	traceDecision(logging.New(sessionID, "user_prompt"), traceUndoAction(sess.StopTriggered), sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+	if !saveOrBlock(sess) {
+		return 0
+	}
+
+	blockPrompt(fmt.Sprintf("Reset undone. Score: %s", displayScore(sess.Score, sess.ThresholdLimit)))
+	return 0
+}
+
+// handleSessionInfo shows session details including time since last reset.
+func handleSessionInfo(sessionID string) int {
+	sess := loadSessionOrBlock(sessionID)
+	if sess == nil {
+		return 0
+	}
+
+	blockPrompt(strings.TrimRight(formatSessionInfo(sess, time.Now()), "\n"))
 	return 0
 }
 
 // handlePause disables threshold enforcement.
-func handlePause(sessionID string) int {
+// With a duration (e.g. "30m") enforcement auto-resumes after it; without, until /bumper-resume.
+func handlePause(sessionID, duration string) int {
+	var d time.Duration
+	if duration != "" {
+		var err error
+		if d, err = time.ParseDuration(duration); err != nil || d <= 0 {
+			blockPrompt(fmt.Sprintf("Invalid pause duration %q. Use e.g. /bumper-pause 30m or /bumper-pause 2h", duration))
+			return 0
+		}
+	}
+
 	sess := loadSessionOrBlock(sessionID)
 	if sess == nil {
 		return 0
 	}
 
-	sess.SetPaused(true)
+	if d > 0 {
+		sess.PauseFor(d, time.Now())
+	} else {
+		sess.SetPaused(true)
+	}
 	if !saveOrBlock(sess) {
 		return 0
 	}
 
+	if d > 0 {
+		blockPrompt(fmt.Sprintf("Enforcement paused for %s. Changes still tracked.\nAuto-resumes then, or use /bumper-resume sooner.", d))
+		return 0
+	}
 	blockPrompt("Enforcement paused. Changes still tracked.\nUse /bumper-resume to re-enable.")
 	return 0
 }
 
+// handleAllowOnce lets the next Stop pass regardless of score.
+// Unlike pause, enforcement resumes on the Stop after that.
+func handleAllowOnce(sessionID string) int {
+	sess := loadSessionOrBlock(sessionID)
+	if sess == nil {
+		return 0
+	}
+
+	sess.SetAllowOnce(true)
+	if !saveOrBlock(sess) {
+		return 0
+	}
+
+	blockPrompt(fmt.Sprintf("Next stop over threshold will be allowed (currently %s).\nEnforcement resumes after that.", displayScore(sess.Score, sess.ThresholdLimit)))
+	return 0
+}
+
 // handleResume re-enables threshold enforcement.
 func handleResume(sessionID string) int {
 	sess := loadSessionOrBlock(sessionID)
@@ -168,23 +412,31 @@ func handleResume(sessionID string) int {
 		return 0
 	}
 
-	blockPrompt(fmt.Sprintf("Enforcement resumed. Score: %d/%d", sess.Score, sess.ThresholdLimit))
+	blockPrompt(fmt.Sprintf("Enforcement resumed. Score: %s", displayScore(sess.Score, sess.ThresholdLimit)))
+	return 0
+}
+
+// handleDiff shows the current diff visualization at the session's view mode.
+// Rendered without color since the reason text is shown as plain output.
+func handleDiff(sessionID string) int {
+	blockPrompt(renderSessionDiff(sessionID, false, config.LoadCountUntracked()))
 	return 0
 }
 
 // handleView sets or shows the visualization mode.
+// Setting a mode also saves it as the repo default in .bumper-lanes.json.
 // Note: /bumper-view <mode> won't trigger immediate statusline refresh due to Claude Code bug.
 // Use per-mode commands (/bumper-tree, /bumper-icicle, etc.) for instant updates.
 func handleView(sessionID, mode string) int {
 	if mode == "" {
 		// Show current mode + hint
 		currentMode := config.LoadViewMode()
-		blockPrompt(fmt.Sprintf("Current: %s\nModes: %s", currentMode, config.ValidModes))
+		blockPrompt(fmt.Sprintf("Current: %s\nModes: %s", currentMode, strings.Join(config.Modes(), " ")))
 		return 0
 	}
 
 	// Validate mode before loading session
-	validModes := strings.Fields(config.ValidModes)
+	validModes := config.Modes()
 	isValid := false
 	for _, v := range validModes {
 		if mode == v {
@@ -193,7 +445,7 @@ func handleView(sessionID, mode string) int {
 		}
 	}
 	if !isValid {
-		blockPrompt(fmt.Sprintf("Invalid mode: %s\nValid modes: %s", mode, config.ValidModes))
+		blockPrompt(fmt.Sprintf("Invalid mode: %s\nValid modes: %s", mode, strings.Join(config.Modes(), " ")))
 		return 0
 	}
 
@@ -209,7 +461,10 @@ func handleView(sessionID, mode string) int {
 	}
 
 	// Persist to config for future sessions
-	_ = config.SaveConfig(config.Config{DefaultViewMode: mode})
+	if err := config.SaveConfig(config.Config{DefaultViewMode: mode}); err != nil {
+		blockPrompt(fmt.Sprintf("View mode set to: %s (session only - config save failed: %v)", mode, err))
+		return 0
+	}
 
 	blockPrompt(fmt.Sprintf("View mode set to: %s", mode))
 	return 0
@@ -218,6 +473,8 @@ func handleView(sessionID, mode string) int {
 // handleViewMode sets view mode via no-arg command (triggers immediate statusline refresh).
 // This exists because Claude Code only refreshes statusline for no-arg commands.
 // Also forces diff-viz to show for this session (overrides config.show_diff_viz=false).
+// Session-only: quick mode switching never touches .bumper-lanes.json.
+// Use /bumper-view <mode> to persist a default.
 func handleViewMode(sessionID, mode string) int {
 	sess := loadSessionOrBlock(sessionID)
 	if sess == nil {
@@ -230,8 +487,7 @@ func handleViewMode(sessionID, mode string) int {
 		return 0
 	}
 
-	_ = config.SaveConfig(config.Config{DefaultViewMode: mode})
-	blockPrompt(fmt.Sprintf("View: %s", mode))
+	blockPrompt(fmt.Sprintf("View: %s (this session; /bumper-view %s saves it as default)", mode, mode))
 	return 0
 }
 
@@ -239,14 +495,8 @@ func handleViewMode(sessionID, mode string) int {
 func handleConfig(sessionID, args string) int {
 	if args == "" {
 		// Show current config
-		threshold := config.LoadThreshold()
-		viewMode := config.LoadViewMode()
-		configPath := config.GetConfigPath()
-
-		source := "default"
-		if configPath != "" && (threshold != config.DefaultThreshold || threshold == 0) {
-			source = configPath
-		}
+		threshold, thresholdSource := config.LoadThresholdWithSource()
+		viewMode, viewModeSource := config.LoadViewModeWithSource()
 
 		var thresholdStr string
 		if threshold == 0 {
@@ -254,14 +504,37 @@ func handleConfig(sessionID, args string) int {
 		} else {
 			thresholdStr = fmt.Sprintf("%d points", threshold)
 		}
-		blockPrompt(fmt.Sprintf("Threshold: %s\nView mode: %s\nSource: %s", thresholdStr, viewMode, source))
+		blockPrompt(fmt.Sprintf("Threshold: %s (%s)\nView mode: %s (%s)", thresholdStr, thresholdSource, viewMode, viewModeSource))
 		return 0
 	}
 
+	if key, ok := strings.CutPrefix(args, "unset "); ok {
+		return unsetConfig(sessionID, strings.TrimSpace(key))
+	}
+
 	// Direct number sets config
 	return setThreshold(sessionID, args)
 }
 
+// unsetConfig removes a key from .bumper-lanes.json.
+// Unsetting threshold re-applies the fallback to the current session.
+func unsetConfig(sessionID, key string) int {
+	if err := config.UnsetConfig(key); err != nil {
+		blockPrompt(fmt.Sprintf("Error: %v\nKeys: %s", err, strings.Join(config.ConfigKeys(), ", ")))
+		return 0
+	}
+
+	if key == "threshold" {
+		if sess := loadSessionOrBlock(sessionID); sess != nil {
+			sess.ThresholdLimit = config.LoadThreshold()
+			sess.Save()
+		}
+	}
+
+	blockPrompt(fmt.Sprintf("Unset %s. Using fallback from global config or default.", key))
+	return 0
+}
+
 // setThreshold parses and saves threshold value to .bumper-lanes.json.
 // Accepts 0 (disabled) or 50-2000 (active threshold).
 func setThreshold(sessionID, valStr string) int {
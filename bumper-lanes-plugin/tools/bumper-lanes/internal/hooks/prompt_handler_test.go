@@ -2,9 +2,14 @@ package hooks
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
 // TestGetBumperLanesBinPath verifies path detection works.
@@ -171,3 +176,230 @@ func TestHandlePromptNonGitRepo(t *testing.T) {
 		t.Errorf("HandlePrompt in non-git repo produced output: %q, want empty (pass through)", output)
 	}
 }
+
+// TestViewModePersistence verifies per-mode commands are session-only while
+// /bumper-view <mode> also saves the repo default.
+func TestViewModePersistence(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	sessionID := "test-view-persist"
+	sess, _ := state.New(sessionID, "tree-sha", "main", 400)
+	sess.Save()
+
+	configPath := filepath.Join(tmpDir, ".bumper-lanes.json")
+	handle := func(prompt string) {
+		captureOutput(t, func() { HandlePrompt(&HookInput{SessionID: sessionID, UserPrompt: prompt}) })
+	}
+
+	t.Run("per-mode command is session-only", func(t *testing.T) {
+		handle("/bumper-icicle")
+
+		if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+			data, _ := os.ReadFile(configPath)
+			t.Errorf("/bumper-icicle wrote .bumper-lanes.json: %s", data)
+		}
+		if got, _ := state.Load(sessionID); got.ViewMode != "icicle" {
+			t.Errorf("session ViewMode = %q, want icicle", got.ViewMode)
+		}
+	})
+
+	t.Run("bumper-view persists the default", func(t *testing.T) {
+		handle("/bumper-view hotpath")
+
+		if got := config.LoadViewMode(); got != "hotpath" {
+			t.Errorf("config.LoadViewMode() = %q, want hotpath", got)
+		}
+		if got, _ := state.Load(sessionID); got.ViewMode != "hotpath" {
+			t.Errorf("session ViewMode = %q, want hotpath", got.ViewMode)
+		}
+	})
+}
+
+func TestViewReportsConfigSaveFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// YAML repo configs are read-only for bumper-lanes, so SaveConfig fails
+	os.WriteFile(filepath.Join(tmpDir, ".bumper-lanes.yaml"), []byte("threshold: 400\n"), 0644)
+
+	sessionID := "test-view-save-fail"
+	sess, _ := state.New(sessionID, "tree-sha", "main", 400)
+	sess.Save()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	HandlePrompt(&HookInput{SessionID: sessionID, UserPrompt: "/bumper-view hotpath"})
+	w.Close()
+	os.Stdout = oldStdout
+
+	var resp UserPromptResponse
+	if err := json.NewDecoder(r).Decode(&resp); err != nil {
+		t.Fatalf("decode block response: %v", err)
+	}
+	if !strings.Contains(resp.Reason, "session only - config save failed") || !strings.Contains(resp.Reason, "read-only") {
+		t.Errorf("reason = %q, want the config save failure", resp.Reason)
+	}
+	if got, _ := state.Load(sessionID); got.ViewMode != "hotpath" {
+		t.Errorf("session ViewMode = %q, want hotpath despite the save failure", got.ViewMode)
+	}
+}
+
+func TestTagCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	sessionID := "test-tag"
+	sess, _ := state.New(sessionID, GetHeadTree(), "main", 400)
+	sess.Save()
+
+	handle := func(prompt string) {
+		captureOutput(t, func() { HandlePrompt(&HookInput{SessionID: sessionID, UserPrompt: prompt}) })
+	}
+
+	t.Run("tag persists", func(t *testing.T) {
+		handle("/bumper-tag auth refactor")
+		if got, _ := state.Load(sessionID); got.Tag != "auth refactor" {
+			t.Errorf("Tag = %q, want %q", got.Tag, "auth refactor")
+		}
+	})
+
+	t.Run("plain reset clears the tag", func(t *testing.T) {
+		handle("/bumper-reset")
+		if got, _ := state.Load(sessionID); got.Tag != "" {
+			t.Errorf("Tag = %q after reset, want empty", got.Tag)
+		}
+	})
+
+	t.Run("reset with a tag names the new baseline", func(t *testing.T) {
+		handle("/claude-bumper-lanes:bumper-reset billing")
+		got, _ := state.Load(sessionID)
+		if got.Tag != "billing" {
+			t.Errorf("Tag = %q, want billing", got.Tag)
+		}
+		if got.Score != 0 || len(got.ResetHistory) != 2 {
+			t.Errorf("Score = %d, resets = %d; want a fresh baseline", got.Score, len(got.ResetHistory))
+		}
+	})
+
+	t.Run("mistyped flag is rejected, not used as a tag", func(t *testing.T) {
+		sess, _ := state.Load(sessionID)
+		sess.SetScore(120)
+		sess.Save()
+		out, _ := captureOutput(t, func() {
+			HandlePrompt(&HookInput{SessionID: sessionID, UserPrompt: "/bumper-reset --sfot"})
+		})
+		if !strings.Contains(out, "unknown option --sfot") {
+			t.Errorf("output = %q, want an unknown option error", out)
+		}
+		if got, _ := state.Load(sessionID); got.Score != 120 || got.Tag != "billing" || len(got.ResetHistory) != 2 {
+			t.Errorf("Score = %d, tag = %q, resets = %d; want the session untouched", got.Score, got.Tag, len(got.ResetHistory))
+		}
+	})
+}
+
+func TestParseResetArgs(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    resetArgs
+		wantErr bool
+	}{
+		{"billing", resetArgs{tag: "billing"}, false},
+		{"--confirm auth refactor", resetArgs{tag: "auth refactor", confirmed: true}, false},
+		{"  --soft ", resetArgs{soft: true}, false},
+		{"--sfot", resetArgs{}, true},
+		{"-f", resetArgs{}, true},
+		{"--soft billing", resetArgs{}, true},
+		{"--soft --confirm", resetArgs{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parseResetArgs(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseResetArgs(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseResetArgs(%q) = %+v, want %+v", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWidgetCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	sessionID := "test-widget"
+	sess, _ := state.New(sessionID, GetHeadTree(), "main", 400)
+	sess.Save()
+
+	tests := []struct {
+		prompt     string
+		wantWidget string
+	}{
+		{"/bumper-widget indicator", "indicator"},
+		{"/bumper-widget sideways", "indicator"}, // Invalid: unchanged
+		{"/claude-bumper-lanes:bumper-widget diff-tree", "diff-tree"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prompt, func(t *testing.T) {
+			captureOutput(t, func() { HandlePrompt(&HookInput{SessionID: sessionID, UserPrompt: tt.prompt}) })
+
+			if got, _ := state.Load(sessionID); got.Widget != tt.wantWidget {
+				t.Errorf("Widget = %q, want %q", got.Widget, tt.wantWidget)
+			}
+		})
+	}
+}
+
+func TestResetConfirmsNewBaseline(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	sessionID := "test-reset-confirm"
+	sess, _ := state.New(sessionID, "old-tree-sha", "main", 400)
+	sess.Save()
+
+	os.WriteFile(filepath.Join(tmpDir, "work.go"), []byte("package main\n"), 0644)
+
+	out, _ := captureOutput(t, func() {
+		HandlePrompt(&HookInput{SessionID: sessionID, UserPrompt: "/bumper-reset"})
+	})
+
+	var resp UserPromptResponse
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("decode block response: %v", err)
+	}
+
+	got, _ := state.Load(sessionID)
+	want := "Baseline reset to " + shortSHA(got.BaselineTree) + " on main."
+	if !strings.Contains(resp.Reason, want) {
+		t.Errorf("reason = %q, want it to contain %q", resp.Reason, want)
+	}
+}
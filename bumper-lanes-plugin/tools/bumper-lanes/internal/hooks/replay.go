@@ -0,0 +1,139 @@
+package hooks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// replayTextPattern matches a text-format session log line:
+// "[2025-12-27 09:56:28] [DEBUG] [stop] message".
+var replayTextPattern = regexp.MustCompile(`^\[([^\]]+)\] \[[A-Z]+\] \[([^\]]+)\] (.*)$`)
+
+// replayDecisionPattern matches the message written by traceDecision.
+// The action and current tree may contain spaces ("allow (paused)", "(not captured)").
+var replayDecisionPattern = regexp.MustCompile(`^decision=(.+) baseline=\S* current=.+ score=(-?\d+) threshold=(-?\d+)$`)
+
+// ReplayEvent is one decision recovered from a session log.
+type ReplayEvent struct {
+	Time      string
+	Source    string
+	Action    string
+	Score     int
+	Threshold int
+}
+
+// ReplayState is the session state re-derived by replaying decisions in order.
+type ReplayState struct {
+	Score     int
+	Threshold int
+	Tripped   bool
+	Resets    int
+	Events    int
+}
+
+// Replay prints a score/trip timeline reconstructed from a session log.
+// Decisions are only traced with BUMPER_LANES_DEBUG=1; other, partial, or
+// corrupt lines are skipped, so a truncated or rotated log still replays.
+func Replay(path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	events := parseReplayEvents(f)
+	if len(events) == 0 {
+		fmt.Fprintf(w, "No decision traces in %s (hooks log them when BUMPER_LANES_DEBUG=1)\n", path)
+		return nil
+	}
+
+	var st ReplayState
+	for _, ev := range events {
+		st.apply(ev)
+		fmt.Fprintf(w, "%s  %-13s %-35s %d/%d%s\n", ev.Time, ev.Source, ev.Action, st.Score, st.Threshold, trippedMark(st.Tripped))
+	}
+	fmt.Fprintf(w, "\nFinal: score %d/%d, tripped: %v, resets: %d, events: %d\n", st.Score, st.Threshold, st.Tripped, st.Resets, st.Events)
+	return nil
+}
+
+// parseReplayEvents reads decision traces from a session log in either
+// text or JSON (BUMPER_LANES_LOG_FORMAT=json) format, skipping everything else.
+func parseReplayEvents(r io.Reader) []ReplayEvent {
+	var events []ReplayEvent
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if ev, ok := parseReplayLine(scanner.Text()); ok {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// parseReplayLine extracts a decision from one log line.
+func parseReplayLine(line string) (ReplayEvent, bool) {
+	var ts, source, msg string
+	if strings.HasPrefix(line, "{") {
+		var entry struct {
+			Timestamp string `json:"ts"`
+			Source    string `json:"source"`
+			Message   string `json:"msg"`
+		}
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			return ReplayEvent{}, false
+		}
+		ts, source, msg = entry.Timestamp, entry.Source, entry.Message
+	} else {
+		m := replayTextPattern.FindStringSubmatch(line)
+		if m == nil {
+			return ReplayEvent{}, false
+		}
+		ts, source, msg = m[1], m[2], m[3]
+	}
+
+	m := replayDecisionPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return ReplayEvent{}, false
+	}
+	score, _ := strconv.Atoi(m[2])
+	threshold, _ := strconv.Atoi(m[3])
+	return ReplayEvent{Time: ts, Source: source, Action: m[1], Score: score, Threshold: threshold}, true
+}
+
+// apply advances the state by one decision.
+// Reset traces log the pre-reset score, so resets bring the score to 0.
+// Undo traces log the restored score and whether the restored state was tripped.
+func (s *ReplayState) apply(ev ReplayEvent) {
+	s.Events++
+	s.Score = ev.Score
+	s.Threshold = ev.Threshold
+	switch {
+	case ev.Action == "block":
+		s.Tripped = true
+	case ev.Action == "auto-recover":
+		s.Tripped = false
+	case strings.HasPrefix(ev.Action, "auto-reset"), ev.Action == traceManualReset:
+		s.Tripped = false
+		s.Score = 0
+		s.Resets++
+	case ev.Action == traceAck:
+		s.Tripped = false
+	case strings.HasPrefix(ev.Action, traceUndo):
+		s.Tripped = ev.Action != traceUndo
+		s.Resets = max(0, s.Resets-1)
+	}
+}
+
+// trippedMark flags timeline rows where the session is tripped.
+func trippedMark(tripped bool) string {
+	if tripped {
+		return "  TRIPPED"
+	}
+	return ""
+}
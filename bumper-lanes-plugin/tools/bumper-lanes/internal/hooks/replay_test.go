@@ -0,0 +1,147 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestReplay(t *testing.T) {
+	// Mixed text and JSON lines, non-decision noise, a multiline message,
+	// and a line truncated mid-write (as after a crash or rotation)
+	log := `[2026-01-05 10:00:00] [INFO] [session_start] captured baseline
+[2026-01-05 10:01:00] [DEBUG] [post_tool_use] decision=allow baseline=aaa current=bbb score=120 threshold=400
+[2026-01-05 10:02:00] [DEBUG] [stop] decision=block baseline=aaa current=ccc score=450 threshold=400
+[2026-01-05 10:02:30] [WARN] [stop]
+multiline
+warning
+{"ts":"2026-01-05T10:03:00Z","level":"DEBUG","source":"pre_tool_use","session":"s","msg":"decision=allow (stop not triggered) baseline=aaa current=(not captured) score=450 threshold=400"}
+[2026-01-05 10:04:00] [DEBUG] [post_tool_use] decision=auto-reset (commit) baseline=aaa current=ddd score=450 threshold=400
+[2026-01-05 10:05:00] [DEBUG] [stop] decision=allow baseline=ddd current=eee score=80 threshold=400
+[2026-01-05 10:06:00] [DEBUG] [stop] decision=block baseline=ddd current=fff score=410 thresh
+`
+
+	t.Run("reconstructs final state", func(t *testing.T) {
+		events := parseReplayEvents(strings.NewReader(log))
+		if len(events) != 5 {
+			t.Fatalf("parsed %d events, want 5: %+v", len(events), events)
+		}
+		if events[2].Action != "allow (stop not triggered)" || events[2].Source != "pre_tool_use" {
+			t.Errorf("JSON line parsed as %+v", events[2])
+		}
+
+		var st ReplayState
+		trippedAt := make([]bool, len(events))
+		for i, ev := range events {
+			st.apply(ev)
+			trippedAt[i] = st.Tripped
+		}
+		want := ReplayState{Score: 80, Threshold: 400, Tripped: false, Resets: 1, Events: 5}
+		if st != want {
+			t.Errorf("final state = %+v, want %+v", st, want)
+		}
+		if !trippedAt[1] || !trippedAt[2] || trippedAt[3] {
+			t.Errorf("tripped timeline = %v, want trip at block cleared by the commit reset", trippedAt)
+		}
+	})
+
+	t.Run("prints timeline", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "session-s.log")
+		os.WriteFile(path, []byte(log), 0644)
+
+		var buf bytes.Buffer
+		if err := Replay(path, &buf); err != nil {
+			t.Fatalf("Replay() error = %v", err)
+		}
+		got := buf.String()
+		if !strings.Contains(got, "450/400  TRIPPED") {
+			t.Errorf("timeline missing tripped row:\n%s", got)
+		}
+		if !strings.HasSuffix(got, "Final: score 80/400, tripped: false, resets: 1, events: 5\n") {
+			t.Errorf("unexpected summary:\n%s", got)
+		}
+	})
+
+	t.Run("log without traces", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "session-empty.log")
+		os.WriteFile(path, []byte("[2026-01-05 10:00:00] [INFO] [stop] hello\n"), 0644)
+
+		var buf bytes.Buffer
+		if err := Replay(path, &buf); err != nil {
+			t.Fatalf("Replay() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), "No decision traces") {
+			t.Errorf("expected no-traces note, got %q", buf.String())
+		}
+	})
+
+	t.Run("missing log", func(t *testing.T) {
+		if err := Replay(filepath.Join(t.TempDir(), "nope.log"), &bytes.Buffer{}); err == nil {
+			t.Error("Replay() on a missing file returned nil error")
+		}
+	})
+}
+
+func TestReplayManualActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []string
+		want    ReplayState
+	}{
+		{"manual reset clears the trip", []string{"block", traceManualReset},
+			ReplayState{Score: 0, Threshold: 400, Tripped: false, Resets: 1, Events: 2}},
+		{"ack clears the trip and keeps the score", []string{"block", traceAck},
+			ReplayState{Score: 450, Threshold: 400, Tripped: false, Resets: 0, Events: 2}},
+		{"undo restores the trip", []string{"block", traceManualReset, traceUndoAction(true)},
+			ReplayState{Score: 450, Threshold: 400, Tripped: true, Resets: 0, Events: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var st ReplayState
+			for _, action := range tt.actions {
+				st.apply(ReplayEvent{Action: action, Score: 450, Threshold: 400})
+			}
+			if st != tt.want {
+				t.Errorf("state = %+v, want %+v", st, tt.want)
+			}
+		})
+	}
+
+	t.Run("block then /bumper-reset from a real log", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		logDir := t.TempDir()
+		t.Setenv("BUMPER_LANES_LOG_DIR", logDir)
+		t.Setenv("BUMPER_LANES_DEBUG", "1")
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		baseline, err := CaptureTree()
+		if err != nil {
+			t.Fatalf("CaptureTree: %v", err)
+		}
+		sess, _ := state.New("test-replay-reset", baseline, "main", 50)
+		sess.Save()
+		os.WriteFile("work.txt", []byte(strings.Repeat("x\n", 120)), 0644)
+
+		captureOutput(t, func() {
+			Stop(&HookInput{SessionID: "test-replay-reset", HookEventName: "Stop"})
+			HandlePrompt(&HookInput{SessionID: "test-replay-reset", UserPrompt: "/bumper-reset"})
+		})
+
+		var buf bytes.Buffer
+		if err := Replay(filepath.Join(logDir, "session-test-replay-reset.log"), &buf); err != nil {
+			t.Fatalf("Replay() error = %v", err)
+		}
+		if got := buf.String(); !strings.HasSuffix(got, "Final: score 0/50, tripped: false, resets: 1, events: 2\n") {
+			t.Errorf("unexpected replay:\n%s", got)
+		}
+	})
+}
@@ -2,19 +2,51 @@ package hooks
 
 import (
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
+// resetConfirmWindow is how long a reset awaiting confirmation
+// (require_reset_confirmation) can be confirmed by re-issuing it.
+const resetConfirmWindow = 2 * time.Minute
+
+// resetNeedsConfirmation reports whether a hard reset of sess has to ask
+// first under require_reset_confirmation: the session is tripped, the reset
+// isn't confirmed, and no earlier request is still pending. When it does,
+// the request is recorded on sess (unsaved), so re-issuing the reset within
+// resetConfirmWindow goes through.
+func resetNeedsConfirmation(sess *state.SessionState, confirmed bool, now time.Time) bool {
+	if !sess.StopTriggered || confirmed || !config.LoadRequireResetConfirmation() ||
+		sess.ResetConfirmationPending(now, resetConfirmWindow) {
+		return false
+	}
+	sess.RequestResetConfirmation(now)
+	return true
+}
+
 // Reset handles the reset user command.
-// It captures a new baseline and resets the accumulated score.
-func Reset(sessionID string) error {
+// It captures a new baseline and resets the accumulated score. Under
+// require_reset_confirmation a tripped session is only reset when confirmed
+// or re-run within resetConfirmWindow, as with /bumper-reset.
+func Reset(sessionID string, confirmed bool) error {
 	// Load session state
 	sess, err := state.Load(sessionID)
 	if err != nil {
 		return fmt.Errorf("no session state for %s", sessionID)
 	}
 
+	if resetNeedsConfirmation(sess, confirmed, time.Now()) {
+		if err := sess.Save(); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+		return fmt.Errorf("threshold tripped (score %s): review the changes, then re-run within %s or pass --confirm",
+			displayScore(sess.Score, sess.ThresholdLimit), resetConfirmWindow)
+	}
+
 	// Capture new baseline tree
 	newTree, err := CaptureTree()
 	if err != nil {
@@ -25,7 +57,9 @@ func Reset(sessionID string) error {
 	currentBranch := GetCurrentBranch()
 
 	// Reset baseline
+	traceDecision(logging.New(sessionID, "cli"), traceManualReset, sess.BaselineTree, newTree, sess.Score, sess.ThresholdLimit)
 	sess.ResetBaseline(newTree, currentBranch)
+	startCooldown(sess)
 
 	// Save state
 	if err := sess.Save(); err != nil {
@@ -35,3 +69,84 @@ func Reset(sessionID string) error {
 	fmt.Printf("Baseline reset. New tree: %s\n", newTree[:12])
 	return nil
 }
+
+// SoftReset handles reset --soft: it acknowledges a trip without moving
+// the baseline. Score keeps accumulating against the original baseline,
+// so the next Stop re-trips if the diff is still over threshold. Like
+// /bumper-reset --soft, it never asks for confirmation.
+func SoftReset(sessionID string) error {
+	sess, err := state.Load(sessionID)
+	if err != nil {
+		return fmt.Errorf("no session state for %s", sessionID)
+	}
+
+	traceDecision(logging.New(sessionID, "cli"), traceAck, sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+	sess.SetStopTriggered(false)
+
+	if err := sess.Save(); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+
+	fmt.Printf("Trip acknowledged. Baseline kept, score: %s\n", displayScore(sess.Score, sess.ThresholdLimit))
+	return nil
+}
+
+// ResetAll handles the session-reset-all user command. By default it
+// soft-resets every session in the checkpoint dir, clearing trips and
+// keeping baselines. With full, it moves every session's baseline to the
+// current tree like Reset, including its confirmation step: tripped
+// sessions that still need confirming are skipped and reported. Returns an
+// error only if nothing can be read; sessions that fail to load or save are
+// skipped and not counted.
+func ResetAll(w io.Writer, full, confirmed bool) error {
+	sessions, err := state.LoadAll()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var newTree, currentBranch string
+	if full && len(sessions) > 0 {
+		if newTree, err = CaptureTree(); err != nil {
+			return fmt.Errorf("failed to capture tree: %w", err)
+		}
+		currentBranch = GetCurrentBranch()
+	}
+
+	affected, unconfirmed := 0, 0
+	now := time.Now()
+	for _, listed := range sessions {
+		// Reload so Save merges with concurrent hook writes
+		sess, err := state.Load(listed.SessionID)
+		if err != nil {
+			continue
+		}
+		log := logging.New(sess.SessionID, "cli")
+		if full && resetNeedsConfirmation(sess, confirmed, now) {
+			sess.Save()
+			unconfirmed++
+			continue
+		} else if full {
+			traceDecision(log, traceManualReset, sess.BaselineTree, newTree, sess.Score, sess.ThresholdLimit)
+			sess.ResetBaseline(newTree, currentBranch)
+			startCooldown(sess)
+		} else if sess.StopTriggered {
+			traceDecision(log, traceAck, sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+			sess.SetStopTriggered(false)
+		} else {
+			continue
+		}
+		if sess.Save() == nil {
+			affected++
+		}
+	}
+
+	if full {
+		fmt.Fprintf(w, "Baseline reset for %d of %d sessions\n", affected, len(sessions))
+		if unconfirmed > 0 {
+			fmt.Fprintf(w, "%d tripped sessions skipped: review the changes, then re-run within %s or pass --confirm\n", unconfirmed, resetConfirmWindow)
+		}
+	} else {
+		fmt.Fprintf(w, "Trip cleared for %d of %d sessions\n", affected, len(sessions))
+	}
+	return nil
+}
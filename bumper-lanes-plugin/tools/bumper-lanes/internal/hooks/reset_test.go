@@ -0,0 +1,284 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestSoftReset(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	trip := func(sessionID string) {
+		sess, _ := state.New(sessionID, "original-tree", "main", 400)
+		sess.SetScore(520)
+		sess.SetStopTriggered(true)
+		sess.Save()
+	}
+	check := func(t *testing.T, sessionID string) {
+		t.Helper()
+		got, _ := state.Load(sessionID)
+		if got.StopTriggered {
+			t.Error("StopTriggered = true, want trip cleared")
+		}
+		if got.BaselineTree != "original-tree" {
+			t.Errorf("BaselineTree = %q, want original-tree (unchanged)", got.BaselineTree)
+		}
+		if got.Score != 520 {
+			t.Errorf("Score = %d, want 520 (unchanged)", got.Score)
+		}
+		if len(got.ResetHistory) != 0 {
+			t.Errorf("ResetHistory has %d entries, want none", len(got.ResetHistory))
+		}
+	}
+
+	t.Run("reset --soft", func(t *testing.T) {
+		trip("test-soft-reset")
+		if err := SoftReset("test-soft-reset"); err != nil {
+			t.Fatalf("SoftReset() error = %v", err)
+		}
+		check(t, "test-soft-reset")
+	})
+
+	for _, prompt := range []string{"/bumper-ack", "/bumper-reset --soft"} {
+		t.Run(prompt, func(t *testing.T) {
+			trip("test-ack")
+			captureOutput(t, func() { HandlePrompt(&HookInput{SessionID: "test-ack", UserPrompt: prompt}) })
+			check(t, "test-ack")
+		})
+	}
+}
+
+func TestResetRequiresConfirmation(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	os.WriteFile(filepath.Join(tmpDir, ".bumper-lanes.json"), []byte(`{"require_reset_confirmation": true}`), 0644)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	const sessionID = "test-reset-confirmation"
+	trip := func(t *testing.T) {
+		t.Helper()
+		sess, _ := state.New(sessionID, "original-tree", "main", 400)
+		sess.SetScore(520)
+		sess.SetStopTriggered(true)
+		if err := sess.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handle := func(t *testing.T, prompt string) string {
+		t.Helper()
+		out, _ := captureOutput(t, func() {
+			HandlePrompt(&HookInput{SessionID: sessionID, UserPrompt: prompt})
+		})
+		var resp UserPromptResponse
+		json.Unmarshal([]byte(out), &resp)
+		return resp.Reason
+	}
+	wasReset := func(t *testing.T) bool {
+		t.Helper()
+		got, _ := state.Load(sessionID)
+		return got.BaselineTree != "original-tree" && !got.StopTriggered && got.Score == 0
+	}
+
+	t.Run("unconfirmed reset asks and keeps the trip", func(t *testing.T) {
+		trip(t)
+		reason := handle(t, "/bumper-reset")
+		if wasReset(t) {
+			t.Fatal("first reset after a trip reset the baseline")
+		}
+		if !strings.Contains(reason, "/bumper-reset --confirm") {
+			t.Errorf("reason = %q, want a confirmation prompt", reason)
+		}
+		if got, _ := state.Load(sessionID); got.ResetConfirmAt == "" || !got.StopTriggered || got.Score != 520 {
+			t.Errorf("state = %+v, want pending confirmation with trip and score kept", got)
+		}
+	})
+
+	t.Run("re-issuing within the window resets", func(t *testing.T) {
+		reason := handle(t, "/bumper-reset")
+		if !wasReset(t) {
+			t.Fatalf("re-issued reset did not reset (reason %q)", reason)
+		}
+		if got, _ := state.Load(sessionID); got.ResetConfirmAt != "" {
+			t.Errorf("ResetConfirmAt = %q after reset, want cleared", got.ResetConfirmAt)
+		}
+	})
+
+	t.Run("--confirm resets immediately and keeps the tag", func(t *testing.T) {
+		trip(t)
+		handle(t, "/bumper-reset --confirm billing")
+		if !wasReset(t) {
+			t.Fatal("--confirm did not reset")
+		}
+		if got, _ := state.Load(sessionID); got.Tag != "billing" {
+			t.Errorf("Tag = %q, want billing", got.Tag)
+		}
+	})
+
+	t.Run("expired confirmation asks again", func(t *testing.T) {
+		trip(t)
+		sess, _ := state.Load(sessionID)
+		sess.RequestResetConfirmation(time.Now().Add(-resetConfirmWindow - time.Second))
+		sess.Save()
+		handle(t, "/bumper-reset")
+		if wasReset(t) {
+			t.Error("reset after the confirmation window expired went through")
+		}
+	})
+
+	t.Run("untripped session resets without asking", func(t *testing.T) {
+		sess, _ := state.New(sessionID, "original-tree", "main", 400)
+		sess.SetScore(120)
+		sess.Save()
+		handle(t, "/bumper-reset")
+		if !wasReset(t) {
+			t.Error("reset of an untripped session asked for confirmation")
+		}
+	})
+
+	t.Run("CLI reset asks too, then goes through when re-run", func(t *testing.T) {
+		trip(t)
+		if err := Reset(sessionID, false); err == nil || !strings.Contains(err.Error(), "--confirm") {
+			t.Fatalf("Reset() error = %v, want a confirmation request", err)
+		}
+		if wasReset(t) {
+			t.Fatal("unconfirmed CLI reset reset the baseline")
+		}
+		captureOutput(t, func() {
+			if err := Reset(sessionID, false); err != nil {
+				t.Errorf("re-run Reset() error = %v", err)
+			}
+		})
+		if !wasReset(t) {
+			t.Error("re-run CLI reset did not reset")
+		}
+	})
+
+	t.Run("CLI reset --confirm resets immediately", func(t *testing.T) {
+		trip(t)
+		captureOutput(t, func() {
+			if err := Reset(sessionID, true); err != nil {
+				t.Errorf("Reset(confirmed) error = %v", err)
+			}
+		})
+		if !wasReset(t) {
+			t.Error("confirmed CLI reset did not reset")
+		}
+	})
+
+	t.Run("session-reset-all --full skips unconfirmed trips", func(t *testing.T) {
+		trip(t)
+		var out strings.Builder
+		ResetAll(&out, true, false)
+		if wasReset(t) || !strings.Contains(out.String(), "1 tripped sessions skipped") {
+			t.Errorf("ResetAll(full) reset a tripped session without confirmation:\n%s", out.String())
+		}
+		out.Reset()
+		ResetAll(&out, true, true)
+		if !wasReset(t) {
+			t.Errorf("ResetAll(full, confirmed) did not reset:\n%s", out.String())
+		}
+	})
+
+	// Soft resets keep the baseline, so the next stop re-trips if still
+	// over; they are exempt from the confirmation step
+	t.Run("soft resets are exempt", func(t *testing.T) {
+		trip(t)
+		handle(t, "/bumper-reset --soft")
+		if got, _ := state.Load(sessionID); got.StopTriggered || got.ResetConfirmAt != "" || got.BaselineTree != "original-tree" {
+			t.Errorf("/bumper-reset --soft state = %+v, want trip cleared without asking, baseline kept", got)
+		}
+		trip(t)
+		captureOutput(t, func() { SoftReset(sessionID) })
+		if got, _ := state.Load(sessionID); got.StopTriggered || got.BaselineTree != "original-tree" {
+			t.Errorf("CLI SoftReset state = %+v, want trip cleared, baseline kept", got)
+		}
+	})
+}
+
+func TestResetAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	ids := []string{"test-all-a", "test-all-b", "test-all-c"}
+	setup := func(t *testing.T) {
+		t.Helper()
+		for i, id := range ids {
+			sess, _ := state.New(id, "original-tree", "main", 400)
+			sess.SetScore(520)
+			// The last session is under threshold and was never tripped
+			sess.SetStopTriggered(i < len(ids)-1)
+			if err := sess.Save(); err != nil {
+				t.Fatalf("Save(%s): %v", id, err)
+			}
+		}
+		// Lock dirs and leftover temp files sit beside the session files
+		checkpointDir, _ := state.GetCheckpointDir()
+		os.MkdirAll(filepath.Join(checkpointDir, "stop-lock-test-all-a.lock"), 0755)
+		os.WriteFile(filepath.Join(checkpointDir, "session-test-all-a.tmp"), []byte("{"), 0644)
+	}
+
+	t.Run("soft clears every trip", func(t *testing.T) {
+		setup(t)
+		var out strings.Builder
+		if err := ResetAll(&out, false, false); err != nil {
+			t.Fatalf("ResetAll() error = %v", err)
+		}
+		if want := "Trip cleared for 2 of 3 sessions\n"; out.String() != want {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+		for _, id := range ids {
+			got, _ := state.Load(id)
+			if got.StopTriggered {
+				t.Errorf("%s: StopTriggered = true, want cleared", id)
+			}
+			if got.BaselineTree != "original-tree" || got.Score != 520 {
+				t.Errorf("%s: baseline %q score %d, want original-tree/520 kept", id, got.BaselineTree, got.Score)
+			}
+		}
+	})
+
+	t.Run("full moves every baseline", func(t *testing.T) {
+		setup(t)
+		current, err := CaptureTree()
+		if err != nil {
+			t.Fatalf("CaptureTree: %v", err)
+		}
+		var out strings.Builder
+		if err := ResetAll(&out, true, false); err != nil {
+			t.Fatalf("ResetAll() error = %v", err)
+		}
+		if want := "Baseline reset for 3 of 3 sessions\n"; out.String() != want {
+			t.Errorf("output = %q, want %q", out.String(), want)
+		}
+		for _, id := range ids {
+			got, _ := state.Load(id)
+			if got.StopTriggered || got.Score != 0 || got.BaselineTree != current {
+				t.Errorf("%s: tripped=%v score=%d baseline=%q, want cleared at %q", id, got.StopTriggered, got.Score, got.BaselineTree, current)
+			}
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// scoreCalc is a weighted score plus the tree pair and stats it came from.
+type scoreCalc struct {
+	FromTree string
+	ToTree   string
+	Stats    *diff.StatsJSON
+	*scoring.WeightedScore
+
+	// Carryover is the part of Score carried over by a commit auto-reset
+	// rather than scored from the diff.
+	Carryover int
+
+	// Submodules is the weighted part of Score from line changes inside
+	// submodules (score_submodules).
+	Submodules int
+}
+
+// calculateScore computes the weighted score from baseline to the current tree,
+// applying scoring options from config.
+// Returns nil if the diff can't be computed (callers fail open).
+func calculateScore(baselineTree string) *scoreCalc {
+	fromTree, toTree, err := resolveScoreTrees(baselineTree)
+	if err != nil {
+		return nil
+	}
+
+	stats := getTreeStatsJSON(fromTree, toTree)
+	if stats == nil {
+		return nil
+	}
+	stats = filterScoredStats(stats)
+
+	// Submodule checkouts are working trees, so staged scope skips them
+	var links []gitlink
+	if config.LoadScoreSubmodules() && config.LoadScoreScope() != config.ScoreScopeStaged {
+		links = treeGitlinks(fromTree)
+		stats = withoutGitlinks(stats, links)
+	}
+
+	opts := loadScoringOptions(fromTree, toTree)
+	result := &scoreCalc{
+		FromTree:      fromTree,
+		ToTree:        toTree,
+		Stats:         stats,
+		WeightedScore: scoring.CalculateWithOptions(stats, opts),
+	}
+	if len(links) > 0 {
+		result.Submodules = submoduleScore(links, opts)
+		result.Score += result.Submodules
+	}
+	return result
+}
+
+// calculateSessionScore scores the session's baseline and adds any
+// carry-over from a commit auto-reset, so enforcement sees the total.
+// Returns nil if the diff can't be computed (callers fail open).
+func calculateSessionScore(sess *state.SessionState) *scoreCalc {
+	result := calculateScore(sess.BaselineTree)
+	if result == nil || sess.Carryover <= 0 {
+		return result
+	}
+	result.Carryover = sess.Carryover
+	result.Score += sess.Carryover
+	return result
+}
+
+// resolveScoreTrees picks the tree pair to diff based on score_scope.
+//   - working: baseline vs working tree (staged + unstaged + untracked)
+//   - staged:  HEAD vs index, ignoring the session baseline
+//
+// Staged falls back to the baseline when HEAD doesn't exist (empty repo).
+func resolveScoreTrees(baselineTree string) (string, string, error) {
+	if config.LoadScoreScope() != config.ScoreScopeStaged {
+		currentTree, err := captureCurrentTree()
+		return baselineTree, currentTree, err
+	}
+
+	fromTree := GetHeadTree()
+	if fromTree == "" {
+		fromTree = baselineTree
+	}
+	indexTree, err := getIndexTree()
+	return fromTree, indexTree, err
+}
+
+// captureCurrentTree captures the working tree for scoring, including
+// untracked files unless count_untracked is off. It shares captureTree with
+// baselines, so both retry write-tree on git lock contention.
+func captureCurrentTree() (string, error) {
+	return captureTree(config.LoadCountUntracked())
+}
+
+// getIndexTree writes the staged index as a tree. write-tree runs against a
+// copy of the real index: on the real one it takes index.lock and rewrites
+// the cache-tree extension, which can fail the user's own git add/commit.
+// Like captureTreeIn, it retries write-tree on git lock contention.
+func getIndexTree() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "index").Output()
+	if err != nil {
+		return "", err
+	}
+	realIndex := strings.TrimSpace(string(output))
+
+	tmpIndex, err := os.CreateTemp("", "git-index-*")
+	if err != nil {
+		return "", err
+	}
+	tmpIndexPath := tmpIndex.Name()
+	defer os.Remove(tmpIndexPath)
+
+	src, err := os.Open(realIndex)
+	if os.IsNotExist(err) {
+		// Nothing staged yet: a missing index file reads as empty
+		tmpIndex.Close()
+		os.Remove(tmpIndexPath)
+	} else if err != nil {
+		tmpIndex.Close()
+		return "", err
+	} else {
+		_, err = io.Copy(tmpIndex, src)
+		src.Close()
+		if closeErr := tmpIndex.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("copying index: %w", err)
+		}
+	}
+
+	output, err = writeTreeWithRetry("", tmpIndexPath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// loadPathFilter builds the include/exclude filter from config.
+func loadPathFilter() scoring.PathFilter {
+	return scoring.PathFilter{Include: config.LoadInclude(), Exclude: config.LoadExclude()}
+}
+
+// filterScoredStats drops files that don't count toward the score: the
+// include/exclude filter, plus linguist-generated files when
+// respect_gitattributes is on (they aren't hand-reviewed).
+func filterScoredStats(stats *diff.StatsJSON) *diff.StatsJSON {
+	f := loadPathFilter()
+	if config.LoadRespectGitattributes() {
+		f.Skip = generatedPaths(stats)
+	}
+	return scoring.FilterStats(stats, f)
+}
+
+// generatedPaths returns the changed files marked linguist-generated in
+// .gitattributes, via one git check-attr call. Returns nil on error
+// (nothing skipped).
+func generatedPaths(stats *diff.StatsJSON) map[string]bool {
+	if len(stats.Files) == 0 {
+		return nil
+	}
+	var in strings.Builder
+	for _, file := range stats.Files {
+		in.WriteString(file.Path + "\x00")
+	}
+	cmd := exec.Command("git", "check-attr", "-z", "--stdin", "linguist-generated")
+	cmd.Stdin = strings.NewReader(in.String())
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	// -z output is NUL-separated <path> <attribute> <value> triples
+	fields := strings.Split(string(output), "\x00")
+	var generated map[string]bool
+	for i := 0; i+2 < len(fields); i += 3 {
+		if v := fields[i+2]; v == "set" || v == "true" {
+			if generated == nil {
+				generated = make(map[string]bool)
+			}
+			generated[fields[i]] = true
+		}
+	}
+	return generated
+}
+
+// loadScoringOptions builds scoring options from config plus the per-diff
+// data between two trees. Options that need extra git work only run that
+// work when enabled.
+func loadScoringOptions(baselineTree, currentTree string) scoring.Options {
+	opts := config.LoadScoringOptions()
+	discountComments := config.LoadDiscountComments()
+	weighHeads := opts.HeadLinesWeight > 0 && opts.HeadLinesWeight != 1
+	if !discountComments && opts.HunkWeight <= 0 && !weighHeads {
+		return opts
+	}
+
+	// One patch feeds every per-diff count; on error none are applied
+	patch, ok := getZeroContextPatch(baselineTree, currentTree)
+	if !ok {
+		return opts
+	}
+	if discountComments {
+		opts.CommentLines = scoring.CountCommentAdditions(patch)
+	}
+	if opts.HunkWeight > 0 {
+		opts.Hunks = scoring.CountHunks(patch)
+	}
+	if weighHeads {
+		opts.HeadLines = scoring.CountHeadAdditions(patch, config.LoadHeadLinesCount())
+	}
+	return opts
+}
+
+// getZeroContextPatch diffs two trees with no context lines, so
+// adjacent-but-separate edits stay separate hunks. The current tree
+// includes untracked files, so one patch covers both. It takes the same
+// diff_flags as the numstat it's matched against, so renames and ignored
+// whitespace line up with the scored files. Reports false on error.
+func getZeroContextPatch(baselineTree, currentTree string) (string, bool) {
+	args := append(append([]string{"diff-tree", "-p", "-r"}, config.LoadDiffFlags()...),
+		"-U0", "--no-color", "--no-ext-diff", baselineTree, currentTree)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", false
+	}
+	return string(output), true
+}
+
+// Actions traced by user commands that change trip state, so replay can
+// follow manual resets as well as the hooks' own decisions.
+const (
+	traceManualReset = "reset (manual)"
+	traceAck         = "ack"
+	traceUndo        = "undo" // "undo (tripped)" when the restored state was tripped
+)
+
+// traceUndoAction names an undo trace by the trip state it restored.
+func traceUndoAction(tripped bool) string {
+	if tripped {
+		return traceUndo + " (tripped)"
+	}
+	return traceUndo
+}
+
+// traceDecision logs the full decision context when BUMPER_LANES_DEBUG=1.
+// Makes the session log a trace for "why did/didn't it block".
+// currentTree is empty on hot paths that don't capture the working tree.
+func traceDecision(log *logging.Logger, action, baselineTree, currentTree string, score, limit int) {
+	if currentTree == "" {
+		currentTree = "(not captured)"
+	}
+	log.Debug("decision=%s baseline=%s current=%s score=%d threshold=%d",
+		action, baselineTree, currentTree, score, limit)
+}
+
+// startCooldown anchors the post-reset cooldown when cooldown_score is set.
+// Call after ResetBaseline. The anchor is only non-zero in staged scope,
+// where a reset doesn't clear the scored diff and would otherwise re-trip.
+func startCooldown(sess *state.SessionState) {
+	if config.LoadCooldownScore() <= 0 {
+		return
+	}
+	score := 0
+	if result := calculateSessionScore(sess); result != nil {
+		score = result.Score
+	}
+	sess.StartCooldown(score)
+}
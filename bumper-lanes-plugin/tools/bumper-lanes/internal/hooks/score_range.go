@@ -0,0 +1,98 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
+)
+
+// ScoreRangeResult is the score for an arbitrary ref range.
+type ScoreRangeResult struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	FromTree string `json:"from_tree"`
+	ToTree   string `json:"to_tree"`
+	*scoring.WeightedScore
+}
+
+// ScoreRange prints the weighted score between two refs.
+// Independent of any session - useful for sizing a PR's review burden in CI.
+func ScoreRange(from, to string, jsonOutput bool) error {
+	result, err := scoreRange(from, to)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(formatScoreRange(result))
+	return nil
+}
+
+// scoreRange resolves both refs to trees and scores the diff between them.
+func scoreRange(from, to string) (*ScoreRangeResult, error) {
+	fromTree, err := resolveTree(from)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := resolveTree(to)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := scoreTrees(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("diffing %s..%s: %w", from, to, err)
+	}
+
+	return &ScoreRangeResult{
+		From:          from,
+		To:            to,
+		FromTree:      fromTree,
+		ToTree:        toTree,
+		WeightedScore: score,
+	}, nil
+}
+
+// scoreTrees scores the diff between two tree SHAs with config filters and options.
+// Unlike calculateScore it skips the stats cache, since callers aren't hooks.
+func scoreTrees(fromTree, toTree string) (*scoring.WeightedScore, error) {
+	stats, err := getTreeDiffStats(fromTree, toTree, config.LoadDiffFlags())
+	if err != nil {
+		return nil, err
+	}
+	jsonStats := stats.ToJSON()
+	filtered := filterScoredStats(&jsonStats)
+	return scoring.CalculateWithOptions(filtered, loadScoringOptions(fromTree, toTree)), nil
+}
+
+// resolveTree resolves a ref (commit, branch, tag, or tree SHA) to a tree SHA.
+func resolveTree(ref string) (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{tree}").Output()
+	if err != nil {
+		return "", fmt.Errorf("unknown ref: %s", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// formatScoreRange formats the breakdown like the Stop hook's threshold message.
+func formatScoreRange(r *ScoreRangeResult) string {
+	return fmt.Sprintf(`Score: %d points (%s..%s)
+- New file additions: %d lines (1.0×)
+- Edit additions: %d lines (1.3×)
+- Files touched: %d
+- Scatter penalty: %s%s
+`, r.Score, r.From, r.To, r.NewAdditions, r.EditAdditions, r.FilesTouched, formatScatter(r.WeightedScore),
+		formatOptionalBreakdown(r.WeightedScore))
+}
@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestScoreRange(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// Commit 1: existing.go with 10 lines
+	os.WriteFile("existing.go", []byte(strings.Repeat("a\n", 10)), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "first").Run()
+	exec.Command("git", "tag", "v1").Run()
+
+	// Commit 2: +10 lines to existing.go, new.go with 20 lines
+	os.WriteFile("existing.go", []byte(strings.Repeat("a\n", 20)), 0644)
+	os.WriteFile("new.go", []byte(strings.Repeat("b\n", 20)), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "second").Run()
+
+	t.Run("breakdown matches hand-computed score", func(t *testing.T) {
+		result, err := scoreRange("v1", "HEAD")
+		if err != nil {
+			t.Fatalf("scoreRange() error = %v", err)
+		}
+		// (20 new * 10 + 10 edit * 13) / 10 = 33, 2 files = no scatter
+		if result.Score != 33 {
+			t.Errorf("Score = %d, want 33", result.Score)
+		}
+		if result.NewAdditions != 20 {
+			t.Errorf("NewAdditions = %d, want 20", result.NewAdditions)
+		}
+		if result.EditAdditions != 10 {
+			t.Errorf("EditAdditions = %d, want 10", result.EditAdditions)
+		}
+		if result.FilesTouched != 2 {
+			t.Errorf("FilesTouched = %d, want 2", result.FilesTouched)
+		}
+		if result.ScatterPenalty != 0 {
+			t.Errorf("ScatterPenalty = %d, want 0", result.ScatterPenalty)
+		}
+	})
+
+	t.Run("json output flattens score fields", func(t *testing.T) {
+		result, _ := scoreRange("v1", "HEAD")
+		data, _ := json.Marshal(result)
+
+		var decoded map[string]interface{}
+		json.Unmarshal(data, &decoded)
+		if decoded["score"] != float64(33) {
+			t.Errorf("json score = %v, want 33", decoded["score"])
+		}
+		if decoded["from"] != "v1" || decoded["to"] != "HEAD" {
+			t.Errorf("json from/to = %v/%v, want v1/HEAD", decoded["from"], decoded["to"])
+		}
+	})
+
+	t.Run("unknown ref errors", func(t *testing.T) {
+		if _, err := scoreRange("v1", "no-such-ref"); err == nil {
+			t.Error("scoreRange() with unknown ref should error")
+		}
+	})
+}
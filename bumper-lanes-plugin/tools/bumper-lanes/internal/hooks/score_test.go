@@ -0,0 +1,537 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCalculateScoreDiscountComments(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+	output, _ := cmd.Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	// 10 comment lines + 10 code lines in a new (untracked) file
+	var content strings.Builder
+	for i := 0; i < 10; i++ {
+		content.WriteString("// comment\n")
+		content.WriteString("x := 1\n")
+	}
+	os.WriteFile("mixed.go", []byte(content.String()), 0644)
+
+	t.Run("full weight by default", func(t *testing.T) {
+		result := calculateScore(baselineTree)
+		if result == nil {
+			t.Fatal("calculateScore() returned nil")
+		}
+		if result.Score != 20 {
+			t.Errorf("Score = %d, want 20", result.Score)
+		}
+	})
+
+	t.Run("comments discounted when enabled", func(t *testing.T) {
+		os.WriteFile(".bumper-lanes.json", []byte(`{"discount_comments": true}`), 0644)
+		defer os.Remove(".bumper-lanes.json")
+
+		result := calculateScore(baselineTree)
+		if result == nil {
+			t.Fatal("calculateScore() returned nil")
+		}
+		// .bumper-lanes.json is itself a new 1-line file: 1 pt
+		// mixed.go: (10*10 + 10*10/4) / 10 = 12
+		if result.Score != 13 {
+			t.Errorf("Score = %d, want 13 (12 for mixed.go + 1 for config)", result.Score)
+		}
+		if result.CommentAdditions != 10 {
+			t.Errorf("CommentAdditions = %d, want 10", result.CommentAdditions)
+		}
+	})
+}
+
+func TestCalculateScoreStagedScope(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+	output, _ := cmd.Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	// 10 lines staged, 30 lines unstaged
+	os.WriteFile("staged.go", []byte(strings.Repeat("x\n", 10)), 0644)
+	exec.Command("git", "add", "staged.go").Run()
+	os.WriteFile("unstaged.go", []byte(strings.Repeat("y\n", 30)), 0644)
+
+	t.Run("working scope scores everything", func(t *testing.T) {
+		result := calculateScore(baselineTree)
+		if result == nil {
+			t.Fatal("calculateScore() returned nil")
+		}
+		if result.Score != 40 {
+			t.Errorf("Score = %d, want 40", result.Score)
+		}
+	})
+
+	t.Run("staged scope scores only the index", func(t *testing.T) {
+		// Config file stays untracked, so it doesn't count in staged scope
+		os.WriteFile(".bumper-lanes.json", []byte(`{"score_scope": "staged"}`), 0644)
+		defer os.Remove(".bumper-lanes.json")
+
+		result := calculateScore(baselineTree)
+		if result == nil {
+			t.Fatal("calculateScore() returned nil")
+		}
+		if result.Score != 10 {
+			t.Errorf("Score = %d, want 10 (staged.go only)", result.Score)
+		}
+		if len(result.Stats.Files) != 1 || result.Stats.Files[0].Path != "staged.go" {
+			t.Errorf("Files = %+v, want only staged.go", result.Stats.Files)
+		}
+	})
+}
+
+func TestCalculateScorePathFilter(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+	output, _ := cmd.Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	os.MkdirAll("src", 0755)
+	os.MkdirAll("docs", 0755)
+	os.WriteFile("src/app.go", []byte(strings.Repeat("x\n", 10)), 0644)
+	os.WriteFile("src/app_test.go", []byte(strings.Repeat("x\n", 20)), 0644)
+	os.WriteFile("docs/guide.md", []byte(strings.Repeat("x\n", 40)), 0644)
+
+	tests := []struct {
+		name   string
+		config string
+		want   int
+	}{
+		{"include only", `{"include": ["src/"]}`, 30},
+		{"include then exclude", `{"include": ["src/"], "exclude": ["*_test.go"]}`, 10},
+		{"exclude only", `{"exclude": ["docs/", ".bumper-lanes.json"]}`, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			defer os.Remove(".bumper-lanes.json")
+
+			result := calculateScore(baselineTree)
+			if result == nil {
+				t.Fatal("calculateScore() returned nil")
+			}
+			if result.Score != tt.want {
+				t.Errorf("Score = %d, want %d", result.Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreDiffFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile("old.go", []byte(strings.Repeat("line\n", 30)), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "add old.go").Run()
+	baseline := GetHeadTree()
+
+	// A pure rename: without rename detection it scores as a 30-line new file
+	exec.Command("git", "mv", "old.go", "new.go").Run()
+
+	tests := []struct {
+		name   string
+		config string
+		want   int
+	}{
+		{"no flags", `{"exclude": [".bumper-lanes.json"]}`, 30},
+		{"rename detection", `{"exclude": [".bumper-lanes.json"], "diff_flags": ["-M"]}`, 0},
+		{"invalid flags ignored", `{"exclude": [".bumper-lanes.json"], "diff_flags": ["HEAD", "--output=out.txt"]}`, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			defer os.Remove(".bumper-lanes.json")
+
+			result := calculateScore(baseline)
+			if result == nil {
+				t.Fatal("calculateScore() returned nil")
+			}
+			if result.Score != tt.want {
+				t.Errorf("score = %d, want %d", result.Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreDiffFlagsPerDiffCounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile("old.go", []byte(strings.Repeat("// comment\n", 20)), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "add old.go").Run()
+	baseline := GetHeadTree()
+
+	// Rename, then append 10 code lines. With -M only those 10 lines are
+	// scored; the patch must agree, or the 20 moved comments discount them.
+	exec.Command("git", "mv", "old.go", "new.go").Run()
+	f, _ := os.OpenFile("new.go", os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(strings.Repeat("x := 1\n", 10))
+	f.Close()
+
+	os.WriteFile(".bumper-lanes.json", []byte(`{"exclude": [".bumper-lanes.json"], "diff_flags": ["-M"]}`), 0644)
+	plain := calculateScore(baseline)
+	os.WriteFile(".bumper-lanes.json", []byte(`{"exclude": [".bumper-lanes.json"], "diff_flags": ["-M"], "discount_comments": true, "hunk_weight": 5}`), 0644)
+	defer os.Remove(".bumper-lanes.json")
+	result := calculateScore(baseline)
+	if plain == nil || result == nil {
+		t.Fatal("calculateScore() returned nil")
+	}
+	if result.CommentAdditions != 0 {
+		t.Errorf("CommentAdditions = %d, want 0 (the comments were renamed, not added)", result.CommentAdditions)
+	}
+	if want := plain.Score + 5; result.Score != want {
+		t.Errorf("Score = %d, want %d (one appended hunk on top of %d)", result.Score, want, plain.Score)
+	}
+}
+
+func TestCalculateScoreRespectsGitattributes(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile(".gitattributes", []byte("*.pb.go linguist-generated\ngen/** linguist-generated=true\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "add attributes").Run()
+	baseline := GetHeadTree()
+
+	// 10 hand-written lines, 200 generated ones in two generated forms
+	os.WriteFile("main.go", []byte(strings.Repeat("x\n", 10)), 0644)
+	os.WriteFile("api.pb.go", []byte(strings.Repeat("x\n", 100)), 0644)
+	os.MkdirAll("gen", 0755)
+	os.WriteFile(filepath.Join("gen", "client.go"), []byte(strings.Repeat("x\n", 100)), 0644)
+
+	tests := []struct {
+		name   string
+		config string
+		want   int
+	}{
+		{"default skips generated", `{"exclude": [".bumper-lanes.json"]}`, 10},
+		{"disabled scores everything", `{"exclude": [".bumper-lanes.json"], "respect_gitattributes": false}`, 210},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			defer os.Remove(".bumper-lanes.json")
+
+			result := calculateScore(baseline)
+			if result == nil {
+				t.Fatal("calculateScore() returned nil")
+			}
+			if result.Score != tt.want {
+				t.Errorf("score = %d, want %d", result.Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreCountUntracked(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile("tracked.go", []byte(strings.Repeat("x\n", 10)), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "add tracked.go").Run()
+
+	tests := []struct {
+		name        string
+		config      string
+		want        int
+		wantScratch bool
+	}{
+		// 10 edit lines * 1.3 = 13, plus a 50-line untracked scratch file
+		{"default counts untracked", `{"exclude": [".bumper-lanes.json"]}`, 63, true},
+		{"disabled skips untracked", `{"exclude": [".bumper-lanes.json"], "count_untracked": false}`, 13, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			defer os.Remove(".bumper-lanes.json")
+
+			baseline, err := CaptureTree()
+			if err != nil {
+				t.Fatalf("CaptureTree: %v", err)
+			}
+			os.WriteFile("tracked.go", []byte(strings.Repeat("x\n", 20)), 0644)
+			os.WriteFile("scratch.go", []byte(strings.Repeat("x\n", 50)), 0644)
+			defer os.Remove("scratch.go")
+			defer exec.Command("git", "checkout", "tracked.go").Run()
+
+			result := calculateScore(baseline)
+			if result == nil {
+				t.Fatal("calculateScore() returned nil")
+			}
+			if result.Score != tt.want {
+				t.Errorf("score = %d, want %d", result.Score, tt.want)
+			}
+			hasScratch := false
+			for _, f := range result.Stats.Files {
+				hasScratch = hasScratch || f.Path == "scratch.go"
+			}
+			if hasScratch != tt.wantScratch {
+				t.Errorf("scratch.go in stats = %v, want %v", hasScratch, tt.wantScratch)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreSubmodules(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// A standalone repo to add as a submodule
+	libDir := filepath.Join(tmpDir, "lib-src")
+	os.MkdirAll(libDir, 0755)
+	setupTempGitRepo(t, libDir)
+	os.WriteFile(filepath.Join(libDir, "lib.go"), []byte(strings.Repeat("x\n", 10)), 0644)
+	gitIn := func(t *testing.T, dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	gitIn(t, libDir, "add", ".")
+	gitIn(t, libDir, "commit", "-m", "add lib.go")
+
+	repoDir := filepath.Join(tmpDir, "repo")
+	os.MkdirAll(repoDir, 0755)
+	setupTempGitRepo(t, repoDir)
+	gitIn(t, repoDir, "-c", "protocol.file.allow=always", "submodule", "add", libDir, "vendor/lib")
+	gitIn(t, repoDir, "commit", "-m", "add submodule")
+	subDir := filepath.Join(repoDir, "vendor", "lib")
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(repoDir)
+
+	tests := []struct {
+		name   string
+		config string
+		commit bool   // commit the submodule work, so only the pointer moves
+		attrs  string // parent .gitattributes
+		want   int
+	}{
+		// 10 edit lines * 1.3 = 13, plus a 20-line new file = 33
+		{"default ignores submodules", `{"exclude": [".bumper-lanes.json"]}`, false, "", 0},
+		{"enabled counts dirty submodule", `{"exclude": [".bumper-lanes.json"], "score_submodules": true}`, false, "", 33},
+		{"weight scales submodule score", `{"exclude": [".bumper-lanes.json"], "score_submodules": true, "submodule_weight": 0.5}`, false, "", 17},
+		{"committed submodule work still counts", `{"exclude": [".bumper-lanes.json"], "score_submodules": true}`, true, "", 33},
+		{"filters apply to submodule paths", `{"exclude": [".bumper-lanes.json", "vendor/**"], "score_submodules": true}`, false, "", 0},
+		{"generated submodule files skipped", `{"exclude": [".bumper-lanes.json", ".gitattributes"], "score_submodules": true, "respect_gitattributes": true}`, false, "vendor/lib/new.go linguist-generated\n", 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			defer os.Remove(".bumper-lanes.json")
+			if tt.attrs != "" {
+				os.WriteFile(".gitattributes", []byte(tt.attrs), 0644)
+				defer os.Remove(".gitattributes")
+			}
+
+			baseline, err := CaptureTree()
+			if err != nil {
+				t.Fatalf("CaptureTree: %v", err)
+			}
+			headBefore := strings.TrimSpace(gitOutput(t, subDir, "rev-parse", "HEAD"))
+			defer gitIn(t, subDir, "reset", "--hard", headBefore)
+			defer os.Remove(filepath.Join(subDir, "new.go"))
+
+			os.WriteFile(filepath.Join(subDir, "lib.go"), []byte(strings.Repeat("x\n", 20)), 0644)
+			os.WriteFile(filepath.Join(subDir, "new.go"), []byte(strings.Repeat("x\n", 20)), 0644)
+			if tt.commit {
+				gitIn(t, subDir, "add", ".")
+				gitIn(t, subDir, "commit", "-m", "submodule work")
+			}
+
+			result := calculateScore(baseline)
+			if result == nil {
+				t.Fatal("calculateScore() returned nil")
+			}
+			if result.Score != tt.want || result.Submodules != tt.want {
+				t.Errorf("score = %d (submodules %d), want both %d", result.Score, result.Submodules, tt.want)
+			}
+		})
+	}
+}
+
+// gitOutput runs git in dir and returns its stdout.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+	return string(out)
+}
+
+func TestCalculateScoreHunkWeight(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// Commit a 100-line file, then edit 5 of its lines two ways
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	os.WriteFile("main.go", []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	gitOutput(t, tmpDir, "add", "main.go")
+	gitOutput(t, tmpDir, "-c", "user.name=test", "-c", "user.email=test@test.com", "commit", "-q", "-m", "add main.go")
+	baseline := strings.TrimSpace(gitOutput(t, tmpDir, "rev-parse", "HEAD^{tree}"))
+
+	edit := func(rows ...int) {
+		changed := append([]string(nil), lines...)
+		for _, r := range rows {
+			changed[r] = "changed"
+		}
+		os.WriteFile("main.go", []byte(strings.Join(changed, "\n")+"\n"), 0644)
+	}
+	score := func(t *testing.T) int {
+		t.Helper()
+		result := calculateScore(baseline)
+		if result == nil {
+			t.Fatal("calculateScore() returned nil")
+		}
+		return result.Score
+	}
+	block := func(t *testing.T) int { edit(40, 41, 42, 43, 44); return score(t) }
+	scattered := func(t *testing.T) int { edit(10, 30, 50, 70, 90); return score(t) }
+
+	t.Run("off by default: same lines score the same", func(t *testing.T) {
+		if b, s := block(t), scattered(t); b != s {
+			t.Errorf("block = %d, scattered = %d; want equal without hunk_weight", b, s)
+		}
+	})
+
+	t.Run("hunk_weight charges scattered edits more", func(t *testing.T) {
+		os.WriteFile(".bumper-lanes.json", []byte(`{"hunk_weight": 2}`), 0644)
+		defer os.Remove(".bumper-lanes.json")
+
+		b, s := block(t), scattered(t)
+		// 5 edit adds = 6 pts + 1 hunk * 2 (block) or 5 hunks * 2 (scattered),
+		// plus 1 pt and 1 hunk for the new config file
+		if b != 6+2+1+2 || s != 6+10+1+2 {
+			t.Errorf("block = %d, scattered = %d; want 11 and 19", b, s)
+		}
+	})
+}
+
+func TestCalculateScoreHeadLinesWeight(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// Commit a 200-line file, then add 10 lines at its top or its bottom
+	lines := make([]string, 200)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	os.WriteFile("main.go", []byte(strings.Join(lines, "\n")+"\n"), 0644)
+	gitOutput(t, tmpDir, "add", "main.go")
+	gitOutput(t, tmpDir, "-c", "user.name=test", "-c", "user.email=test@test.com", "commit", "-q", "-m", "add main.go")
+	baseline := strings.TrimSpace(gitOutput(t, tmpDir, "rev-parse", "HEAD^{tree}"))
+
+	added := make([]string, 10)
+	for i := range added {
+		added[i] = "added"
+	}
+	score := func(t *testing.T, content []string) int {
+		t.Helper()
+		os.WriteFile("main.go", []byte(strings.Join(content, "\n")+"\n"), 0644)
+		result := calculateScore(baseline)
+		if result == nil {
+			t.Fatal("calculateScore() returned nil")
+		}
+		return result.Score
+	}
+	top := func(t *testing.T) int { return score(t, append(append([]string(nil), added...), lines...)) }
+	bottom := func(t *testing.T) int { return score(t, append(append([]string(nil), lines...), added...)) }
+
+	t.Run("off by default: position doesn't matter", func(t *testing.T) {
+		if tp, b := top(t), bottom(t); tp != 13 || b != 13 {
+			t.Errorf("top = %d, bottom = %d; want both 13 without head_lines_weight", tp, b)
+		}
+	})
+
+	t.Run("head_lines_weight charges additions near the top more", func(t *testing.T) {
+		os.WriteFile(".bumper-lanes.json", []byte(`{"head_lines_weight": 2, "head_lines_count": 20, "exclude": [".bumper-lanes.json"]}`), 0644)
+		defer os.Remove(".bumper-lanes.json")
+
+		// 10 edit adds = 13 pts; at the top they count twice
+		if tp, b := top(t), bottom(t); tp != 26 || b != 13 {
+			t.Errorf("top = %d, bottom = %d; want 26 and 13", tp, b)
+		}
+	})
+}
@@ -1,12 +1,22 @@
 package hooks
 
 import (
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
 // SessionEnd handles the SessionEnd hook event.
-// It cleans up the session state file.
+// It cleans up the session state file, unless inherit_baseline is on:
+// then the state is kept so the next session on the branch can inherit it,
+// and only other sessions idle longer than inheritMaxAge (too old to ever
+// be inherited) are pruned.
 func SessionEnd(input *HookInput) error {
+	if config.LoadInheritBaseline() {
+		state.PruneIdle(input.SessionID, inheritMaxAge, time.Now())
+		return nil
+	}
 	// Delete session state - ignore errors (file may not exist)
 	state.Delete(input.SessionID)
 	return nil
@@ -0,0 +1,43 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestSessionEndPrunesIdleWithInheritBaseline(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile(".bumper-lanes.json", []byte(`{"inherit_baseline": true}`), 0644)
+
+	tree, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree() error = %v", err)
+	}
+	for _, id := range []string{"test-end-current", "test-end-recent", "test-end-idle"} {
+		sess, _ := state.New(id, tree, "main", 400)
+		sess.Save()
+	}
+	checkpointDir, _ := state.GetCheckpointDir()
+	old := time.Now().Add(-2 * inheritMaxAge)
+	os.Chtimes(filepath.Join(checkpointDir, "session-test-end-idle"), old, old)
+
+	SessionEnd(&HookInput{SessionID: "test-end-current"})
+
+	for id, wantKept := range map[string]bool{"test-end-current": true, "test-end-recent": true, "test-end-idle": false} {
+		_, err := state.Load(id)
+		if kept := err == nil; kept != wantKept {
+			t.Errorf("session %s kept = %v, want %v (err %v)", id, kept, wantKept, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// sessionExportVersion is the session-export format version. Bump it when
+// the format changes incompatibly; import rejects other versions.
+const sessionExportVersion = 1
+
+// sessionExport is the portable form written by session-export.
+type sessionExport struct {
+	Version int                 `json:"version"`
+	Session *state.SessionState `json:"session"`
+	// Config is the merged global + repo config at export time (nil fields
+	// are defaults). It's a record of the settings the score came from;
+	// import never writes it.
+	Config *config.Config `json:"config"`
+}
+
+// SessionExport writes the session's state and the resolved config as
+// portable JSON, for handing a review off to another machine or agent.
+func SessionExport(sessionID string, w io.Writer) error {
+	sess, err := state.Load(sessionID)
+	if err != nil {
+		return fmt.Errorf("no session state for %s", sessionID)
+	}
+	data, err := json.MarshalIndent(sessionExport{
+		Version: sessionExportVersion,
+		Session: sess,
+		Config:  config.LoadMerged(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling export: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// SessionImport recreates a session from session-export JSON. sessionID
+// overrides the exported ID when non-empty. RepoPath is remapped to this
+// repo, and the baseline tree must exist here: tree SHAs only mean
+// something in the repo (or a clone) they came from. An existing session
+// is only replaced with force.
+func SessionImport(r io.Reader, w io.Writer, sessionID string, force bool) error {
+	var exp sessionExport
+	if err := json.NewDecoder(r).Decode(&exp); err != nil {
+		return fmt.Errorf("parsing export: %w", err)
+	}
+	if exp.Version != sessionExportVersion {
+		return fmt.Errorf("unsupported export version %d (want %d)", exp.Version, sessionExportVersion)
+	}
+	sess := exp.Session
+	if sess == nil || sess.BaselineTree == "" {
+		return fmt.Errorf("export has no session baseline")
+	}
+	if sessionID != "" {
+		sess.SessionID = sessionID
+	}
+	if sess.SessionID == "" {
+		return fmt.Errorf("export has no session_id; pass one with --id")
+	}
+	if err := state.ValidateSessionID(sess.SessionID); err != nil {
+		return err
+	}
+
+	if !objectExists(sess.BaselineTree + "^{tree}") {
+		return fmt.Errorf("baseline tree %s not found in this repo; fetch the branch it came from first", shortSHA(sess.BaselineTree))
+	}
+	if _, err := state.Load(sess.SessionID); err == nil && !force {
+		return fmt.Errorf("session %s already exists (pass --force to replace it)", sess.SessionID)
+	} else if err != nil && !errors.Is(err, state.ErrNoSession) && !force {
+		return fmt.Errorf("checking existing session: %w", err)
+	}
+
+	repoPath, err := state.GetRepoPath()
+	if err != nil {
+		return err
+	}
+	sess.RepoPath = repoPath
+	sess.Revision = 0
+	if err := sess.Save(); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+
+	fmt.Fprintf(w, "Imported session %s (baseline %s, score %s)\n",
+		sess.SessionID, shortSHA(sess.BaselineTree), displayScore(sess.Score, sess.ThresholdLimit))
+	return nil
+}
@@ -0,0 +1,142 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestSessionExportImportRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+
+	// Source repo: a committed file, so the baseline tree travels with a clone
+	srcDir := t.TempDir()
+	setupTempGitRepo(t, srcDir)
+	os.Chdir(srcDir)
+	os.WriteFile(filepath.Join(srcDir, "main.go"), []byte("package main\n"), 0644)
+	os.WriteFile(filepath.Join(srcDir, ".bumper-lanes.json"), []byte(`{"threshold": 300}`), 0644)
+	gitOutput(t, srcDir, "add", ".")
+	gitOutput(t, srcDir, "-c", "user.name=test", "-c", "user.email=test@test.com", "commit", "-q", "-m", "add main.go")
+	baseline := GetHeadTree()
+
+	sess, _ := state.New("review-1", baseline, "main", 300)
+	sess.SetScore(220)
+	sess.SetTag("auth refactor")
+	sess.Save()
+
+	var exported bytes.Buffer
+	if err := SessionExport("review-1", &exported); err != nil {
+		t.Fatalf("SessionExport() error = %v", err)
+	}
+	var exp sessionExport
+	if err := json.Unmarshal(exported.Bytes(), &exp); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, exported.String())
+	}
+	if exp.Version != sessionExportVersion || exp.Session.BaselineTree != baseline {
+		t.Errorf("export = version %d baseline %q, want %d %q", exp.Version, exp.Session.BaselineTree, sessionExportVersion, baseline)
+	}
+	if exp.Config == nil || exp.Config.Threshold == nil || *exp.Config.Threshold != 300 {
+		t.Errorf("export config = %+v, want the repo threshold 300", exp.Config)
+	}
+
+	t.Run("import into a clone remaps RepoPath", func(t *testing.T) {
+		cloneDir := filepath.Join(t.TempDir(), "clone")
+		if out, err := exec.Command("git", "clone", "-q", srcDir, cloneDir).CombinedOutput(); err != nil {
+			t.Fatalf("git clone: %v\n%s", err, out)
+		}
+		os.Chdir(cloneDir)
+		defer os.Chdir(srcDir)
+
+		var out bytes.Buffer
+		if err := SessionImport(bytes.NewReader(exported.Bytes()), &out, "", false); err != nil {
+			t.Fatalf("SessionImport() error = %v", err)
+		}
+		got, err := state.Load("review-1")
+		if err != nil {
+			t.Fatalf("Load() after import: %v", err)
+		}
+		wantRepo, _ := state.GetRepoPath()
+		if got.RepoPath != wantRepo || got.RepoPath == sess.RepoPath {
+			t.Errorf("RepoPath = %q, want remapped to %q", got.RepoPath, wantRepo)
+		}
+		if got.BaselineTree != baseline || got.Score != 220 || got.Tag != "auth refactor" || got.ThresholdLimit != 300 {
+			t.Errorf("imported state = %+v, want the exported session", got)
+		}
+		if !strings.Contains(out.String(), "Imported session review-1") {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+
+	t.Run("existing session needs --force", func(t *testing.T) {
+		err := SessionImport(bytes.NewReader(exported.Bytes()), &bytes.Buffer{}, "", false)
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("SessionImport() over an existing session error = %v, want already exists", err)
+		}
+		if err := SessionImport(bytes.NewReader(exported.Bytes()), &bytes.Buffer{}, "", true); err != nil {
+			t.Errorf("SessionImport(force) error = %v", err)
+		}
+	})
+
+	t.Run("--id imports under a new session", func(t *testing.T) {
+		if err := SessionImport(bytes.NewReader(exported.Bytes()), &bytes.Buffer{}, "review-2", false); err != nil {
+			t.Fatalf("SessionImport(--id) error = %v", err)
+		}
+		if got, err := state.Load("review-2"); err != nil || got.BaselineTree != baseline {
+			t.Errorf("Load(review-2) = %+v, %v", got, err)
+		}
+	})
+
+	t.Run("unknown baseline tree is rejected", func(t *testing.T) {
+		otherDir := t.TempDir()
+		setupTempGitRepo(t, otherDir)
+		os.Chdir(otherDir)
+		defer os.Chdir(srcDir)
+
+		err := SessionImport(bytes.NewReader(exported.Bytes()), &bytes.Buffer{}, "", false)
+		if err == nil || !strings.Contains(err.Error(), "not found in this repo") {
+			t.Errorf("SessionImport() error = %v, want missing tree", err)
+		}
+		if _, err := state.Load("review-1"); err == nil {
+			t.Error("rejected import still wrote a session")
+		}
+	})
+
+	t.Run("path traversal session IDs are rejected", func(t *testing.T) {
+		gitDir := strings.TrimSpace(gitOutput(t, srcDir, "rev-parse", "--absolute-git-dir"))
+		configBefore, _ := os.ReadFile(filepath.Join(gitDir, "config"))
+		for _, id := range []string{"../../../escaped", "../../config"} {
+			traversal := strings.Replace(exported.String(), `"session_id": "review-1"`, `"session_id": "`+id+`"`, 1)
+			if traversal == exported.String() {
+				t.Fatal("export has no session_id field to replace")
+			}
+			if err := SessionImport(strings.NewReader(traversal), &bytes.Buffer{}, "", true); !errors.Is(err, state.ErrInvalidSessionID) {
+				t.Errorf("SessionImport(%q) error = %v, want ErrInvalidSessionID", id, err)
+			}
+			if err := SessionImport(bytes.NewReader(exported.Bytes()), &bytes.Buffer{}, id, true); !errors.Is(err, state.ErrInvalidSessionID) {
+				t.Errorf("SessionImport(--id %q) error = %v, want ErrInvalidSessionID", id, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(gitDir, "escaped")); !os.IsNotExist(err) {
+			t.Errorf("traversal import wrote outside the checkpoint dir: %v", err)
+		}
+		if configAfter, _ := os.ReadFile(filepath.Join(gitDir, "config")); !bytes.Equal(configAfter, configBefore) {
+			t.Error("traversal import overwrote .git/config")
+		}
+	})
+
+	t.Run("other export versions are rejected", func(t *testing.T) {
+		err := SessionImport(strings.NewReader(`{"version": 2, "session": {}}`), &bytes.Buffer{}, "", false)
+		if err == nil || !strings.Contains(err.Error(), "unsupported export version") {
+			t.Errorf("SessionImport() error = %v, want version error", err)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package hooks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// SessionInfo handles the session-info user command.
+// It prints the session's baseline, score, and how long the baseline has been accumulating.
+func SessionInfo(sessionID string) error {
+	sess, err := state.Load(sessionID)
+	if err != nil {
+		return fmt.Errorf("no session state for %s", sessionID)
+	}
+
+	fmt.Print(formatSessionInfo(sess, time.Now()))
+	return nil
+}
+
+// formatSessionInfo renders session details for display.
+func formatSessionInfo(sess *state.SessionState, now time.Time) string {
+	lastReset := sess.LastResetAt
+	if lastReset == "" {
+		lastReset = "never"
+	}
+
+	branch := sess.BaselineBranch
+	if branch == "" {
+		branch = "(detached)"
+	}
+
+	tag := sess.Tag
+	if tag == "" {
+		tag = "(none)"
+	}
+
+	return fmt.Sprintf(`Session:    %s
+Tag:        %s
+Baseline:   %s (%s)
+Score:      %s
+Created:    %s
+Last reset: %s
+Age:        %s
+`, sess.SessionID, tag, shortSHA(sess.BaselineTree), branch, displayScore(sess.Score, sess.ThresholdLimit),
+		sess.CreatedAt, lastReset, state.FormatAge(sess.Age(now)))
+}
@@ -1,12 +1,14 @@
 package hooks
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
@@ -21,6 +23,9 @@ const (
 	// handsOffMarker prevents bumper-lanes from modifying a statusline script.
 	// Users add this comment anywhere in their script to opt out of auto-setup.
 	handsOffMarker = "# BUMPER_HANDS_OFF"
+	// inheritMaxAge bounds how old a prior session's baseline may be for
+	// inherit_baseline to reuse it.
+	inheritMaxAge = 24 * time.Hour
 )
 
 // SessionStart handles the SessionStart hook event.
@@ -55,6 +60,18 @@ func SessionStart(input *HookInput) int {
 		return 0 // Fail open
 	}
 
+	sess.LastHead = GetHeadCommit()
+
+	// Restarted sessions on the same branch keep counting the work in progress
+	if config.LoadInheritBaseline() {
+		if prior := findInheritableSession(input.SessionID, baselineBranch, sess.LastHead, time.Now()); prior != nil {
+			sess.BaselineTree = prior.BaselineTree
+			sess.Carryover = prior.Carryover
+			sess.Tag = prior.Tag
+			log.Info("inherited baseline %s from session %s", prior.BaselineTree, prior.SessionID)
+		}
+	}
+
 	// Load persisted view settings from config
 	sess.SetViewMode(config.LoadViewMode())
 	sess.SetViewOpts(config.LoadViewOpts())
@@ -64,6 +81,11 @@ func SessionStart(input *HookInput) int {
 		return 0 // Fail open
 	}
 
+	// Observe only: no warnings and no status line setup
+	if isObserveOnly() {
+		return 0
+	}
+
 	// Collect warnings to show user (exit 1 with stderr shows warnings)
 	var warnings []string
 
@@ -88,6 +110,50 @@ func SessionStart(input *HookInput) int {
 	return 0
 }
 
+// findInheritableSession returns the most recently started or reset prior
+// session on branch whose baseline can carry over to a new session, or nil.
+// A baseline qualifies when it is younger than inheritMaxAge, its tree object
+// still exists, and the HEAD it was taken at is an ancestor of (or equal to)
+// head, so the current tree descends from the state it snapshotted.
+func findInheritableSession(sessionID, branch, head string, now time.Time) *state.SessionState {
+	if branch == "" || head == "" {
+		return nil
+	}
+	sessions, err := state.LoadAll()
+	if err != nil {
+		return nil
+	}
+	var best *state.SessionState
+	for _, s := range sessions {
+		if s.SessionID == sessionID || s.BaselineBranch != branch || s.BaselineTree == "" || s.LastHead == "" {
+			continue
+		}
+		age := s.Age(now)
+		if age > inheritMaxAge {
+			continue
+		}
+		if best != nil && age >= best.Age(now) {
+			continue
+		}
+		if !objectExists(s.BaselineTree) || !isAncestor(s.LastHead, head) {
+			continue
+		}
+		best = s
+	}
+	return best
+}
+
+// objectExists reports whether sha names an object in the repository.
+func objectExists(sha string) bool {
+	return exec.Command("git", "cat-file", "-e", sha).Run() == nil
+}
+
+// isAncestor reports whether commit ancestor is reachable from commit descendant.
+// A commit counts as its own ancestor.
+func isAncestor(ancestor, descendant string) bool {
+	return exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant).Run() == nil
+}
+
 // hasStatusLineConfigured checks if ~/.claude/settings.json has statusLine configured.
 func hasStatusLineConfigured() bool {
 	homeDir, err := os.UserHomeDir()
@@ -175,7 +241,7 @@ func setupStatusLineWrapper(log *logging.Logger) string {
 			log.Warn("failed to get executable path: %v (failing open)", err)
 			return "" // Fail open
 		}
-		if err := updateSettingsWithJq(homeDir, newBinaryPath); err != nil {
+		if err := updateSettings(log, homeDir, newBinaryPath); err != nil {
 			log.Warn("failed to update settings.json: %v (failing open)", err)
 			return "" // Fail open - old binary might still work
 		}
@@ -188,7 +254,7 @@ func setupStatusLineWrapper(log *logging.Logger) string {
 		if err != nil {
 			return fmt.Sprintf("[bumper-lanes] Couldn't find binary path: %v", err)
 		}
-		if err := updateSettingsWithJq(homeDir, binaryPath); err != nil {
+		if err := updateSettings(log, homeDir, binaryPath); err != nil {
 			return fmt.Sprintf("[bumper-lanes] Couldn't update settings: %v\nRun /bumper-setup-statusline for manual setup.", err)
 		}
 		return "[bumper-lanes] Status line configured! Restart session to see diff tree."
@@ -200,7 +266,7 @@ func setupStatusLineWrapper(log *logging.Logger) string {
 		return fmt.Sprintf("[bumper-lanes] Failed to create wrapper: %v\nRun /bumper-setup-statusline for manual setup.", err)
 	}
 
-	if err := updateSettingsWithJq(homeDir, wrapperPath); err != nil {
+	if err := updateSettings(log, homeDir, wrapperPath); err != nil {
 		return fmt.Sprintf("[bumper-lanes] Wrapper created at %s\nCouldn't update settings: %v\nRun /bumper-setup-statusline for manual setup.", wrapperPath, err)
 	}
 
@@ -386,23 +452,29 @@ exit 0
 	return nil
 }
 
-// updateSettingsWithJq updates ~/.claude/settings.json using jq.
-func updateSettingsWithJq(homeDir, wrapperPath string) error {
+// updateSettings points statusLine in ~/.claude/settings.json at
+// wrapperPath. jq is preferred since it keeps the file's key order; without
+// it the file is rewritten in-process (keys sorted) and a warning logged.
+func updateSettings(log *logging.Logger, homeDir, wrapperPath string) error {
 	settingsPath := filepath.Join(homeDir, ".claude", "settings.json")
 
-	// Check if jq is available
-	if _, err := exec.LookPath("jq"); err != nil {
-		return fmt.Errorf("jq not installed")
-	}
-
 	// Ensure settings.json exists
 	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
 		// Create minimal settings file
+		if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
+			return err
+		}
 		if err := os.WriteFile(settingsPath, []byte("{}"), 0644); err != nil {
 			return err
 		}
 	}
 
+	// jq is optional
+	if _, err := exec.LookPath("jq"); err != nil {
+		log.Warn("jq not installed; updating %s without it (keys will be re-sorted)", settingsPath)
+		return setStatusLineJSON(settingsPath, wrapperPath)
+	}
+
 	// Use jq to update settings - must set both type and command
 	jqExpr := fmt.Sprintf(`.statusLine.type = "command" | .statusLine.command = %q`, wrapperPath)
 	cmd := exec.Command("jq", jqExpr, settingsPath)
@@ -412,9 +484,79 @@ func updateSettingsWithJq(homeDir, wrapperPath string) error {
 	}
 
 	// Write back
-	if err := os.WriteFile(settingsPath, output, 0644); err != nil {
+	return writeSettingsFile(settingsPath, output)
+}
+
+// setStatusLineJSON is the jq-free updateSettings: it sets statusLine.type
+// and statusLine.command, keeping every other setting.
+func setStatusLineJSON(settingsPath, command string) error {
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		return err
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("parsing %s: %w", settingsPath, err)
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+	statusLine, ok := settings["statusLine"].(map[string]interface{})
+	if !ok {
+		statusLine = make(map[string]interface{})
+	}
+	statusLine["type"] = "command"
+	statusLine["command"] = command
+	settings["statusLine"] = statusLine
+
+	// Plain Marshal would rewrite every "&&" in hook commands as "\u0026\u0026"
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(settings); err != nil {
 		return err
 	}
+	return writeSettingsFile(settingsPath, out.Bytes())
+}
 
+// writeSettingsFile replaces the user's settings file via temp file +
+// rename, like config.writeConfigFile, so a crash mid-write can't truncate
+// it. A symlinked settings.json (dotfile repos) is written through to its
+// target, and the file keeps its permissions.
+func writeSettingsFile(settingsPath string, data []byte) error {
+	path, err := filepath.EvalSymlinks(settingsPath)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".settings-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tempPath := tempFile.Name()
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Chmod(tempPath, mode); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("renaming temp file: %w", err)
+	}
 	return nil
 }
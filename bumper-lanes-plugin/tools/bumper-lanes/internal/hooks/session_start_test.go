@@ -1,9 +1,15 @@
 package hooks
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 )
 
 func TestIsOurWrapper(t *testing.T) {
@@ -317,3 +323,123 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestSessionStartInheritBaseline(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		priorHead   string // "" = HEAD at prior session start
+		priorAge    time.Duration
+		wantInherit bool
+	}{
+		{"default captures fresh", `{}`, "", time.Hour, false},
+		{"enabled inherits", `{"inherit_baseline": true}`, "", time.Hour, true},
+		{"prior HEAD not an ancestor", `{"inherit_baseline": true}`, "0123456789abcdef0123456789abcdef01234567", time.Hour, false},
+		{"prior too old", `{"inherit_baseline": true}`, "", 2 * inheritMaxAge, false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			setupTempGitRepo(t, tmpDir)
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("HOME", t.TempDir())
+
+			origDir, _ := os.Getwd()
+			defer os.Chdir(origDir)
+			os.Chdir(tmpDir)
+
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+
+			// Prior session captured the tree before the work started
+			priorTree, err := CaptureTree()
+			if err != nil {
+				t.Fatalf("CaptureTree() error = %v", err)
+			}
+			prior, _ := state.New(fmt.Sprintf("test-inherit-prior-%d", i), priorTree, "main", 400)
+			prior.CreatedAt = time.Now().Add(-tt.priorAge).UTC().Format(time.RFC3339)
+			prior.LastHead = GetHeadCommit()
+			if tt.priorHead != "" {
+				prior.LastHead = tt.priorHead
+			}
+			prior.Tag = "auth refactor"
+			prior.Save()
+
+			// Uncommitted work survives the restart
+			os.WriteFile(filepath.Join(tmpDir, "work.go"), []byte(strings.Repeat("x\n", 20)), 0644)
+
+			sessionID := fmt.Sprintf("test-inherit-new-%d", i)
+			captureStderr(t, func() {
+				SessionStart(&HookInput{SessionID: sessionID})
+			})
+
+			sess, err := state.Load(sessionID)
+			if err != nil {
+				t.Fatalf("state.Load() error = %v", err)
+			}
+			if got := sess.BaselineTree == priorTree; got != tt.wantInherit {
+				t.Errorf("inherited baseline = %v, want %v", got, tt.wantInherit)
+			}
+			score := calculateSessionScore(sess)
+			if tt.wantInherit {
+				if score == nil || score.Score == 0 || sess.Tag != "auth refactor" {
+					t.Errorf("inherited session should score the pre-existing work and keep the tag, got score %+v tag %q", score, sess.Tag)
+				}
+			} else if score != nil && score.Score != 0 {
+				t.Errorf("fresh baseline should score 0, got %d", score.Score)
+			}
+		})
+	}
+}
+
+func TestSetupStatusLineWithoutJq(t *testing.T) {
+	homeDir := t.TempDir()
+	logDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("PATH", t.TempDir()) // No jq (or anything else) on PATH
+	t.Setenv("CLAUDE_PLUGIN_ROOT", filepath.Join(homeDir, "no-such-plugin"))
+	t.Setenv("BUMPER_LANES_LOG_DIR", logDir)
+
+	// settings.json is a private file symlinked in from a dotfiles repo
+	settingsPath := filepath.Join(homeDir, ".claude", "settings.json")
+	os.MkdirAll(filepath.Dir(settingsPath), 0755)
+	dotfiles := filepath.Join(homeDir, "dotfiles")
+	os.MkdirAll(dotfiles, 0755)
+	realSettings := filepath.Join(dotfiles, "settings.json")
+	os.WriteFile(realSettings, []byte(`{"model": "opus", "hooks": {"Stop": [{"command": "make lint && make test"}]}, "statusLine": {"type": "command", "command": "/usr/bin/my-status-line"}}`), 0600)
+	os.Symlink(realSettings, settingsPath)
+
+	log := logging.New("test-no-jq", "session_start")
+	msg := setupStatusLineWrapper(log)
+	if !strings.Contains(msg, "Wrapped your status line") {
+		t.Fatalf("setupStatusLineWrapper() = %q, want the wrapper set up without jq", msg)
+	}
+
+	wrapperPath := filepath.Join(homeDir, ".claude", wrapperFileName)
+	if got := getStatusLineCommand(homeDir); got != wrapperPath {
+		t.Errorf("statusLine.command = %q, want %q", got, wrapperPath)
+	}
+	data, _ := os.ReadFile(settingsPath)
+	if !strings.Contains(string(data), `"model": "opus"`) {
+		t.Errorf("other settings lost:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"make lint && make test"`) {
+		t.Errorf("hook command not kept verbatim (HTML-escaped?):\n%s", data)
+	}
+	if info, err := os.Lstat(settingsPath); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("settings.json symlink replaced by a file: %v", err)
+	}
+	if info, err := os.Stat(realSettings); err != nil {
+		t.Errorf("settings file: %v", err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("settings file mode = %v, want 0600 kept", info.Mode().Perm())
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dotfiles, ".settings-*.tmp")); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+
+	logData, _ := os.ReadFile(log.LogFile())
+	if !strings.Contains(string(logData), "jq not installed") {
+		t.Errorf("log missing the jq warning:\n%s", logData)
+	}
+}
@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// checkpointUsage is the disk usage of the checkpoint dir, by file kind.
+type checkpointUsage struct {
+	SessionFiles int
+	SessionBytes int64
+	LockFiles    int
+	LockBytes    int64
+	OtherFiles   int // Stats cache, leftover .tmp files
+	OtherBytes   int64
+}
+
+// Total returns the combined size of every file in the checkpoint dir.
+func (u checkpointUsage) Total() int64 {
+	return u.SessionBytes + u.LockBytes + u.OtherBytes
+}
+
+// Size prints how much disk the repo's checkpoint dir uses, split into
+// session state files and stop-hook lock files.
+func Size(w io.Writer) error {
+	dir, err := state.GetCheckpointDir()
+	if err != nil {
+		return err
+	}
+	usage, err := measureCheckpoints(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s: %s\n", dir, formatBytes(usage.Total()))
+	fmt.Fprintf(w, "  sessions: %d files, %s\n", usage.SessionFiles, formatBytes(usage.SessionBytes))
+	fmt.Fprintf(w, "  locks:    %d files, %s\n", usage.LockFiles, formatBytes(usage.LockBytes))
+	if usage.OtherFiles > 0 {
+		fmt.Fprintf(w, "  other:    %d files, %s\n", usage.OtherFiles, formatBytes(usage.OtherBytes))
+	}
+	return nil
+}
+
+// measureCheckpoints sums file sizes under dir. Files are classified by the
+// top-level entry they live under, so anything inside a stop-lock-*.lock
+// directory counts as a lock. A missing dir is empty, not an error.
+func measureCheckpoints(dir string) (checkpointUsage, error) {
+	var usage checkpointUsage
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		top := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+		isLock := strings.HasSuffix(top, ".lock")
+		if d.IsDir() {
+			// An empty lock dir is still a lock left behind
+			if isLock && top == rel {
+				usage.LockFiles++
+			}
+			return nil
+		}
+
+		switch {
+		case isLock:
+			usage.LockBytes += info.Size()
+		case strings.HasPrefix(top, "session-") && !strings.HasSuffix(top, ".tmp"):
+			usage.SessionFiles++
+			usage.SessionBytes += info.Size()
+		default:
+			usage.OtherFiles++
+			usage.OtherBytes += info.Size()
+		}
+		return nil
+	})
+	return usage, err
+}
+
+// formatBytes renders n in binary units, e.g. "512 B", "1.5 KiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,86 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	t.Run("no checkpoint dir", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Size(&buf); err != nil {
+			t.Fatalf("Size() error = %v", err)
+		}
+		if !strings.Contains(buf.String(), ": 0 B\n") {
+			t.Errorf("expected empty usage, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("known sizes", func(t *testing.T) {
+		checkpointDir, _ := state.GetCheckpointDir()
+		os.MkdirAll(filepath.Join(checkpointDir, "stop-lock-a.lock"), 0755)
+		os.MkdirAll(filepath.Join(checkpointDir, "stop-lock-b.lock"), 0755)
+		files := map[string]int{
+			"session-a":              1000,
+			"session-b":              1048,
+			"stop-lock-b.lock/owner": 24,
+			"stats-cache.json":       100,
+			"session-c.tmp":          28,
+		}
+		for name, size := range files {
+			os.WriteFile(filepath.Join(checkpointDir, name), bytes.Repeat([]byte("x"), size), 0644)
+		}
+
+		usage, err := measureCheckpoints(checkpointDir)
+		if err != nil {
+			t.Fatalf("measureCheckpoints() error = %v", err)
+		}
+		want := checkpointUsage{SessionFiles: 2, SessionBytes: 2048, LockFiles: 2, LockBytes: 24, OtherFiles: 2, OtherBytes: 128}
+		if usage != want {
+			t.Errorf("usage = %+v, want %+v", usage, want)
+		}
+		if usage.Total() != 2200 {
+			t.Errorf("Total() = %d, want 2200", usage.Total())
+		}
+
+		var buf bytes.Buffer
+		if err := Size(&buf); err != nil {
+			t.Fatalf("Size() error = %v", err)
+		}
+		for _, line := range []string{": 2.1 KiB\n", "sessions: 2 files, 2.0 KiB\n", "locks:    2 files, 24 B\n", "other:    2 files, 128 B\n"} {
+			if !strings.Contains(buf.String(), line) {
+				t.Errorf("output missing %q in:\n%s", line, buf.String())
+			}
+		}
+	})
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
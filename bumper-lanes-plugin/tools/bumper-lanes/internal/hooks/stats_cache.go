@@ -0,0 +1,102 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// statsCacheFile holds the most recent baseline->current diff stats.
+//
+// Tree SHAs are content-addressed, so a (baseline, current) pair always
+// produces the same stats and the entry never goes stale - it is simply
+// replaced when either tree changes.
+//
+// Tradeoff: this only skips the diff-tree + numstat step. captureCurrentTree
+// (git add -A into a temp index + write-tree) still runs on every call since
+// that is how we learn the current tree SHA. With 200 modified files the
+// cache cut a call from ~36ms to ~27ms; captureCurrentTree dominates what's
+// left. See BenchmarkTreeStats* in stats_cache_test.go.
+const statsCacheFile = "stats-cache.json"
+
+type statsCacheEntry struct {
+	BaselineTree string         `json:"baseline_tree"`
+	CurrentTree  string         `json:"current_tree"`
+	DiffFlags    []string       `json:"diff_flags,omitempty"`
+	Stats        diff.StatsJSON `json:"stats"`
+}
+
+// statsCachePath returns the cache file path in the checkpoint dir.
+func statsCachePath() (string, error) {
+	checkpointDir, err := state.GetCheckpointDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(checkpointDir, statsCacheFile), nil
+}
+
+// loadCachedStats returns cached stats for the tree pair and diff flags, or nil on miss.
+func loadCachedStats(baselineTree, currentTree string, flags []string) *diff.StatsJSON {
+	path, err := statsCachePath()
+	if err != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var entry statsCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	if entry.BaselineTree != baselineTree || entry.CurrentTree != currentTree || !slices.Equal(entry.DiffFlags, flags) {
+		return nil
+	}
+	return &entry.Stats
+}
+
+// saveCachedStats stores stats for the tree pair and diff flags, replacing any prior entry.
+// Errors are ignored - a failed write just means the next call recomputes.
+func saveCachedStats(baselineTree, currentTree string, flags []string, stats *diff.StatsJSON) {
+	path, err := statsCachePath()
+	if err != nil {
+		return
+	}
+
+	data, err := json.Marshal(statsCacheEntry{
+		BaselineTree: baselineTree,
+		CurrentTree:  currentTree,
+		DiffFlags:    flags,
+		Stats:        *stats,
+	})
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	// Atomic write: parallel hooks may read while we write
+	tempFile, err := os.CreateTemp(dir, "stats-cache-*.tmp")
+	if err != nil {
+		return
+	}
+	tempPath := tempFile.Name()
+	_, writeErr := tempFile.Write(data)
+	closeErr := tempFile.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tempPath)
+		return
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+	}
+}
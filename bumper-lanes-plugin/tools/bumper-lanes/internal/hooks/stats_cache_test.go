@@ -0,0 +1,135 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+func TestGetTreeStatsJSONCache(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+	output, _ := cmd.Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	os.WriteFile("new.go", []byte("a\nb\nc\n"), 0644)
+	currentTree, err := diff.CaptureCurrentTree()
+	if err != nil {
+		t.Fatalf("CaptureCurrentTree() error: %v", err)
+	}
+
+	first := getTreeStatsJSON(baselineTree, currentTree)
+	if first == nil {
+		t.Fatal("getTreeStatsJSON() returned nil")
+	}
+	if first.Totals.Adds != 3 {
+		t.Fatalf("Totals.Adds = %d, want 3", first.Totals.Adds)
+	}
+
+	t.Run("unchanged tree returns cached stats", func(t *testing.T) {
+		// Plant a sentinel so a hit is distinguishable from a recompute
+		sentinel := diff.StatsJSON{Files: []diff.FileStatJSON{{Path: "cached.go", Adds: 999}}}
+		sentinel.Totals.Adds = 999
+		saveCachedStats(baselineTree, currentTree, nil, &sentinel)
+
+		got := getTreeStatsJSON(baselineTree, currentTree)
+		if got == nil || got.Totals.Adds != 999 {
+			t.Errorf("expected cached stats (999 adds), got %+v", got)
+		}
+	})
+
+	t.Run("changed tree invalidates cache", func(t *testing.T) {
+		os.WriteFile("new.go", []byte("a\nb\nc\nd\n"), 0644)
+		newTree, err := diff.CaptureCurrentTree()
+		if err != nil {
+			t.Fatalf("CaptureCurrentTree() error: %v", err)
+		}
+		if newTree == currentTree {
+			t.Fatal("tree SHA should change after edit")
+		}
+
+		got := getTreeStatsJSON(baselineTree, newTree)
+		if got == nil || got.Totals.Adds != 4 {
+			t.Errorf("expected recomputed stats (4 adds), got %+v", got)
+		}
+		if loadCachedStats(baselineTree, currentTree, nil) != nil {
+			t.Error("old tree pair should no longer be cached")
+		}
+	})
+
+	t.Run("changed baseline misses", func(t *testing.T) {
+		if loadCachedStats("0000000000000000000000000000000000000000", currentTree, nil) != nil {
+			t.Error("different baseline should not hit cache")
+		}
+	})
+}
+
+// BenchmarkTreeStatsUncached measures CaptureCurrentTree + a full diff.
+func BenchmarkTreeStatsUncached(b *testing.B) {
+	baselineTree := setupStatsBenchRepo(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		currentTree, _ := diff.CaptureCurrentTree()
+		stats, _, _ := diff.GetTreeDiffStats(baselineTree, currentTree)
+		_ = stats.ToJSON()
+	}
+}
+
+// BenchmarkTreeStatsCached measures CaptureCurrentTree + a cache hit.
+// The difference from Uncached is what the cache saves per call.
+func BenchmarkTreeStatsCached(b *testing.B) {
+	baselineTree := setupStatsBenchRepo(b)
+	currentTree, _ := diff.CaptureCurrentTree()
+	getTreeStatsJSON(baselineTree, currentTree) // warm
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		currentTree, _ := diff.CaptureCurrentTree()
+		_ = getTreeStatsJSON(baselineTree, currentTree)
+	}
+}
+
+// setupStatsBenchRepo creates a repo with 200 modified files and returns the baseline tree.
+func setupStatsBenchRepo(b *testing.B) string {
+	b.Helper()
+	if !IsGitRepo() {
+		b.Skip("Not in a git repo")
+	}
+
+	tmpDir := b.TempDir()
+	setupBenchGitRepo(b, tmpDir)
+
+	origDir, _ := os.Getwd()
+	b.Cleanup(func() { os.Chdir(origDir) })
+	os.Chdir(tmpDir)
+
+	for i := 0; i < 200; i++ {
+		os.WriteFile(fmt.Sprintf("file%03d.go", i), []byte("package main\n"), 0644)
+	}
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "initial").Run()
+
+	output, _ := exec.Command("git", "rev-parse", "HEAD^{tree}").Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	body := strings.Repeat("x := 1\n", 50)
+	for i := 0; i < 200; i++ {
+		os.WriteFile(fmt.Sprintf("file%03d.go", i), []byte("package main\n"+body), 0644)
+	}
+	return baselineTree
+}
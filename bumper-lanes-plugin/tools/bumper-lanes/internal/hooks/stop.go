@@ -3,13 +3,15 @@ package hooks
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/statusline"
 	"github.com/kylesnowschwartz/diff-viz/v2/diff"
 )
 
@@ -46,6 +48,8 @@ import (
 //
 // Reference: https://docs.anthropic.com/en/docs/claude-code/hooks
 func Stop(input *HookInput) error {
+	input.SessionID = resolveSessionID(input)
+
 	// Initialize logger for this session
 	log := logging.New(input.SessionID, "stop")
 
@@ -72,14 +76,14 @@ func Stop(input *HookInput) error {
 		log.Warn("failed to load session state: %v (failing open)", err)
 		return nil // No baseline - fail open
 	}
+	refreshThreshold(sess)
 
 	// Always recalculate score to enable bidirectional state transitions.
 	// If paused, track changes but don't enforce
-	if sess.Paused {
+	if sess.IsPaused(time.Now()) {
 		// Use fresh score from baseline (not incremental accumulation)
-		stats := getStatsJSON(sess.BaselineTree)
-		if stats != nil {
-			result := scoring.Calculate(stats)
+		if result := calculateSessionScore(sess); result != nil {
+			traceDecision(log, "allow (paused)", result.FromTree, result.ToTree, result.Score, sess.ThresholdLimit)
 			sess.SetScore(result.Score)
 			sess.Save()
 		}
@@ -89,18 +93,20 @@ func Stop(input *HookInput) error {
 	// If threshold is 0 (disabled), track changes but don't enforce
 	// Same behavior as paused, but config-driven instead of session command
 	if sess.ThresholdLimit == 0 {
-		stats := getStatsJSON(sess.BaselineTree)
-		if stats != nil {
-			result := scoring.Calculate(stats)
+		if result := calculateSessionScore(sess); result != nil {
+			traceDecision(log, "allow (disabled)", result.FromTree, result.ToTree, result.Score, sess.ThresholdLimit)
 			sess.SetScore(result.Score)
 			sess.Save()
 		}
 		return nil
 	}
 
-	// Detect branch switch - auto-reset baseline
+	// Detect branch switch - auto-reset baseline unless configured to keep it
 	currentBranch := GetCurrentBranch()
-	if sess.BaselineBranch != "" && currentBranch != "" && sess.BaselineBranch != currentBranch {
+	branchSwitched := sess.BaselineBranch != "" && currentBranch != "" && sess.BaselineBranch != currentBranch
+	if branchSwitched && !config.LoadResetOnBranchSwitch() {
+		log.Info("branch changed (%s → %s), keeping baseline (reset_on_branch_switch=false)", sess.BaselineBranch, currentBranch)
+	} else if branchSwitched {
 		// Only capture tree when actually needed (branch switch detected)
 		// This avoids ~50ms overhead on every Stop invocation
 		currentTree, err := CaptureTree()
@@ -108,8 +114,13 @@ func Stop(input *HookInput) error {
 			log.Warn("failed to capture current tree for branch reset: %v (failing open)", err)
 			return nil
 		}
+		traceDecision(log, "auto-reset (branch switch)", sess.BaselineTree, currentTree, sess.Score, sess.ThresholdLimit)
 		sess.ResetBaseline(currentTree, currentBranch)
+		startCooldown(sess)
 		sess.Save()
+		if isObserveOnly() {
+			return nil
+		}
 
 		// Output branch switch message
 		resp := StopResponse{
@@ -122,21 +133,50 @@ func Stop(input *HookInput) error {
 
 	// Get diff stats from baseline (fresh calculation, not incremental)
 	// This allows score to decrease when user manually deletes/reverts changes
-	stats := getStatsJSON(sess.BaselineTree)
-	if stats == nil {
+	result := calculateSessionScore(sess)
+	if result == nil {
 		log.Warn("failed to get diff stats (failing open)")
 		return nil // Fail open
 	}
-
-	// Calculate fresh score from baseline
-	result := scoring.Calculate(stats)
 	freshScore := result.Score
 
+	// Dry run: record score and log the would-be decision, but never block
+	if isDryRun() {
+		decision := "allow"
+		if freshScore > sess.ThresholdLimit {
+			decision = "block"
+		}
+		log.Info("dry run: would %s (score %d/%d)", decision, freshScore, sess.ThresholdLimit)
+		traceDecision(log, "allow (dry run)", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
+		sess.SetScore(freshScore)
+		sess.Save()
+		return nil
+	}
+
+	// Observe only: record the score, never block or message
+	if isObserveOnly() {
+		traceDecision(log, "allow (observe)", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
+		sess.SetScore(freshScore)
+		sess.Save()
+		return nil
+	}
+
+	// Below the enforcement floor: trivial work never blocks or messages,
+	// and a tripped session recovers without a notice
+	if freshScore < config.LoadMinEnforceScore() {
+		traceDecision(log, "allow (below floor)", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
+		sess.SetStopTriggered(false)
+		sess.SetScore(freshScore)
+		sess.Save()
+		return nil
+	}
+
 	// Check threshold
 	if freshScore <= sess.ThresholdLimit {
 		// Under threshold - check if we need to clear StopTriggered flag
 		if sess.StopTriggered {
 			// Automatic recovery: score dropped below threshold
+			traceDecision(log, "auto-recover", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
 			sess.SetStopTriggered(false)
 			sess.SetScore(freshScore)
 			sess.Save()
@@ -148,25 +188,58 @@ func Stop(input *HookInput) error {
 			}
 			resp := StopResponse{
 				Continue:       true,
-				SystemMessage:  fmt.Sprintf("✓ Bumper lanes: Auto-recovered (score dropped to %d/%d - %d%%)", freshScore, sess.ThresholdLimit, pct),
+				SystemMessage:  fmt.Sprintf("✓ Bumper lanes: Auto-recovered (score dropped to %s - %d%%)", displayScore(freshScore, sess.ThresholdLimit), pct),
 				SuppressOutput: false,
 			}
 			return WriteResponse(resp)
 		}
 
 		// Normal case: update state and allow
+		traceDecision(log, "allow", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
 		sess.SetScore(freshScore)
 		sess.Save()
 		return nil
 	}
 
+	// Over threshold, but still close to the post-reset anchor - don't re-trip yet
+	if sess.InCooldown(freshScore, config.LoadCooldownScore()) {
+		traceDecision(log, "allow (cooldown)", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
+		sess.SetScore(freshScore)
+		sess.Save()
+		return nil
+	}
+
+	// Over threshold, but the user allowed this one through
+	// (/bumper-allow-once). Only an over-threshold Stop consumes the override,
+	// and it clears an earlier trip so PreToolUse stops blocking edits.
+	if sess.AllowOnce {
+		traceDecision(log, "allow (once)", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
+		sess.SetAllowOnce(false)
+		sess.SetStopTriggered(false)
+		sess.SetScore(freshScore)
+		sess.Save()
+
+		resp := StopResponse{
+			Continue:       true,
+			SystemMessage:  fmt.Sprintf("Bumper lanes: Over threshold (%s) - allowed once. The next stop enforces again.", displayScore(freshScore, sess.ThresholdLimit)),
+			SuppressOutput: false,
+		}
+		return WriteResponse(resp)
+	}
+
 	// Over threshold - set stop_triggered and block
+	traceDecision(log, "block", result.FromTree, result.ToTree, freshScore, sess.ThresholdLimit)
 	sess.SetStopTriggered(true)
 	sess.SetScore(freshScore)
 	sess.Save()
 
 	// Format breakdown message (stats are already from baseline)
 	pct := (freshScore * 100) / sess.ThresholdLimit
+	extensions := ""
+	if config.LoadStopShowExtensions() {
+		extensions = formatExtensions(result.Stats)
+	}
+	shownScore, shownLimit := config.DisplayScore(freshScore, sess.ThresholdLimit)
 	reason := fmt.Sprintf(`
 
 ⚠️  Bumper lanes: Diff threshold exceeded
@@ -175,13 +248,15 @@ Score: %d / %d points (%d%%)
 - New file additions: %d lines (1.0×)
 - Edit additions: %d lines (1.3×)
 - Files touched: %d
-- Scatter penalty: %d pts
+- Scatter penalty: %s%s
 
-Ask the User: Would you like to conduct a structured, manual review?
+Ask the User: Would you like to conduct a structured, manual review?%s
 
 This workflow ensures incremental code review at predictable checkpoints.
 
-`, freshScore, sess.ThresholdLimit, pct, result.NewAdditions, result.EditAdditions, result.FilesTouched, result.ScatterPenalty)
+`, shownScore, shownLimit, pct, result.NewAdditions, result.EditAdditions, result.FilesTouched, formatScatter(result.WeightedScore),
+		formatOptionalBreakdown(result.WeightedScore)+formatSubmodules(result.Submodules)+formatCarryover(result.Carryover)+extensions,
+		formatChecklist(config.LoadReviewChecklist()))
 
 	// Build response - see function doc comment for explanation of these confusing semantics
 	resp := StopResponse{
@@ -205,40 +280,197 @@ This workflow ensures incremental code review at predictable checkpoints.
 			"edit_additions":       result.EditAdditions,
 			"files_touched":        result.FilesTouched,
 			"scatter_penalty":      result.ScatterPenalty,
+			"comment_additions":    result.CommentAdditions,
+			"deletion_score":       result.DeletionScore,
+			"hunk_score":           result.HunkScore,
 		},
 	}
 
 	return WriteResponse(resp)
 }
 
+// formatChecklist formats review_checklist as a numbered list under the
+// review question. Returns "" when no checklist is configured.
+func formatChecklist(steps []string) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nReview checklist:")
+	for i, step := range steps {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, step)
+	}
+	return b.String()
+}
+
+// formatScatter formats the scatter penalty breakdown value.
+func formatScatter(result *scoring.WeightedScore) string {
+	if result.ScatterDisabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("%d pts", result.ScatterPenalty)
+}
+
+// formatOptionalBreakdown returns extra breakdown lines for opt-in scoring
+// components, each prefixed with a newline. Empty when none are active.
+func formatOptionalBreakdown(result *scoring.WeightedScore) string {
+	var b strings.Builder
+	if result.DeletionScore > 0 {
+		fmt.Fprintf(&b, "\n- Deletions: %d pts", result.DeletionScore)
+	}
+	if result.HunkScore > 0 {
+		fmt.Fprintf(&b, "\n- Hunks: %d (%d pts)", result.Hunks, result.HunkScore)
+	}
+	if result.HeadScore != 0 {
+		fmt.Fprintf(&b, "\n- Head-of-file additions: %d lines (%+d pts)", result.HeadAdditions, result.HeadScore)
+	}
+	return b.String()
+}
+
+// formatSubmodules adds a breakdown line for points scored inside
+// submodules (score_submodules). Empty when there are none.
+func formatSubmodules(points int) string {
+	if points <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n- Submodule changes: %d pts", points)
+}
+
+// formatCarryover adds a breakdown line for points carried over by a
+// commit auto-reset (carryover_fraction). Empty when nothing carried over.
+func formatCarryover(points int) string {
+	if points <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n- Carried over from last commit: %d pts", points)
+}
+
+// stopMaxExtensions is how many extensions the Stop breakdown names.
+const stopMaxExtensions = 3
+
+// formatExtensions adds a breakdown line naming the extensions with the
+// most added lines (stop_show_extensions), e.g. "go:120 md:40 other:5".
+// Empty when nothing was added.
+func formatExtensions(stats *diff.StatsJSON) string {
+	if stats == nil {
+		return ""
+	}
+	var ds diff.DiffStats
+	for _, f := range stats.Files {
+		ds.Files = append(ds.Files, diff.FileStat{Path: f.Path, Additions: f.Adds, Deletions: f.Dels})
+	}
+	breakdown := statusline.FormatExtensionBreakdown(&ds, stopMaxExtensions)
+	if breakdown == "" {
+		return ""
+	}
+	return "\n- Additions by extension: " + breakdown
+}
+
+// isDryRun reports whether BUMPER_LANES_DRY_RUN=1 is set.
+// In dry-run mode Stop logs what it would decide without enforcing it,
+// which lets teams calibrate thresholds against real work.
+func isDryRun() bool {
+	return os.Getenv("BUMPER_LANES_DRY_RUN") == "1"
+}
+
+// isObserveOnly reports whether hooks run in observe-only mode, via
+// BUMPER_LANES_OBSERVE=1 or observe_only config. Unlike dry run, which only
+// covers Stop, observe mode silences every hook: scores and state are still
+// recorded, but nothing blocks and nothing reaches stdout or stderr.
+func isObserveOnly() bool {
+	return os.Getenv("BUMPER_LANES_OBSERVE") == "1" || config.LoadObserveOnly()
+}
+
 // getStatsJSON uses diff-viz library to get stats from baseline to current tree.
 func getStatsJSON(baselineTree string) *diff.StatsJSON {
 	// Capture current working tree
-	currentTree, err := diff.CaptureCurrentTree()
+	currentTree, err := captureCurrentTree()
 	if err != nil {
 		return nil
 	}
+	return getTreeStatsJSON(baselineTree, currentTree)
+}
+
+// getTreeStatsJSON gets diff stats between two tree SHAs.
+// Results are cached in the checkpoint dir keyed by the tree pair and diff flags.
+func getTreeStatsJSON(baselineTree, currentTree string) *diff.StatsJSON {
+	flags := config.LoadDiffFlags()
+	if cached := loadCachedStats(baselineTree, currentTree, flags); cached != nil {
+		return cached
+	}
 
-	// Get diff stats from baseline to current
-	stats, _, err := diff.GetTreeDiffStats(baselineTree, currentTree)
+	stats, err := getTreeDiffStats(baselineTree, currentTree, flags)
 	if err != nil {
 		return nil
 	}
 
 	jsonStats := stats.ToJSON()
+	saveCachedStats(baselineTree, currentTree, flags, &jsonStats)
 	return &jsonStats
 }
 
+// getTreeDiffStats is diff.GetTreeDiffStats with extra git flags (diff_flags).
+// diff-viz can't pass flags through, so with flags set this runs the same
+// numstat and --name-status commands itself. Fails open like diff-viz.
+func getTreeDiffStats(baselineTree, currentTree string, flags []string) (*diff.DiffStats, error) {
+	if len(flags) == 0 {
+		stats, _, err := diff.GetTreeDiffStats(baselineTree, currentTree)
+		return stats, err
+	}
+	return gitTreeDiffStats("", baselineTree, currentTree, flags)
+}
+
+// gitTreeDiffStats diffs two trees of the repository at dir ("" = current
+// directory) with numstat and --name-status, marking added files as new.
+func gitTreeDiffStats(dir, baselineTree, currentTree string, flags []string) (*diff.DiffStats, error) {
+	numstatArgs := append(append([]string{"diff-tree", "--numstat", "-r"}, flags...), baselineTree, currentTree)
+	output, err := gitCommand(dir, numstatArgs...).Output()
+	if err != nil {
+		return &diff.DiffStats{}, nil
+	}
+	stats, _, err := diff.ParseNumstat(string(output))
+	if err != nil {
+		return nil, err
+	}
+
+	// Mark added files as new for weighted scoring
+	statusArgs := append(append([]string{"diff-tree", "-r", "--name-status", "--diff-filter=AM"}, flags...), baselineTree, currentTree)
+	statusOutput, _ := gitCommand(dir, statusArgs...).Output()
+	added := make(map[string]bool)
+	for _, line := range strings.Split(string(statusOutput), "\n") {
+		if status, path, ok := strings.Cut(line, "\t"); ok && status == "A" {
+			added[path] = true
+		}
+	}
+	for i := range stats.Files {
+		stats.Files[i].Path = renameDest(stats.Files[i].Path)
+		stats.Files[i].IsUntracked = added[stats.Files[i].Path]
+	}
+	return stats, nil
+}
+
+// renameDest keeps the destination of a top-level rename ("old.go =>
+// new.go") that diff-viz leaves as is; it only resolves the braced
+// "{old => new}/file" form. Per-path counts from the patch key on the
+// destination, so scored paths must too.
+func renameDest(path string) string {
+	if strings.Contains(path, "{") {
+		return path
+	}
+	if _, dest, ok := strings.Cut(path, " => "); ok {
+		return dest
+	}
+	return path
+}
+
 // acquireLock creates a lock directory to prevent parallel hook races.
 func acquireLock(sessionID string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
-	output, err := cmd.Output()
+	checkpointDir, err := state.GetCheckpointDir()
 	if err != nil {
 		return "", err
 	}
-	gitDir := strings.TrimSpace(string(output))
 
-	lockDir := filepath.Join(gitDir, "bumper-checkpoints", fmt.Sprintf("stop-lock-%s.lock", sessionID))
+	lockDir := filepath.Join(checkpointDir, fmt.Sprintf("stop-lock-%s.lock", sessionID))
 	if err := os.Mkdir(lockDir, 0755); err != nil {
 		return "", err // Lock already held
 	}
@@ -2,14 +2,17 @@ package hooks
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
 )
 
 func TestStopCumulativeStats(t *testing.T) {
@@ -18,7 +21,7 @@ func TestStopCumulativeStats(t *testing.T) {
 		t.Skip("Not in a git repo")
 	}
 
-	t.Run("uses BaselineTree not PreviousTree for breakdown", func(t *testing.T) {
+	t.Run("breakdown is computed fresh from BaselineTree", func(t *testing.T) {
 		// Create a temp git repo
 		tmpDir := t.TempDir()
 		setupTempGitRepo(t, tmpDir)
@@ -614,3 +617,802 @@ func TestScoreDecreasesWhenFileDeleted(t *testing.T) {
 		t.Errorf("Score should be 0 after deleting all added content, got %d", scoreAfterDelete)
 	}
 }
+
+// TestStopDryRun verifies BUMPER_LANES_DRY_RUN=1 records the score but never blocks.
+func TestStopDryRun(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	t.Setenv("BUMPER_LANES_DRY_RUN", "1")
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+	output, _ := cmd.Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	// 50 new lines = 50 pts, well over the 30 pt threshold
+	var content strings.Builder
+	for i := 0; i < 50; i++ {
+		content.WriteString("// line\n")
+	}
+	os.WriteFile(filepath.Join(tmpDir, "large.go"), []byte(content.String()), 0644)
+
+	sessionID := "test-stop-dry-run"
+	sess, _ := state.New(sessionID, baselineTree, "main", 30)
+	sess.Save()
+
+	var err error
+	outputStr, _ := captureOutput(t, func() {
+		err = Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"})
+	})
+
+	if err != nil {
+		t.Errorf("Stop() in dry run error = %v, want nil", err)
+	}
+	if outputStr != "" {
+		t.Errorf("Stop() in dry run should emit no response, got: %s", outputStr)
+	}
+
+	reloaded, _ := state.Load(sessionID)
+	if reloaded.StopTriggered {
+		t.Error("StopTriggered should stay false in dry run")
+	}
+	if reloaded.Score != 50 {
+		t.Errorf("Score = %d, want 50 (recorded in dry run)", reloaded.Score)
+	}
+}
+
+// TestStopScoresFreshFromBaseline pins the scoring model: every Stop diffs
+// BaselineTree vs current and overwrites Score. Nothing accumulates between
+// calls, so the scatter penalty is applied once over the whole diff.
+func TestStopScoresFreshFromBaseline(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+	output, _ := cmd.Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	sessionID := "test-stop-fresh"
+	sess, _ := state.New(sessionID, baselineTree, "main", 1000)
+	sess.Save()
+
+	writeFiles := func(n int) {
+		for i := 0; i < n; i++ {
+			dir := filepath.Join(tmpDir, fmt.Sprintf("pkg%d", i))
+			os.MkdirAll(dir, 0755)
+			os.WriteFile(filepath.Join(dir, "f.go"), []byte("x\n"), 0644)
+		}
+	}
+	runStop := func() int {
+		captureOutput(t, func() { Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"}) })
+
+		reloaded, _ := state.Load(sessionID)
+		return reloaded.Score
+	}
+
+	// 7 new 1-line files: 7 pts + scatter (7-5)*10 = 27
+	writeFiles(7)
+
+	t.Run("repeated Stop on unchanged tree is idempotent", func(t *testing.T) {
+		first := runStop()
+		second := runStop()
+		if first != 27 || second != 27 {
+			t.Errorf("Scores = %d, %d; want 27, 27 (no double-counted scatter)", first, second)
+		}
+	})
+
+	t.Run("stored score is not an input", func(t *testing.T) {
+		sess, _ := state.Load(sessionID)
+		sess.Score = 999
+		sess.Save()
+
+		if got := runStop(); got != 27 {
+			t.Errorf("Score = %d, want 27 (recomputed, not carried over)", got)
+		}
+	})
+
+	t.Run("growth rescored over whole diff", func(t *testing.T) {
+		// 8 files: 8 pts + scatter (8-5)*10 = 38
+		writeFiles(8)
+		if got := runStop(); got != 38 {
+			t.Errorf("Score = %d, want 38 (fresh scatter over 8 files)", got)
+		}
+	})
+}
+
+func TestStopDebugTrace(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	// Logs go to $HOME/.claude/logs/bumper-lanes
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("BUMPER_LANES_LOG_DIR", "")
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+	output, _ := cmd.Output()
+	baselineTree := strings.TrimSpace(string(output))
+
+	os.WriteFile(filepath.Join(tmpDir, "file.go"), []byte(strings.Repeat("x\n", 40)), 0644)
+
+	runStop := func(sessionID string, limit int) string {
+		sess, _ := state.New(sessionID, baselineTree, "main", limit)
+		sess.Save()
+
+		captureOutput(t, func() { Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"}) })
+
+		logPath := filepath.Join(homeDir, ".claude", "logs", "bumper-lanes", "session-"+sessionID+".log")
+		data, _ := os.ReadFile(logPath)
+		return string(data)
+	}
+
+	t.Run("no trace without debug", func(t *testing.T) {
+		t.Setenv("BUMPER_LANES_DEBUG", "")
+		if logs := runStop("test-trace-off", 1000); strings.Contains(logs, "decision=") {
+			t.Errorf("expected no decision trace without debug, got:\n%s", logs)
+		}
+	})
+
+	t.Run("allow trace", func(t *testing.T) {
+		t.Setenv("BUMPER_LANES_DEBUG", "1")
+		logs := runStop("test-trace-allow", 1000)
+		for _, want := range []string{"decision=allow", "baseline=" + baselineTree, "current=", "score=40", "threshold=1000"} {
+			if !strings.Contains(logs, want) {
+				t.Errorf("trace missing %q, got:\n%s", want, logs)
+			}
+		}
+		if strings.Contains(logs, "current=(not captured)") {
+			t.Errorf("Stop trace should include the current tree, got:\n%s", logs)
+		}
+	})
+
+	t.Run("block trace", func(t *testing.T) {
+		t.Setenv("BUMPER_LANES_DEBUG", "1")
+		logs := runStop("test-trace-block", 30)
+		if !strings.Contains(logs, "decision=block") || !strings.Contains(logs, "threshold=30") {
+			t.Errorf("expected block trace, got:\n%s", logs)
+		}
+	})
+}
+
+func TestFormatOptionalBreakdown(t *testing.T) {
+	if got := formatOptionalBreakdown(&scoring.WeightedScore{Score: 10}); got != "" {
+		t.Errorf("formatOptionalBreakdown() = %q, want empty by default", got)
+	}
+	got := formatOptionalBreakdown(&scoring.WeightedScore{Score: 60, DeletionScore: 50})
+	if got != "\n- Deletions: 50 pts" {
+		t.Errorf("formatOptionalBreakdown() = %q, want deletions line", got)
+	}
+}
+
+func TestFormatScatter(t *testing.T) {
+	if got := formatScatter(&scoring.WeightedScore{ScatterPenalty: 30}); got != "30 pts" {
+		t.Errorf("formatScatter() = %q, want %q", got, "30 pts")
+	}
+	if got := formatScatter(&scoring.WeightedScore{ScatterDisabled: true}); got != "disabled" {
+		t.Errorf("formatScatter() = %q, want %q", got, "disabled")
+	}
+}
+
+func TestFormatExtensions(t *testing.T) {
+	if got := formatExtensions(nil); got != "" {
+		t.Errorf("formatExtensions(nil) = %q, want empty", got)
+	}
+	deletionsOnly := &diff.StatsJSON{Files: []diff.FileStatJSON{{Path: "old.go", Dels: 40}}}
+	if got := formatExtensions(deletionsOnly); got != "" {
+		t.Errorf("formatExtensions(deletions only) = %q, want empty", got)
+	}
+}
+
+func TestStopExtensionBreakdown(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	// Mixed diff: mostly tests, then docs, config, scripts, and a Makefile
+	files := map[string]int{
+		"core_test.go": 60,
+		"core.go":      20,
+		"README.md":    30,
+		"ci.yaml":      20,
+		"build.sh":     10,
+		"Makefile":     5,
+	}
+	for name, lines := range files {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(strings.Repeat("x\n", lines)), 0644)
+	}
+
+	runStop := func(t *testing.T, sessionID string) string {
+		t.Helper()
+		sess, _ := state.New(sessionID, baseline, "", 50)
+		sess.Save()
+		out, _ := captureOutput(t, func() {
+			Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"})
+		})
+		var resp StopResponse
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("decode Stop response %q: %v", out, err)
+		}
+		return resp.Reason
+	}
+
+	if reason := runStop(t, "test-stop-ext-off"); strings.Contains(reason, "by extension") {
+		t.Errorf("default Stop reason includes the extension breakdown:\n%s", reason)
+	}
+
+	os.WriteFile(filepath.Join(tmpDir, ".bumper-lanes.json"), []byte(`{"stop_show_extensions": true}`), 0644)
+	reason := runStop(t, "test-stop-ext-on")
+	// The config file itself is a new .json file too
+	want := "- Additions by extension: go:80 md:30 yaml:20 other:16"
+	if !strings.Contains(reason, want) {
+		t.Errorf("Stop reason missing %q:\n%s", want, reason)
+	}
+}
+
+func TestStopCooldownAfterReset(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("HOME", t.TempDir()) // Isolate from user-global config
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	// Staged scope: a reset doesn't clear staged work, so without a cooldown
+	// the very next Stop re-trips
+	os.WriteFile(".bumper-lanes.json", []byte(`{"score_scope": "staged", "cooldown_score": 30}`), 0644)
+
+	stageLines := func(n int) {
+		os.WriteFile("staged.go", []byte(strings.Repeat("x\n", n)), 0644)
+		exec.Command("git", "add", "staged.go").Run()
+	}
+	runStop := func(sessionID string) *state.SessionState {
+		captureOutput(t, func() { Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"}) })
+
+		reloaded, _ := state.Load(sessionID)
+		return reloaded
+	}
+
+	// 60 staged lines = 60 pts, over the 50 pt threshold
+	stageLines(60)
+	sessionID := "test-stop-cooldown"
+	sess, _ := state.New(sessionID, GetHeadTree(), "main", 50)
+	sess.Save()
+
+	if err := Reset(sessionID, false); err != nil {
+		t.Fatalf("Reset() error: %v", err)
+	}
+	reloaded, _ := state.Load(sessionID)
+	if reloaded.CooldownAnchor == nil || *reloaded.CooldownAnchor != 60 {
+		t.Fatalf("CooldownAnchor = %v, want 60", reloaded.CooldownAnchor)
+	}
+
+	t.Run("small post-reset change does not re-trip", func(t *testing.T) {
+		stageLines(70)
+		if got := runStop(sessionID); got.StopTriggered {
+			t.Errorf("StopTriggered = true at score %d, want cooldown to suppress", got.Score)
+		}
+	})
+
+	t.Run("large post-reset change trips", func(t *testing.T) {
+		stageLines(100)
+		if got := runStop(sessionID); !got.StopTriggered {
+			t.Errorf("StopTriggered = false at score %d, want trip past cooldown delta", got.Score)
+		}
+	})
+
+	t.Run("no cooldown configured re-trips immediately", func(t *testing.T) {
+		os.WriteFile(".bumper-lanes.json", []byte(`{"score_scope": "staged"}`), 0644)
+		stageLines(60)
+		if err := Reset(sessionID, false); err != nil {
+			t.Fatalf("Reset() error: %v", err)
+		}
+		if got := runStop(sessionID); !got.StopTriggered {
+			t.Errorf("StopTriggered = false at score %d, want immediate re-trip", got.Score)
+		}
+	})
+}
+
+func TestStopAllowOnce(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	// 100 new lines = 100 pts, over the 50 pt threshold
+	os.WriteFile(filepath.Join(tmpDir, "vendored.go"), []byte(strings.Repeat("x\n", 100)), 0644)
+
+	sessionID := "test-stop-allow-once"
+	sess, _ := state.New(sessionID, baseline, "", 50)
+	sess.Save()
+
+	if code := handleAllowOnce(sessionID); code != 0 {
+		t.Fatalf("handleAllowOnce() = %d, want 0", code)
+	}
+
+	runStop := func() *state.SessionState {
+		captureOutput(t, func() { Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"}) })
+
+		reloaded, _ := state.Load(sessionID)
+		return reloaded
+	}
+
+	first := runStop()
+	if first.StopTriggered {
+		t.Error("first Stop after allow-once tripped, want it allowed")
+	}
+	if first.AllowOnce {
+		t.Error("AllowOnce still set after the Stop consumed it")
+	}
+
+	if second := runStop(); !second.StopTriggered {
+		t.Errorf("second Stop did not trip at score %d, want enforcement back", second.Score)
+	}
+}
+
+func TestStopResetOnBranchSwitch(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    string
+		wantReset bool
+	}{
+		{"default resets", `{}`, true},
+		{"enabled resets", `{"reset_on_branch_switch": true}`, true},
+		{"disabled keeps baseline", `{"reset_on_branch_switch": false}`, false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			setupTempGitRepo(t, tmpDir)
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+			origDir, _ := os.Getwd()
+			defer os.Chdir(origDir)
+			os.Chdir(tmpDir)
+
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			os.WriteFile("work.go", []byte(strings.Repeat("x\n", 20)), 0644)
+			baseline, err := CaptureTree()
+			if err != nil {
+				t.Fatalf("CaptureTree: %v", err)
+			}
+			os.WriteFile("work.go", []byte(strings.Repeat("x\n", 40)), 0644)
+
+			sessionID := fmt.Sprintf("test-stop-branch-switch-%d", i)
+			sess, _ := state.New(sessionID, baseline, "main", 400)
+			sess.SetScore(26)
+			sess.Save()
+
+			if out, err := exec.Command("git", "checkout", "-b", "reference").CombinedOutput(); err != nil {
+				t.Fatalf("git checkout failed: %v\n%s", err, out)
+			}
+
+			captureOutput(t, func() { Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"}) })
+
+			reloaded, _ := state.Load(sessionID)
+			if tt.wantReset {
+				if reloaded.BaselineBranch != "reference" || reloaded.BaselineTree == baseline {
+					t.Errorf("baseline = %s on %s, want reset onto reference", reloaded.BaselineTree, reloaded.BaselineBranch)
+				}
+				return
+			}
+			if reloaded.BaselineBranch != "main" || reloaded.BaselineTree != baseline {
+				t.Errorf("baseline = %s on %s, want original baseline on main", reloaded.BaselineTree, reloaded.BaselineBranch)
+			}
+			if reloaded.Score == 0 {
+				t.Error("Score = 0, want the accumulated score kept across the switch")
+			}
+		})
+	}
+}
+
+func TestStopTimedPause(t *testing.T) {
+	tests := []struct {
+		name        string
+		until       time.Duration
+		wantTripped bool
+	}{
+		{"pause still running", 30 * time.Minute, false},
+		{"pause expired", -time.Minute, true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			setupTempGitRepo(t, tmpDir)
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+			origDir, _ := os.Getwd()
+			defer os.Chdir(origDir)
+			os.Chdir(tmpDir)
+
+			baseline, err := CaptureTree()
+			if err != nil {
+				t.Fatalf("CaptureTree: %v", err)
+			}
+			// 100 new lines = 100 pts, over the 50 pt threshold
+			os.WriteFile(filepath.Join(tmpDir, "big.go"), []byte(strings.Repeat("x\n", 100)), 0644)
+
+			sessionID := fmt.Sprintf("test-stop-timed-pause-%d", i)
+			sess, _ := state.New(sessionID, baseline, "", 50)
+			sess.PauseFor(tt.until, time.Now())
+			sess.Save()
+
+			captureOutput(t, func() { Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"}) })
+
+			reloaded, _ := state.Load(sessionID)
+			if reloaded.StopTriggered != tt.wantTripped {
+				t.Errorf("StopTriggered = %v, want %v", reloaded.StopTriggered, tt.wantTripped)
+			}
+		})
+	}
+}
+
+func TestStopMinEnforceScore(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	os.WriteFile(".bumper-lanes.json", []byte(`{"min_enforce_score": 100}`), 0644)
+
+	tests := []struct {
+		name      string
+		lines     int
+		wantBlock bool
+	}{
+		{"over threshold but below floor is silent", 60, false},
+		{"at or above floor blocks as usual", 120, true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile("work.txt", []byte(strings.Repeat("x\n", tt.lines)), 0644)
+			sessionID := fmt.Sprintf("test-stop-floor-%d", i)
+			sess, _ := state.New(sessionID, baseline, "main", 50)
+			sess.Save()
+
+			var stopErr error
+			out, _ := captureOutput(t, func() {
+				stopErr = Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"})
+			})
+			if stopErr != nil {
+				t.Fatalf("Stop() error: %v", stopErr)
+			}
+			reloaded, _ := state.Load(sessionID)
+			if tt.wantBlock {
+				if !strings.Contains(out, `"decision":"block"`) || !reloaded.StopTriggered {
+					t.Errorf("want block at score %d, got output %q", reloaded.Score, out)
+				}
+				return
+			}
+			if out != "" || reloaded.StopTriggered {
+				t.Errorf("want silence at score %d, got output %q (StopTriggered=%v)", reloaded.Score, out, reloaded.StopTriggered)
+			}
+		})
+	}
+}
+
+func TestStopScoreDisplay(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+
+	tests := []struct {
+		display   string
+		lines     int
+		wantBlock bool
+		want      string
+	}{
+		{"raw", 387, true, "Score: 387 / 300 points (129%)"},
+		{"rounded", 387, true, "Score: 390 / 300 points (129%)"},
+		{"percent", 387, true, "Score: 129 / 100 points (129%)"},
+		// Rounds up to the limit for display, but enforcement uses the raw 296
+		{"rounded", 296, false, ""},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.display, tt.lines), func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(fmt.Sprintf(`{"score_display": %q, "exclude": [".bumper-lanes.json"]}`, tt.display)), 0644)
+			os.WriteFile("work.txt", []byte(strings.Repeat("x\n", tt.lines)), 0644)
+			sessionID := fmt.Sprintf("test-stop-display-%d", i)
+			sess, _ := state.New(sessionID, baseline, "main", 300)
+			sess.Save()
+
+			out, _ := captureOutput(t, func() {
+				Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"})
+			})
+			blocked := strings.Contains(out, `"decision":"block"`)
+			if blocked != tt.wantBlock {
+				t.Fatalf("blocked = %v, want %v; output %q", blocked, tt.wantBlock, out)
+			}
+			if tt.want != "" && !strings.Contains(out, tt.want) {
+				t.Errorf("reason missing %q:\n%s", tt.want, out)
+			}
+			if strings.Contains(out, `"score":`) && !strings.Contains(out, fmt.Sprintf(`"score":%d`, tt.lines)) {
+				t.Errorf("threshold_data should keep the raw score %d:\n%s", tt.lines, out)
+			}
+		})
+	}
+
+	// Prompt and CLI messages use the same display as the Stop reason
+	os.WriteFile(".bumper-lanes.json", []byte(`{"score_display": "percent"}`), 0644)
+	sess, _ := state.Load("test-stop-display-2")
+	out, _ := captureOutput(t, func() {
+		HandlePrompt(&HookInput{SessionID: sess.SessionID, HookEventName: "UserPromptSubmit", Prompt: "/bumper-ack"})
+	})
+	if !strings.Contains(out, "score: 129/100") {
+		t.Errorf("/bumper-ack should show the percent score, got %q", out)
+	}
+	if info := formatSessionInfo(sess, time.Now()); !strings.Contains(info, "Score:      129/100") {
+		t.Errorf("session info should show the percent score, got:\n%s", info)
+	}
+}
+
+func TestMessagesFollowScoreDisplay(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	os.WriteFile(".bumper-lanes.json", []byte(`{"score_display": "percent", "threshold": 400, "carryover_fraction": 0.5, "exclude": [".bumper-lanes.json"]}`), 0644)
+	os.WriteFile("work.txt", []byte(strings.Repeat("x\n", 200)), 0644)
+	baseline := GetHeadTree()
+
+	prompt := func(sessionID, text string) func() string {
+		return func() string {
+			sess, _ := state.New(sessionID, baseline, "main", 400)
+			sess.Save()
+			out, _ := captureOutput(t, func() {
+				HandlePrompt(&HookInput{SessionID: sessionID, HookEventName: "UserPromptSubmit", Prompt: text})
+			})
+			return out
+		}
+	}
+	tests := []struct {
+		name string
+		run  func() string
+		want string
+	}{
+		{"reset prompt", prompt("display-reset", "/bumper-reset"), "Score: 0/100"},
+		{"tagged reset prompt", prompt("display-reset-tag", "/bumper-reset auth"), "Score: 0/100"},
+		{"check", func() string {
+			var b strings.Builder
+			Check(&b, true, false)
+			return b.String()
+		}, "50/100 pts (50%, working)"},
+		{"diff verdict", func() string {
+			line, _ := formatVerdict(500, 400)
+			return line
+		}, "OVER by 25 (125/100 pts)"},
+		{"trend", func() string { return formatTrend([]int{100, 200}, 400) }, "50/100 pts"},
+		{"commit carryover", func() string {
+			sess, _ := state.New("display-commit", baseline, "main", 400)
+			sess.SetScore(200)
+			sess.Save()
+			return captureStderr(t, func() {
+				handleBashCommit(&HookInput{SessionID: "display-commit", ToolInput: &ToolInput{Command: "git commit -m 'test commit'"}})
+			})
+		}, "25 pts carried over, 75 of 100 pts left"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.run(); !strings.Contains(got, tt.want) {
+				t.Errorf("message %q missing percent score %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStopReviewChecklist(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	os.WriteFile("work.txt", []byte(strings.Repeat("x\n", 120)), 0644)
+
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{"checklist appended in order", `{"review_checklist": ["Run the tests", "Check error paths: nil & empty", "", "Update the CHANGELOG"], "exclude": [".bumper-lanes.json"]}`,
+			[]string{"Review checklist:\n1. Run the tests\n2. Check error paths: nil & empty\n3. Update the CHANGELOG"}},
+		{"no checklist configured", `{"exclude": [".bumper-lanes.json"]}`, nil},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			sessionID := fmt.Sprintf("test-stop-checklist-%d", i)
+			sess, _ := state.New(sessionID, baseline, "main", 50)
+			sess.Save()
+
+			out, _ := captureOutput(t, func() {
+				Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"})
+			})
+			var resp StopResponse
+			if err := json.Unmarshal([]byte(out), &resp); err != nil {
+				t.Fatalf("unmarshal %q: %v", out, err)
+			}
+			if resp.Decision != "block" {
+				t.Fatalf("want a trip, got %q", out)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(resp.Reason, want) {
+					t.Errorf("reason missing %q:\n%s", want, resp.Reason)
+				}
+			}
+			if tt.want == nil && strings.Contains(resp.Reason, "Review checklist") {
+				t.Errorf("no checklist configured, but reason has one:\n%s", resp.Reason)
+			}
+		})
+	}
+}
+
+func TestStopRereadsThresholdFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	os.WriteFile(".bumper-lanes.json", []byte(`{"threshold_file": "budget.txt", "exclude": [".bumper-lanes.json", "budget.txt"]}`), 0644)
+	os.WriteFile("budget.txt", []byte("100\n"), 0644)
+	os.WriteFile("work.txt", []byte(strings.Repeat("x\n", 150)), 0644)
+
+	sess, _ := state.New("test-stop-budget", baseline, "main", 100)
+	sess.Save()
+	stop := func() string {
+		out, _ := captureOutput(t, func() {
+			Stop(&HookInput{SessionID: "test-stop-budget", HookEventName: "Stop"})
+		})
+		return out
+	}
+
+	if out := stop(); !strings.Contains(out, `"decision":"block"`) {
+		t.Fatalf("150 pts against a 100 budget should block, got %q", out)
+	}
+
+	// CI raises the budget mid-session
+	os.WriteFile("budget.txt", []byte("200\n"), 0644)
+	if out := stop(); strings.Contains(out, `"decision":"block"`) {
+		t.Errorf("150 pts against the new 200 budget should not block, got %q", out)
+	}
+	reloaded, _ := state.Load("test-stop-budget")
+	if reloaded.ThresholdLimit != 200 || reloaded.StopTriggered {
+		t.Errorf("ThresholdLimit = %d, StopTriggered = %v; want 200, false", reloaded.ThresholdLimit, reloaded.StopTriggered)
+	}
+}
+
+func TestStopAllowOnceConsumption(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTempGitRepo(t, tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	baseline, err := CaptureTree()
+	if err != nil {
+		t.Fatalf("CaptureTree: %v", err)
+	}
+	stop := func(sessionID string) *state.SessionState {
+		captureOutput(t, func() {
+			Stop(&HookInput{SessionID: sessionID, HookEventName: "Stop"})
+		})
+		reloaded, _ := state.Load(sessionID)
+		return reloaded
+	}
+
+	t.Run("under-threshold stop keeps the override", func(t *testing.T) {
+		sessionID := "test-allow-once-keep"
+		sess, _ := state.New(sessionID, baseline, "", 50)
+		sess.SetAllowOnce(true)
+		sess.Save()
+
+		os.WriteFile("work.txt", []byte(strings.Repeat("x\n", 10)), 0644)
+		if got := stop(sessionID); !got.AllowOnce {
+			t.Fatal("under-threshold Stop consumed AllowOnce, want it kept")
+		}
+
+		os.WriteFile("work.txt", []byte(strings.Repeat("x\n", 100)), 0644)
+		got := stop(sessionID)
+		if got.StopTriggered || got.AllowOnce {
+			t.Errorf("over-threshold Stop: StopTriggered = %v, AllowOnce = %v; want allowed and consumed", got.StopTriggered, got.AllowOnce)
+		}
+	})
+
+	t.Run("allowed stop clears an earlier trip", func(t *testing.T) {
+		sessionID := "test-allow-once-tripped"
+		os.WriteFile("work.txt", []byte(strings.Repeat("x\n", 100)), 0644)
+		sess, _ := state.New(sessionID, baseline, "", 50)
+		sess.Save()
+		if got := stop(sessionID); !got.StopTriggered {
+			t.Fatal("setup: Stop over threshold should trip")
+		}
+
+		captureOutput(t, func() { handleAllowOnce(sessionID) })
+		if got := stop(sessionID); got.StopTriggered {
+			t.Fatal("allowed Stop left StopTriggered set")
+		}
+		if code := PreToolUse(&HookInput{SessionID: sessionID, HookEventName: "PreToolUse", ToolName: "Write"}); code != 0 {
+			t.Errorf("PreToolUse after the allowed Stop = %d, want 0 (edits unblocked)", code)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// gitlink is a submodule entry in a tree: its path and recorded commit.
+type gitlink struct {
+	Path   string
+	Commit string
+}
+
+// treeGitlinks lists the submodules recorded in tree, via git ls-tree.
+// Returns nil on error.
+func treeGitlinks(tree string) []gitlink {
+	output, err := exec.Command("git", "ls-tree", "-r", "-z", tree).Output()
+	if err != nil {
+		return nil
+	}
+	var links []gitlink
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// "<mode> <type> <object>\t<path>"
+		meta, p, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if ok && len(fields) == 3 && fields[1] == "commit" {
+			links = append(links, gitlink{Path: p, Commit: fields[2]})
+		}
+	}
+	return links
+}
+
+// withoutGitlinks drops the submodule pointer entries from parent stats;
+// with score_submodules on, their line changes are scored instead.
+func withoutGitlinks(stats *diff.StatsJSON, links []gitlink) *diff.StatsJSON {
+	if len(links) == 0 {
+		return stats
+	}
+	skip := make(map[string]bool, len(links))
+	for _, link := range links {
+		skip[link.Path] = true
+	}
+	return scoring.FilterStats(stats, scoring.PathFilter{Skip: skip})
+}
+
+// submoduleStats diffs each submodule checkout's working tree against the
+// commit its gitlink records, so both new submodule commits and
+// uncommitted submodule work show up as line changes. Paths are prefixed
+// with the submodule path. Submodules that aren't checked out, or whose
+// recorded commit isn't available locally, are skipped. Nested submodules
+// are not followed.
+func submoduleStats(links []gitlink) *diff.StatsJSON {
+	if len(links) == 0 {
+		return nil
+	}
+	repoRoot, err := state.GetRepoPath()
+	if err != nil {
+		return nil
+	}
+
+	var all diff.DiffStats
+	for _, link := range links {
+		dir := filepath.Join(repoRoot, filepath.FromSlash(link.Path))
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			continue // Not checked out
+		}
+		currentTree, err := captureTreeIn(dir, config.LoadCountUntracked())
+		if err != nil {
+			continue
+		}
+		stats, err := gitTreeDiffStats(dir, link.Commit, currentTree, config.LoadDiffFlags())
+		if err != nil || stats == nil {
+			continue
+		}
+		for _, f := range stats.Files {
+			f.Path = path.Join(link.Path, f.Path)
+			all.Files = append(all.Files, f)
+			all.TotalAdd += f.Additions
+			all.TotalDel += f.Deletions
+			all.TotalFiles++
+		}
+	}
+	if len(all.Files) == 0 {
+		return nil
+	}
+	jsonStats := all.ToJSON()
+	return &jsonStats
+}
+
+// submoduleScore scores submodule line changes (score_submodules) and
+// applies submodule_weight. Files go through the parent's filterScoredStats,
+// so include/exclude and respect_gitattributes (matched against the
+// parent's .gitattributes) apply to prefixed submodule paths. opts are the
+// parent's scoring options; comment discounts, hunk counts, and head-line
+// weighting only cover the parent diff. Returns 0 when nothing changed.
+func submoduleScore(links []gitlink, opts scoring.Options) int {
+	stats := submoduleStats(links)
+	if stats == nil {
+		return 0
+	}
+	stats = filterScoredStats(stats)
+	opts.CommentLines = nil
+	opts.Hunks = nil
+	opts.HeadLines = nil
+	raw := scoring.CalculateWithOptions(stats, opts).Score
+	return int(math.Round(float64(raw) * config.LoadSubmoduleWeight()))
+}
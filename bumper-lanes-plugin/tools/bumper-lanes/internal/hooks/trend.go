@@ -0,0 +1,57 @@
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// sparkBlocks are the 8 sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Trend handles the trend user command.
+// It prints a sparkline of the session's recent scores against its threshold.
+func Trend(sessionID string, w io.Writer) error {
+	sess, err := state.Load(sessionID)
+	if err != nil {
+		return fmt.Errorf("no session state for %s", sessionID)
+	}
+	fmt.Fprintln(w, formatTrend(sess.ScoreHistory, sess.ThresholdLimit))
+	return nil
+}
+
+// formatTrend renders scores as a sparkline followed by the latest score,
+// e.g. "▁▂▄█ 380/400 pts (last 4 scores)".
+func formatTrend(scores []int, threshold int) string {
+	if len(scores) == 0 {
+		return "No score history yet"
+	}
+	latest := scores[len(scores)-1]
+	noun := "scores"
+	if len(scores) == 1 {
+		noun = "score"
+	}
+	return fmt.Sprintf("%s %s pts (last %d %s)", sparkline(scores, threshold), displayScore(latest, threshold), len(scores), noun)
+}
+
+// sparkline draws one block per score. Scores are scaled to the threshold,
+// or to the highest score once the threshold has been passed, so the full
+// block means "at the limit" until the session overshoots it.
+func sparkline(scores []int, threshold int) string {
+	ceiling := threshold
+	for _, s := range scores {
+		ceiling = max(ceiling, s)
+	}
+	var b strings.Builder
+	top := len(sparkBlocks) - 1
+	for _, s := range scores {
+		level := 0
+		if ceiling > 0 && s > 0 {
+			level = min(top, s*top/ceiling)
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
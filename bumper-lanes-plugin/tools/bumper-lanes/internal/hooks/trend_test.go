@@ -0,0 +1,26 @@
+package hooks
+
+import "testing"
+
+func TestFormatTrend(t *testing.T) {
+	tests := []struct {
+		name      string
+		scores    []int
+		threshold int
+		want      string
+	}{
+		{"no history", nil, 400, "No score history yet"},
+		{"single score", []int{200}, 400, "▄ 200/400 pts (last 1 score)"},
+		{"climb to the limit", []int{0, 50, 100, 200, 300, 400}, 400, "▁▁▂▄▆█ 400/400 pts (last 6 scores)"},
+		{"reset drops to the floor", []int{350, 0, 60}, 400, "▇▁▂ 60/400 pts (last 3 scores)"},
+		{"overshoot rescales to the max", []int{100, 400, 800}, 400, "▁▄█ 800/400 pts (last 3 scores)"},
+		{"no threshold", []int{0, 0}, 0, "▁▁ 0/0 pts (last 2 scores)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTrend(tt.scores, tt.threshold); got != tt.want {
+				t.Errorf("formatTrend(%v, %d) = %q, want %q", tt.scores, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+// Undo handles the undo user command.
+// It reverts the most recent baseline reset, restoring the prior baseline and score.
+func Undo(sessionID string) error {
+	sess, err := state.Load(sessionID)
+	if err != nil {
+		return fmt.Errorf("no session state for %s", sessionID)
+	}
+
+	entry, err := sess.UndoReset()
+	if err != nil {
+		return errors.New("nothing to undo: no baseline resets recorded")
+	}
+	traceDecision(logging.New(sessionID, "cli"), traceUndoAction(sess.StopTriggered), sess.BaselineTree, "", sess.Score, sess.ThresholdLimit)
+
+	if err := sess.Save(); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+
+	fmt.Printf("Reset undone. Baseline: %s, score: %s\n", shortSHA(entry.BaselineTree), displayScore(sess.Score, sess.ThresholdLimit))
+	return nil
+}
+
+// shortSHA abbreviates a tree SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,81 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+)
+
+func TestUndo(t *testing.T) {
+	if !IsGitRepo() {
+		t.Skip("Not in a git repo")
+	}
+
+	t.Run("restores baseline after reset", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		cmd := exec.Command("git", "rev-parse", "HEAD^{tree}")
+		output, _ := cmd.Output()
+		originalTree := strings.TrimSpace(string(output))
+
+		sessionID := "test-undo"
+		sess, err := state.New(sessionID, originalTree, "main", 100)
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		sess.Score = 250
+		sess.StopTriggered = true
+		sess.Save()
+
+		// Dirty the tree so reset captures a different baseline
+		os.WriteFile("new-file.txt", []byte("line 1\nline 2\n"), 0644)
+
+		if err := Reset(sessionID, false); err != nil {
+			t.Fatalf("Reset() error = %v", err)
+		}
+		afterReset, _ := state.Load(sessionID)
+		if afterReset.BaselineTree == originalTree {
+			t.Fatal("Reset() did not move the baseline")
+		}
+
+		if err := Undo(sessionID); err != nil {
+			t.Fatalf("Undo() error = %v", err)
+		}
+
+		reloaded, _ := state.Load(sessionID)
+		if reloaded.BaselineTree != originalTree {
+			t.Errorf("BaselineTree = %q, want %q (original)", reloaded.BaselineTree, originalTree)
+		}
+		if reloaded.Score != 250 {
+			t.Errorf("Score = %d, want 250", reloaded.Score)
+		}
+		if !reloaded.StopTriggered {
+			t.Error("StopTriggered should be restored to true")
+		}
+	})
+
+	t.Run("refuses when history is empty", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		setupTempGitRepo(t, tmpDir)
+
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		sessionID := "test-undo-empty"
+		sess, _ := state.New(sessionID, "abc123", "main", 400)
+		sess.Save()
+
+		if err := Undo(sessionID); err == nil {
+			t.Error("Undo() with no reset history should return error")
+		}
+	})
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
-	"github.com/kylesnowschwartz/diff-viz/v2/render"
 )
 
 // ViewShow displays the current view mode and available options.
@@ -78,12 +77,19 @@ func View(sessionID, mode, opts string) error {
 	return nil
 }
 
-// getValidModes returns the list of supported visualization modes from diff-viz.
+// getValidModes returns the list of supported visualization modes:
+// diff-viz's renderers, local ones like oneline, and any registered
+// with statusline.RegisterRenderer.
 func getValidModes() []string {
-	return render.ValidModes
+	return config.Modes()
 }
 
-// isValidMode checks if mode is valid using diff-viz's exported validation.
-func isValidMode(mode string, _ []string) bool {
-	return render.IsValidMode(mode)
+// isValidMode checks if mode is in validModes.
+func isValidMode(mode string, validModes []string) bool {
+	for _, valid := range validModes {
+		if mode == valid {
+			return true
+		}
+	}
+	return false
 }
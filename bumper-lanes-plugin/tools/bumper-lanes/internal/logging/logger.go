@@ -1,12 +1,15 @@
 // Package logging provides session-based file logging for bumper-lanes hooks.
 // Logs are written to ~/.claude/logs/bumper-lanes/session-{session_id}.log
+// (or $BUMPER_LANES_LOG_DIR/session-{session_id}.log when set).
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,21 +25,53 @@ const (
 	LevelError Level = "ERROR"
 )
 
+const (
+	// DefaultMaxLogSize is the size at which a session log is rotated.
+	// Override with BUMPER_LANES_LOG_MAX_SIZE (bytes).
+	DefaultMaxLogSize = 5 * 1024 * 1024
+
+	// MaxRotatedLogs is how many rotated files (.1 .. .N) are kept per session.
+	MaxRotatedLogs = 3
+)
+
 // Logger handles session-based file logging
 type Logger struct {
 	sessionID string
 	source    string
 	logFile   string
+	maxSize   int64
 	mu        sync.Mutex
 }
 
-var (
-	// sessionIDSanitizer replaces non-alphanumeric chars (except - and _) with _
-	sessionIDSanitizer = regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
+// sessionIDSanitizer replaces non-alphanumeric chars (except - and _) with _
+var sessionIDSanitizer = regexp.MustCompile(`[^a-zA-Z0-9\-_]`)
 
-	// debugEnabled is set by BUMPER_LANES_DEBUG=1
-	debugEnabled = os.Getenv("BUMPER_LANES_DEBUG") == "1"
-)
+// jsonEntry is one log line in BUMPER_LANES_LOG_FORMAT=json mode.
+type jsonEntry struct {
+	Timestamp string `json:"ts"`
+	Level     Level  `json:"level"`
+	Source    string `json:"source"`
+	Session   string `json:"session"`
+	Message   string `json:"msg"`
+}
+
+// jsonFormat reports whether BUMPER_LANES_LOG_FORMAT=json is set.
+// Text is the default.
+func jsonFormat() bool {
+	return os.Getenv("BUMPER_LANES_LOG_FORMAT") == "json"
+}
+
+// debugEnabled reports whether BUMPER_LANES_DEBUG=1 is set.
+// Read on each call rather than at init so tests can toggle it.
+func debugEnabled() bool {
+	return os.Getenv("BUMPER_LANES_DEBUG") == "1"
+}
+
+// DebugEnabled reports whether debug logging is on, for callers that want
+// to skip building expensive debug messages.
+func DebugEnabled() bool {
+	return debugEnabled()
+}
 
 // New creates a logger for the given session and source component
 func New(sessionID, source string) *Logger {
@@ -48,12 +83,13 @@ func New(sessionID, source string) *Logger {
 		sessionID: sessionID,
 		source:    source,
 		logFile:   logFile,
+		maxSize:   getMaxLogSize(),
 	}
 }
 
 // Debug logs a debug message (only if BUMPER_LANES_DEBUG=1)
 func (l *Logger) Debug(format string, args ...interface{}) {
-	if debugEnabled {
+	if debugEnabled() {
 		l.log(LevelDebug, format, args...)
 	}
 }
@@ -78,14 +114,26 @@ func (l *Logger) log(level Level, format string, args ...interface{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	now := time.Now()
+	timestamp := now.Format("2006-01-02 15:04:05")
 	message := fmt.Sprintf(format, args...)
 
 	var entry string
-	if strings.Contains(message, "\n") {
+	switch {
+	case jsonFormat():
+		// One object per line; multiline messages stay a single escaped string
+		data, _ := json.Marshal(jsonEntry{
+			Timestamp: now.Format(time.RFC3339),
+			Level:     level,
+			Source:    l.source,
+			Session:   l.sessionID,
+			Message:   message,
+		})
+		entry = string(data) + "\n"
+	case strings.Contains(message, "\n"):
 		// Multiline: put message on new line
 		entry = fmt.Sprintf("[%s] [%s] [%s]\n%s\n", timestamp, level, l.source, message)
-	} else {
+	default:
 		entry = fmt.Sprintf("[%s] [%s] [%s] %s\n", timestamp, level, l.source, message)
 	}
 
@@ -104,6 +152,8 @@ func (l *Logger) writeToFile(entry string) error {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 
+	l.rotateIfNeeded()
+
 	// Open file in append mode (thread-safe via mutex)
 	f, err := os.OpenFile(l.logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -119,12 +169,52 @@ func (l *Logger) writeToFile(entry string) error {
 	return f.Sync()
 }
 
-// getLogDir returns the log directory path (~/.claude/logs/bumper-lanes)
+// rotateIfNeeded shifts the log to .1 (and .1 to .2, etc.) once it reaches maxSize.
+// Costs one stat per write; the oldest rotated file is overwritten.
+func (l *Logger) rotateIfNeeded() {
+	if l.maxSize <= 0 {
+		return
+	}
+	info, err := os.Stat(l.logFile)
+	if err != nil || info.Size() < l.maxSize {
+		return
+	}
+
+	for i := MaxRotatedLogs - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", l.logFile, i), fmt.Sprintf("%s.%d", l.logFile, i+1))
+	}
+	os.Rename(l.logFile, l.logFile+".1")
+}
+
+// getMaxLogSize returns the rotation threshold from BUMPER_LANES_LOG_MAX_SIZE,
+// or DefaultMaxLogSize if unset or invalid.
+func getMaxLogSize() int64 {
+	if v := os.Getenv("BUMPER_LANES_LOG_MAX_SIZE"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultMaxLogSize
+}
+
+// fallbackLogDir is used when the preferred log directory is unavailable.
+const fallbackLogDir = "/tmp/bumper-lanes-logs"
+
+// getLogDir returns the log directory path.
+// BUMPER_LANES_LOG_DIR overrides the default ~/.claude/logs/bumper-lanes;
+// an override that can't be created falls back to /tmp.
 func getLogDir() string {
+	if dir := os.Getenv("BUMPER_LANES_LOG_DIR"); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fallbackLogDir
+		}
+		return dir
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		// Fallback to /tmp if home dir unavailable
-		return "/tmp/bumper-lanes-logs"
+		return fallbackLogDir
 	}
 	return filepath.Join(homeDir, ".claude", "logs", "bumper-lanes")
 }
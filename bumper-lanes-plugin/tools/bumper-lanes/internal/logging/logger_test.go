@@ -0,0 +1,181 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogRotation(t *testing.T) {
+	t.Run("rotates past the cap and preserves old data", func(t *testing.T) {
+		logFile := filepath.Join(t.TempDir(), "session-test.log")
+		l := &Logger{source: "test", logFile: logFile, maxSize: 200}
+
+		l.Info("first entry")
+		for i := 0; i < 20; i++ {
+			if _, err := os.Stat(logFile + ".1"); err == nil {
+				break
+			}
+			l.Info("filler entry %d with some padding to grow the file", i)
+		}
+
+		rotated, err := os.ReadFile(logFile + ".1")
+		if err != nil {
+			t.Fatalf("expected %s.1 after writing past cap: %v", filepath.Base(logFile), err)
+		}
+		if !strings.Contains(string(rotated), "first entry") {
+			t.Errorf(".1 should hold pre-rotation data, got:\n%s", rotated)
+		}
+
+		current, _ := os.ReadFile(logFile)
+		if strings.Contains(string(current), "first entry") {
+			t.Error("current log should start fresh after rotation")
+		}
+		if !strings.Contains(string(current), "filler entry") {
+			t.Errorf("current log should hold the write that triggered rotation, got:\n%s", current)
+		}
+	})
+
+	t.Run("keeps at most MaxRotatedLogs files", func(t *testing.T) {
+		logFile := filepath.Join(t.TempDir(), "session-test.log")
+		l := &Logger{source: "test", logFile: logFile, maxSize: 50}
+
+		for i := 0; i < 50; i++ {
+			l.Info("entry %d padded past the tiny cap", i)
+		}
+
+		for i := 1; i <= MaxRotatedLogs; i++ {
+			if _, err := os.Stat(fmt.Sprintf("%s.%d", logFile, i)); err != nil {
+				t.Errorf("expected rotated file .%d: %v", i, err)
+			}
+		}
+		if _, err := os.Stat(fmt.Sprintf("%s.%d", logFile, MaxRotatedLogs+1)); err == nil {
+			t.Errorf("found .%d, want at most %d rotated files", MaxRotatedLogs+1, MaxRotatedLogs)
+		}
+	})
+
+	t.Run("no rotation under the cap", func(t *testing.T) {
+		logFile := filepath.Join(t.TempDir(), "session-test.log")
+		l := &Logger{source: "test", logFile: logFile, maxSize: DefaultMaxLogSize}
+
+		l.Info("small")
+		if _, err := os.Stat(logFile + ".1"); err == nil {
+			t.Error("unexpected rotation under the cap")
+		}
+	})
+}
+
+func TestGetMaxLogSize(t *testing.T) {
+	tests := []struct {
+		env  string
+		want int64
+	}{
+		{"", DefaultMaxLogSize},
+		{"1024", 1024},
+		{"invalid", DefaultMaxLogSize},
+		{"-5", DefaultMaxLogSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("BUMPER_LANES_LOG_MAX_SIZE", tt.env)
+			if got := getMaxLogSize(); got != tt.want {
+				t.Errorf("getMaxLogSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLogDir(t *testing.T) {
+	t.Run("env override", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "custom", "logs")
+		t.Setenv("BUMPER_LANES_LOG_DIR", dir)
+
+		if got := getLogDir(); got != dir {
+			t.Errorf("getLogDir() = %q, want %q", got, dir)
+		}
+
+		l := New("env-session", "test")
+		l.Info("hello")
+		data, err := os.ReadFile(filepath.Join(dir, "session-env-session.log"))
+		if err != nil {
+			t.Fatalf("expected log in override dir: %v", err)
+		}
+		if !strings.Contains(string(data), "hello") {
+			t.Errorf("log = %q, want entry 'hello'", data)
+		}
+	})
+
+	t.Run("uncreatable override falls back to tmp", func(t *testing.T) {
+		// A path under a regular file can't be created
+		blocker := filepath.Join(t.TempDir(), "file")
+		os.WriteFile(blocker, nil, 0644)
+		t.Setenv("BUMPER_LANES_LOG_DIR", filepath.Join(blocker, "logs"))
+
+		if got := getLogDir(); got != fallbackLogDir {
+			t.Errorf("getLogDir() = %q, want %q", got, fallbackLogDir)
+		}
+	})
+
+	t.Run("default under home", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("BUMPER_LANES_LOG_DIR", "")
+		t.Setenv("HOME", home)
+
+		want := filepath.Join(home, ".claude", "logs", "bumper-lanes")
+		if got := getLogDir(); got != want {
+			t.Errorf("getLogDir() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestJSONLogFormat(t *testing.T) {
+	t.Setenv("BUMPER_LANES_LOG_FORMAT", "json")
+
+	logFile := filepath.Join(t.TempDir(), "session-json.log")
+	l := &Logger{sessionID: "json-session", source: "stop", logFile: logFile}
+
+	l.Warn("single %d", 1)
+	l.Info("line one\nline two")
+
+	data, _ := os.ReadFile(logFile)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (one per entry):\n%s", len(lines), data)
+	}
+
+	var entries []jsonEntry
+	for _, line := range lines {
+		var e jsonEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("line is not valid JSON: %v\n%s", err, line)
+		}
+		entries = append(entries, e)
+	}
+
+	if entries[0].Level != LevelWarn || entries[0].Source != "stop" || entries[0].Session != "json-session" || entries[0].Message != "single 1" {
+		t.Errorf("entry[0] = %+v", entries[0])
+	}
+	if _, err := time.Parse(time.RFC3339, entries[0].Timestamp); err != nil {
+		t.Errorf("ts %q is not RFC3339: %v", entries[0].Timestamp, err)
+	}
+	if entries[1].Message != "line one\nline two" {
+		t.Errorf("multiline msg = %q, want preserved in one field", entries[1].Message)
+	}
+}
+
+func TestTextLogFormatDefault(t *testing.T) {
+	t.Setenv("BUMPER_LANES_LOG_FORMAT", "")
+
+	logFile := filepath.Join(t.TempDir(), "session-text.log")
+	l := &Logger{source: "stop", logFile: logFile}
+	l.Info("hello")
+
+	data, _ := os.ReadFile(logFile)
+	if !strings.Contains(string(data), "[INFO] [stop] hello") {
+		t.Errorf("text log = %q, want [INFO] [stop] hello", data)
+	}
+}
@@ -0,0 +1,97 @@
+package scoring
+
+import (
+	"path"
+	"strings"
+)
+
+// Comment marker sets by language family. Block comment continuation lines
+// only count with a space after the star ("* text") or as a bare "*", so
+// pointer derefs like "*p = 1" stay code.
+var (
+	cFamilyMarkers = []string{"//", "/*", "*/", "* "}
+	hashMarkers    = []string{"#"}
+	dashMarkers    = []string{"--"}
+	markupMarkers  = []string{"<!--"}
+	cssMarkers     = []string{"/*", "*/", "* "}
+)
+
+// commentMarkersByExt maps lowercase file extensions to their comment
+// markers. Files with other extensions (including markdown, where "#" and
+// "*" start headings and bullets) get no comment discount.
+var commentMarkersByExt = map[string][]string{
+	".go": cFamilyMarkers, ".c": cFamilyMarkers, ".h": cFamilyMarkers,
+	".cc": cFamilyMarkers, ".cpp": cFamilyMarkers, ".hpp": cFamilyMarkers,
+	".cs": cFamilyMarkers, ".java": cFamilyMarkers, ".kt": cFamilyMarkers,
+	".kts": cFamilyMarkers, ".scala": cFamilyMarkers, ".swift": cFamilyMarkers,
+	".rs": cFamilyMarkers, ".dart": cFamilyMarkers, ".php": cFamilyMarkers,
+	".m": cFamilyMarkers, ".js": cFamilyMarkers, ".jsx": cFamilyMarkers,
+	".mjs": cFamilyMarkers, ".cjs": cFamilyMarkers, ".ts": cFamilyMarkers,
+	".tsx": cFamilyMarkers, ".scss": cFamilyMarkers, ".less": cFamilyMarkers,
+	".proto": cFamilyMarkers,
+
+	".sh": hashMarkers, ".bash": hashMarkers, ".zsh": hashMarkers,
+	".fish": hashMarkers, ".py": hashMarkers, ".rb": hashMarkers,
+	".pl": hashMarkers, ".r": hashMarkers, ".ps1": hashMarkers,
+	".yaml": hashMarkers, ".yml": hashMarkers, ".toml": hashMarkers,
+	".ini": hashMarkers, ".conf": hashMarkers, ".mk": hashMarkers,
+	".tf": hashMarkers, ".ex": hashMarkers, ".exs": hashMarkers,
+
+	".sql": dashMarkers, ".lua": dashMarkers, ".hs": dashMarkers,
+	".elm": dashMarkers,
+
+	".html": markupMarkers, ".xml": markupMarkers, ".vue": markupMarkers,
+	".svelte": markupMarkers,
+
+	".css": cssMarkers,
+}
+
+// commentMarkersByName covers extensionless files by base name.
+var commentMarkersByName = map[string][]string{
+	"Makefile":   hashMarkers,
+	"Dockerfile": hashMarkers,
+}
+
+// commentMarkersFor returns the comment markers for a file path, or nil
+// when its language isn't known.
+func commentMarkersFor(filePath string) []string {
+	base := path.Base(filePath)
+	if markers, ok := commentMarkersByName[base]; ok {
+		return markers
+	}
+	return commentMarkersByExt[strings.ToLower(path.Ext(base))]
+}
+
+// IsCommentLine reports whether a line of source in the file at filePath
+// starts with one of that language's comment markers. Leading whitespace is
+// ignored. A "#!" shebang is not a comment, and unknown languages have none.
+func IsCommentLine(filePath, line string) bool {
+	trimmed := strings.TrimLeft(line, " \t")
+	if strings.HasPrefix(trimmed, "#!") {
+		return false
+	}
+	markers := commentMarkersFor(filePath)
+	if trimmed == "*" && len(markers) > 0 && markers[len(markers)-1] == "* " {
+		return true
+	}
+	for _, marker := range markers {
+		if strings.HasPrefix(trimmed, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountCommentAdditions parses unified diff output (git diff -p) and returns,
+// per destination path, the number of added lines that are comments in that
+// file's language (see IsCommentLine). Only added lines are read, so any
+// context size works.
+func CountCommentAdditions(patch string) map[string]int {
+	counts := make(map[string]int)
+	walkPatch(patch, nil, func(path, text string, _ int) {
+		if IsCommentLine(path, text) {
+			counts[path]++
+		}
+	})
+	return counts
+}
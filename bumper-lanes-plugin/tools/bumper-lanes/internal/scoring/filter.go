@@ -0,0 +1,134 @@
+package scoring
+
+import (
+	"path"
+	"strings"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// PathFilter selects which files count toward the score and visualization.
+// Include is applied first (when non-empty, only matching files are kept),
+// then Exclude drops matches from what's left.
+//
+// Pattern forms:
+//   - "src/" or "src/**": everything under src
+//   - "*.go" (no slash): matched against the file's base name
+//   - "cmd/*/main.go": matched against the full path with path.Match
+//
+// Skip drops exact paths, e.g. files .gitattributes marks linguist-generated.
+type PathFilter struct {
+	Include []string
+	Exclude []string
+	Skip    map[string]bool
+}
+
+// checkpointDir is the directory bumper-lanes keeps session state in.
+const checkpointDir = "bumper-checkpoints/"
+
+// IsInternalPath reports whether p is bumper-lanes' own state: anything under
+// a bumper-checkpoints/ directory. Normally that lives in .git, but a nested
+// or misconfigured repo can surface it in the diff. Internal paths are always
+// dropped, whatever the filter says.
+func IsInternalPath(p string) bool {
+	return strings.HasPrefix(p, checkpointDir) || strings.Contains(p, "/"+checkpointDir)
+}
+
+// IsZero reports whether the filter keeps every (non-internal) file.
+func (f PathFilter) IsZero() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0 && len(f.Skip) == 0
+}
+
+// Keep reports whether a file path passes the filter.
+func (f PathFilter) Keep(p string) bool {
+	if IsInternalPath(p) || f.Skip[p] {
+		return false
+	}
+	if len(f.Include) > 0 && !matchAny(f.Include, p) {
+		return false
+	}
+	return !matchAny(f.Exclude, p)
+}
+
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a single pattern against a slash-separated path.
+func matchPattern(pattern, p string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "**"); ok {
+		return strings.HasPrefix(p, dir)
+	}
+	if strings.HasSuffix(pattern, "/") {
+		return strings.HasPrefix(p, pattern)
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+// FilterStats returns stats with only the files the filter keeps.
+// Totals are recomputed. Returns stats unchanged when nothing would be dropped
+// by a zero filter.
+func FilterStats(stats *diff.StatsJSON, f PathFilter) *diff.StatsJSON {
+	if f.IsZero() && !hasInternalJSON(stats.Files) {
+		return stats
+	}
+
+	filtered := &diff.StatsJSON{Files: []diff.FileStatJSON{}}
+	for _, file := range stats.Files {
+		if !f.Keep(file.Path) {
+			continue
+		}
+		filtered.Files = append(filtered.Files, file)
+		filtered.Totals.Adds += file.Adds
+		filtered.Totals.Dels += file.Dels
+	}
+	filtered.Totals.FileCount = len(filtered.Files)
+	return filtered
+}
+
+// FilterDiffStats is FilterStats for the renderer-facing DiffStats type.
+func FilterDiffStats(stats *diff.DiffStats, f PathFilter) *diff.DiffStats {
+	if f.IsZero() && !hasInternal(stats.Files) {
+		return stats
+	}
+
+	filtered := &diff.DiffStats{}
+	for _, file := range stats.Files {
+		if !f.Keep(file.Path) {
+			continue
+		}
+		filtered.Files = append(filtered.Files, file)
+		filtered.TotalAdd += file.Additions
+		filtered.TotalDel += file.Deletions
+	}
+	filtered.TotalFiles = len(filtered.Files)
+	return filtered
+}
+
+func hasInternalJSON(files []diff.FileStatJSON) bool {
+	for _, file := range files {
+		if IsInternalPath(file.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasInternal(files []diff.FileStat) bool {
+	for _, file := range files {
+		if IsInternalPath(file.Path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package scoring
+
+// CountHeadAdditions parses unified diff output and returns, per destination
+// path, the number of added lines that land within the first headLines
+// lines of the new file. Positions come from the "+start" of each hunk
+// header, so any context size works. Deleted files are skipped, like
+// CountHunks.
+func CountHeadAdditions(patch string, headLines int) map[string]int {
+	counts := make(map[string]int)
+	walkPatch(patch, nil, func(path, _ string, lineNo int) {
+		if lineNo <= headLines {
+			counts[path]++
+		}
+	})
+	return counts
+}
@@ -0,0 +1,11 @@
+package scoring
+
+// CountHunks parses unified diff output and returns the number of hunks per
+// destination path. Generate the patch with -U0 so every discontinuous
+// change region is its own hunk; with context lines, nearby edits merge.
+// Deleted files are skipped, like CountCommentAdditions.
+func CountHunks(patch string) map[string]int {
+	counts := make(map[string]int)
+	walkPatch(patch, func(path string, _ int) { counts[path]++ }, nil)
+	return counts
+}
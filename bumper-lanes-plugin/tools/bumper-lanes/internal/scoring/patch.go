@@ -0,0 +1,57 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// patchPath returns the destination path from a unified diff "+++ " header
+// line: "+++ b/path" gives "path", and "+++ /dev/null" (a deleted file)
+// gives "". walkPatch skips files with an empty path.
+func patchPath(line string) string {
+	path := strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+	if path == "/dev/null" {
+		return ""
+	}
+	return path
+}
+
+// walkPatch walks unified diff output (git diff -p) once, calling onHunk
+// for each hunk header with the new-file "+start", and onAdd for each added
+// line with its text (without the "+") and new-file line number. Either
+// callback may be nil. Deleted files yield nothing. The patch is split in
+// memory, so there is no line length limit: one minified bundle line can't
+// cut off the files after it.
+func walkPatch(patch string, onHunk func(path string, start int), onAdd func(path, text string, lineNo int)) {
+	var path string
+	inHeader := false
+	lineNo := 0 // New-file line number of the next added or context line
+
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHeader = true
+			path = ""
+		case inHeader && strings.HasPrefix(line, "+++ "):
+			path = patchPath(line)
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			// "@@ -a,b +c,d @@"; a +c,0 hunk (pure deletion) adds nothing
+			_, plus, _ := strings.Cut(line, " +")
+			lineNo = 0
+			fmt.Sscanf(plus, "%d", &lineNo)
+			if path != "" && onHunk != nil {
+				onHunk(path, lineNo)
+			}
+		case inHeader || path == "":
+			// File header lines, or the body of a deleted file
+		case strings.HasPrefix(line, "+"):
+			if onAdd != nil {
+				onAdd(path, line[1:], lineNo)
+			}
+			lineNo++
+		case strings.HasPrefix(line, " "):
+			lineNo++
+		}
+	}
+}
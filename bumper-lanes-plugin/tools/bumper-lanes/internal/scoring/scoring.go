@@ -2,15 +2,75 @@
 // This calculates scores from raw DiffStats JSON (from diff-viz library).
 package scoring
 
-import "github.com/kylesnowschwartz/diff-viz/v2/diff"
+import (
+	"strings"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
 
 // WeightedScore holds the bumper-lanes weighted score calculation.
 type WeightedScore struct {
-	Score          int `json:"score"`          // Total weighted score
-	NewAdditions   int `json:"new_additions"`  // Lines added in new files
-	EditAdditions  int `json:"edit_additions"` // Lines added in edited files
-	FilesTouched   int `json:"files_touched"`  // Number of files changed
-	ScatterPenalty int `json:"scatter"`        // Penalty for touching many files
+	Score            int  `json:"score"`                       // Total weighted score
+	NewAdditions     int  `json:"new_additions"`               // Lines added in new files
+	EditAdditions    int  `json:"edit_additions"`              // Lines added in edited files
+	FilesTouched     int  `json:"files_touched"`               // Number of files changed
+	ScatterPenalty   int  `json:"scatter"`                     // Penalty for touching many files
+	CommentAdditions int  `json:"comment_additions,omitempty"` // Added comment lines scored at a discount
+	DeletionScore    int  `json:"deletion_score,omitempty"`    // Points from deletions (only with DeletionWeight)
+	Hunks            int  `json:"hunks,omitempty"`             // Change regions in scored files (only with HunkWeight)
+	HunkScore        int  `json:"hunk_score,omitempty"`        // Points from hunks (only with HunkWeight)
+	HeadAdditions    int  `json:"head_additions,omitempty"`    // Added lines near the top of their file (only with HeadLinesWeight)
+	HeadScore        int  `json:"head_score,omitempty"`        // Extra points from HeadLinesWeight (negative when it discounts)
+	ScatterDisabled  bool `json:"scatter_disabled,omitempty"`  // Scatter penalty turned off via Options
+}
+
+// Options tunes the scoring formula. The zero value reproduces Calculate.
+type Options struct {
+	// CommentLines maps file path to the number of its added lines that are
+	// comments. Those lines are scored at 1/commentDiscountDivisor of the
+	// file's normal weight. Nil disables comment discounting.
+	CommentLines map[string]int
+
+	// DeletionWeight adds deletions * DeletionWeight to the score.
+	// Zero (default) keeps deletions free.
+	DeletionWeight float64
+
+	// DisableScatter zeroes the scatter penalty regardless of file count.
+	DisableScatter bool
+
+	// ScatterWeights maps file name suffixes (".md", "_test.go") to how much
+	// a file counts toward scatter. The scatter formula uses the weighted sum
+	// as its file count. The longest matching suffix wins; unmatched files
+	// count 1. Nil counts every file as 1.
+	ScatterWeights map[string]float64
+
+	// Hunks maps file path to its number of diff hunks (discontinuous change
+	// regions). Only files in the scored stats count. Used with HunkWeight.
+	Hunks map[string]int
+
+	// HunkWeight adds hunks * HunkWeight to the score, so many scattered
+	// edits cost more than one block edit of the same size. Zero (default)
+	// ignores hunks.
+	HunkWeight float64
+
+	// WeightedScatter replaces the raw file count in the scatter tiers with
+	// the smaller of two counts, so only changes that are both substantial
+	// and spread out pay the full penalty:
+	//   - size:   sum of w * min(1, adds/scatterFullLines); a one-line touch counts 0.1
+	//   - spread: (sum w*adds)^2 / sum w*adds^2, the effective number of
+	//     files; N equal changes count N, one dominant file counts about 1
+	//
+	// where w is the file's ScatterWeights weight.
+	WeightedScatter bool
+
+	// HeadLines maps file path to the number of its added lines within the
+	// first lines of the file, where public API tends to live. Used with
+	// HeadLinesWeight.
+	HeadLines map[string]int
+
+	// HeadLinesWeight scores HeadLines at this multiple of their file's
+	// normal weight, e.g. 2 doubles them. Zero (default) or 1 is off.
+	HeadLinesWeight float64
 }
 
 // Scoring constants (match threshold-calculator.sh)
@@ -22,44 +82,150 @@ const (
 	scatterPenaltyLow    = 10 // Points/file for 6-10 files
 	scatterPenaltyHigh   = 30 // Points/file for 11+ files
 	freeTier             = 5  // Files 1-5 are penalty-free
+	scatterFullLines     = 10 // Added lines at which a file counts fully toward weighted scatter
+
+	commentDiscountDivisor = 4 // Comment lines score 0.25x of their file's weight
 )
 
 // Calculate computes bumper-lanes score from raw diff stats.
 // New files get 1.0x weight, edits get 1.3x weight.
-// Deletions are ignored (they reduce complexity, not add review burden).
+// Deletions are ignored (they reduce complexity, not add review burden)
+// unless Options.DeletionWeight is set.
 func Calculate(stats *diff.StatsJSON) *WeightedScore {
-	var newAdd, editAdd int
-	var filesWithAdditions int // Only count files that add lines (not pure deletions)
+	return CalculateWithOptions(stats, Options{})
+}
+
+// CalculateWithOptions computes the score like Calculate, applying opts.
+func CalculateWithOptions(stats *diff.StatsJSON, opts Options) *WeightedScore {
+	var newAdd, editAdd, commentAdd, deletions, hunks, headAdd int
+	var commentPoints, headPoints int
+	var filesWithAdditions int     // Only count files that add lines (not pure deletions)
+	var scatterFiles float64       // filesWithAdditions weighted by ScatterWeights
+	var sizeFiles float64          // WeightedScatter: files weighted by change size
+	var sumAdds, sumSqAdds float64 // WeightedScatter: weighted adds and squared adds, for spread
 
 	for _, f := range stats.Files {
+		deletions += f.Dels
+		hunks += opts.Hunks[f.Path]
 		if f.Adds > 0 {
 			filesWithAdditions++
+			w := scatterWeight(f.Path, opts.ScatterWeights)
+			scatterFiles += w
+			sizeFiles += w * min(1, float64(f.Adds)/scatterFullLines)
+			sumAdds += w * float64(f.Adds)
+			sumSqAdds += w * float64(f.Adds) * float64(f.Adds)
+
+			weight := editFileWeight
 			if f.New {
+				weight = newFileWeight
 				newAdd += f.Adds
 			} else {
 				editAdd += f.Adds
 			}
+
+			// Comment lines are pulled out of the full-weight pool
+			comments := min(opts.CommentLines[f.Path], f.Adds)
+			commentAdd += comments
+			commentPoints += comments * weight
+
+			heads := min(opts.HeadLines[f.Path], f.Adds)
+			headAdd += heads
+			headPoints += heads * weight
 		}
 		// Files with only deletions (f.Adds == 0) don't count toward scatter
 	}
 
+	if opts.WeightedScatter {
+		spreadFiles := 0.0
+		if sumSqAdds > 0 {
+			spreadFiles = sumAdds * sumAdds / sumSqAdds
+		}
+		scatterFiles = min(sizeFiles, spreadFiles)
+	}
+
 	// Calculate scatter penalty (only for files with additions)
 	var scatter int
-	if filesWithAdditions >= scatterHighThreshold {
-		scatter = (filesWithAdditions - freeTier) * scatterPenaltyHigh
-	} else if filesWithAdditions >= scatterLowThreshold {
-		scatter = (filesWithAdditions - freeTier) * scatterPenaltyLow
+	if opts.DisableScatter {
+		scatter = 0
+	} else if scatterFiles >= scatterHighThreshold {
+		scatter = int((scatterFiles - freeTier) * scatterPenaltyHigh)
+	} else if scatterFiles >= scatterLowThreshold {
+		scatter = int((scatterFiles - freeTier) * scatterPenaltyLow)
 	}
 
 	// Weighted score: (new x 10 + edit x 13) / 10 + scatter
+	// Discounted comment lines contribute weight/commentDiscountDivisor instead
 	totalPoints := (newAdd * newFileWeight) + (editAdd * editFileWeight)
+	totalPoints -= commentPoints - commentPoints/commentDiscountDivisor
 	score := (totalPoints / 10) + scatter
 
+	// Head lines already scored once at their file's weight; add the extra
+	var headScore int
+	if opts.HeadLinesWeight > 0 && opts.HeadLinesWeight != 1 {
+		headScore = int(float64(headPoints) * (opts.HeadLinesWeight - 1) / 10)
+		score += headScore
+	} else {
+		headAdd = 0
+	}
+
+	var deletionScore int
+	if opts.DeletionWeight > 0 {
+		deletionScore = int(float64(deletions) * opts.DeletionWeight)
+		score += deletionScore
+	}
+
+	var hunkScore int
+	if opts.HunkWeight > 0 {
+		hunkScore = int(float64(hunks) * opts.HunkWeight)
+		score += hunkScore
+	} else {
+		hunks = 0
+	}
+
 	return &WeightedScore{
-		Score:          score,
-		NewAdditions:   newAdd,
-		EditAdditions:  editAdd,
-		FilesTouched:   filesWithAdditions, // Only files with additions
-		ScatterPenalty: scatter,
+		Score:            score,
+		NewAdditions:     newAdd,
+		EditAdditions:    editAdd,
+		FilesTouched:     filesWithAdditions, // Only files with additions
+		ScatterPenalty:   scatter,
+		CommentAdditions: commentAdd,
+		DeletionScore:    deletionScore,
+		Hunks:            hunks,
+		HunkScore:        hunkScore,
+		HeadAdditions:    headAdd,
+		HeadScore:        headScore,
+		ScatterDisabled:  opts.DisableScatter,
 	}
 }
+
+// scatterWeight returns how much a file counts toward scatter: the weight of
+// the longest suffix in weights that path ends with, or 1 if none match.
+func scatterWeight(path string, weights map[string]float64) float64 {
+	weight, matched := 1.0, 0
+	for suffix, w := range weights {
+		if len(suffix) > matched && strings.HasSuffix(path, suffix) {
+			weight, matched = w, len(suffix)
+		}
+	}
+	return weight
+}
+
+// ScoreNumstat scores raw `git diff --numstat` output without a live repo.
+// Paths listed in untracked are scored as new files; everything else as edits.
+// Malformed numstat lines are skipped (fail-open, like diff.ParseNumstat).
+func ScoreNumstat(numstat string, untracked []string) *WeightedScore {
+	stats, _, _ := diff.ParseNumstat(numstat)
+
+	isNew := make(map[string]bool, len(untracked))
+	for _, p := range untracked {
+		isNew[p] = true
+	}
+	for i := range stats.Files {
+		if isNew[stats.Files[i].Path] {
+			stats.Files[i].IsUntracked = true
+		}
+	}
+
+	jsonStats := stats.ToJSON()
+	return Calculate(&jsonStats)
+}
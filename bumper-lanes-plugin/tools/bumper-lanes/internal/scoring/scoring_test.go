@@ -1,6 +1,8 @@
 package scoring
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/kylesnowschwartz/diff-viz/v2/diff"
@@ -103,3 +105,580 @@ func TestCalculate(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateWithCommentDiscount(t *testing.T) {
+	// File with 10 comment lines and 10 code lines
+	tests := []struct {
+		name      string
+		file      diff.FileStatJSON
+		comments  map[string]int
+		wantScore int
+	}{
+		{
+			name:      "no discount without comment counts",
+			file:      diff.FileStatJSON{Path: "new.go", Adds: 20, New: true},
+			comments:  nil,
+			wantScore: 20,
+		},
+		{
+			name:      "new file comments at 0.25x",
+			file:      diff.FileStatJSON{Path: "new.go", Adds: 20, New: true},
+			comments:  map[string]int{"new.go": 10},
+			wantScore: 12, // (10*10 + 10*10/4) / 10 = 125 / 10 = 12
+		},
+		{
+			name:      "edited file comments at 0.25x of 1.3x",
+			file:      diff.FileStatJSON{Path: "edit.go", Adds: 20},
+			comments:  map[string]int{"edit.go": 10},
+			wantScore: 16, // (10*13 + 10*13/4) / 10 = 162 / 10 = 16
+		},
+		{
+			name:      "comment count capped at additions",
+			file:      diff.FileStatJSON{Path: "new.go", Adds: 4, New: true},
+			comments:  map[string]int{"new.go": 99},
+			wantScore: 1, // (4*10/4) / 10 = 1
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &diff.StatsJSON{Files: []diff.FileStatJSON{tt.file}}
+			got := CalculateWithOptions(stats, Options{CommentLines: tt.comments})
+			if got.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", got.Score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestCountCommentAdditions(t *testing.T) {
+	patch := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,6 @@
+ package main
++// A comment
++	// Indented comment
++func main() {}
++	*p = 1
+-// removed comment
+diff --git a/script.sh b/script.sh
+new file mode 100644
+--- /dev/null
++++ b/script.sh
+@@ -0,0 +1,4 @@
++#!/bin/sh
++# shell comment
++++counter
++echo hi
+diff --git a/notes.md b/notes.md
+new file mode 100644
+--- /dev/null
++++ b/notes.md
+@@ -0,0 +1,2 @@
++# Heading
++* bullet
+diff --git a/gone.sql b/gone.sql
+deleted file mode 100644
+--- a/gone.sql
++++ /dev/null
+@@ -1 +0,0 @@
+--- sql comment
+`
+
+	got := CountCommentAdditions(patch)
+
+	// "*p = 1" is a pointer deref, not a block comment
+	if got["main.go"] != 2 {
+		t.Errorf("main.go comments = %d, want 2", got["main.go"])
+	}
+	// "+++counter" is content (after @@), not a header; the shebang is code
+	if got["script.sh"] != 1 {
+		t.Errorf("script.sh comments = %d, want 1", got["script.sh"])
+	}
+	// Markdown headings and bullets are content
+	if got["notes.md"] != 0 {
+		t.Errorf("notes.md comments = %d, want 0", got["notes.md"])
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d files, want 2: %v", len(got), got)
+	}
+}
+
+func TestIsCommentLine(t *testing.T) {
+	tests := []struct {
+		path string
+		line string
+		want bool
+	}{
+		{"main.go", "// comment", true},
+		{"main.go", "/* block", true},
+		{"main.go", " * continuation", true},
+		{"main.go", " *", true},
+		{"main.go", " */", true},
+		{"main.go", "*p = 1", false},
+		{"main.go", "# not go", false},
+		{"main.c", "#include <stdio.h>", false},
+		{"lib/app.py", "# comment", true},
+		{"lib/app.py", "x = 1  # trailing", false},
+		{"run.sh", "#!/bin/bash", false},
+		{"config.yml", "  # comment", true},
+		{"Makefile", "# comment", true},
+		{"query.sql", "-- comment", true},
+		{"query.sql", "// not sql", false},
+		{"index.html", "<!-- comment -->", true},
+		{"README.md", "# Heading", false},
+		{"README.md", "* bullet", false},
+		{"data.txt", "// anything", false},
+		{"Main.JAVA", "// comment", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsCommentLine(tt.path, tt.line); got != tt.want {
+			t.Errorf("IsCommentLine(%q, %q) = %v, want %v", tt.path, tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCountHunks(t *testing.T) {
+	patch := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -3 +3 @@ func a() {
+-old
++new
+@@ -10,0 +11,2 @@ func b() {
++added
++@@ not a hunk header
+@@ -20 +21,0 @@
+-removed
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,3 @@
++package x
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+`
+
+	got := CountHunks(patch)
+	want := map[string]int{"main.go": 3, "new.go": 1}
+	if len(got) != len(want) || got["main.go"] != 3 || got["new.go"] != 1 {
+		t.Errorf("CountHunks() = %v, want %v", got, want)
+	}
+}
+
+func TestCalculateWithHunkWeight(t *testing.T) {
+	stats := &diff.StatsJSON{
+		Files: []diff.FileStatJSON{
+			{Path: "block.go", Adds: 10},
+			{Path: "scattered.go", Adds: 10},
+		},
+	}
+	hunks := map[string]int{"block.go": 1, "scattered.go": 5, "filtered-out.go": 7}
+
+	if result := CalculateWithOptions(stats, Options{Hunks: hunks}); result.HunkScore != 0 || result.Hunks != 0 {
+		t.Errorf("without HunkWeight: HunkScore = %d, Hunks = %d; want 0, 0", result.HunkScore, result.Hunks)
+	}
+
+	result := CalculateWithOptions(stats, Options{Hunks: hunks, HunkWeight: 1.5})
+	// 20 edit adds * 1.3 = 26, plus 6 hunks * 1.5 = 9; filtered-out.go isn't in stats
+	if result.Hunks != 6 || result.HunkScore != 9 || result.Score != 35 {
+		t.Errorf("Hunks = %d, HunkScore = %d, Score = %d; want 6, 9, 35", result.Hunks, result.HunkScore, result.Score)
+	}
+}
+
+func TestCountHeadAdditions(t *testing.T) {
+	patch := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,4 @@
++// Package doc
++import "fmt"
+ package main
+ 
+@@ -9,0 +12,2 @@ func a() {
++straddles the head
++below the head
+@@ -90 +92 @@ func b() {
+-old
++far down
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,14 @@
+` + strings.Repeat("+x\n", 14) + `diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+`
+
+	got := CountHeadAdditions(patch, 12)
+	want := map[string]int{"main.go": 3, "new.go": 12}
+	if len(got) != len(want) || got["main.go"] != 3 || got["new.go"] != 12 {
+		t.Errorf("CountHeadAdditions() = %v, want %v", got, want)
+	}
+}
+
+func TestWalkPatch(t *testing.T) {
+	patch := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -3,2 +3,3 @@ func a() {
+ context
++added
+-removed
++added too
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`
+	var hunks, adds []string
+	walkPatch(patch,
+		func(path string, start int) { hunks = append(hunks, fmt.Sprintf("%s@%d", path, start)) },
+		func(path, text string, lineNo int) { adds = append(adds, fmt.Sprintf("%s:%d:%s", path, lineNo, text)) })
+
+	if want := []string{"main.go@3"}; fmt.Sprint(hunks) != fmt.Sprint(want) {
+		t.Errorf("hunks = %v, want %v", hunks, want)
+	}
+	if want := []string{"main.go:4:added", "main.go:5:added too"}; fmt.Sprint(adds) != fmt.Sprint(want) {
+		t.Errorf("adds = %v, want %v", adds, want)
+	}
+}
+
+func TestPatchCountersOverlongLine(t *testing.T) {
+	// A minified bundle line well past bufio.Scanner's 1 MiB cap must not
+	// drop the counts for files after it
+	patch := "diff --git a/bundle.js b/bundle.js\n--- /dev/null\n+++ b/bundle.js\n@@ -0,0 +1 @@\n+" +
+		strings.Repeat("x", 2<<20) + "\n" + `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -0,0 +1,2 @@
++// comment
++code
+`
+	if got := CountHunks(patch); got["main.go"] != 1 || got["bundle.js"] != 1 {
+		t.Errorf("CountHunks() = %v, want one hunk each", got)
+	}
+	if got := CountCommentAdditions(patch); got["main.go"] != 1 {
+		t.Errorf("CountCommentAdditions() = %v, want main.go 1", got)
+	}
+	if got := CountHeadAdditions(patch, 50); got["main.go"] != 2 || got["bundle.js"] != 1 {
+		t.Errorf("CountHeadAdditions() = %v, want main.go 2, bundle.js 1", got)
+	}
+}
+
+func TestCalculateWithHeadLinesWeight(t *testing.T) {
+	stats := &diff.StatsJSON{
+		Files: []diff.FileStatJSON{
+			{Path: "api.go", Adds: 10},
+			{Path: "new.go", Adds: 20, New: true},
+		},
+	}
+	heads := map[string]int{"api.go": 10, "new.go": 5}
+
+	if result := CalculateWithOptions(stats, Options{HeadLines: heads}); result.HeadScore != 0 || result.HeadAdditions != 0 || result.Score != 33 {
+		t.Errorf("without HeadLinesWeight: HeadScore = %d, HeadAdditions = %d, Score = %d; want 0, 0, 33", result.HeadScore, result.HeadAdditions, result.Score)
+	}
+
+	result := CalculateWithOptions(stats, Options{HeadLines: heads, HeadLinesWeight: 2})
+	// 10 edit adds * 1.3 + 20 new adds = 33, plus the head lines once more:
+	// 10 * 1.3 + 5 * 1.0 = 18
+	if result.HeadAdditions != 15 || result.HeadScore != 18 || result.Score != 51 {
+		t.Errorf("HeadAdditions = %d, HeadScore = %d, Score = %d; want 15, 18, 51", result.HeadAdditions, result.HeadScore, result.Score)
+	}
+}
+
+func TestPathFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter PathFilter
+		path   string
+		keep   bool
+	}{
+		{"zero filter keeps all", PathFilter{}, "any/file.go", true},
+		{"include dir slash", PathFilter{Include: []string{"src/"}}, "src/a/b.go", true},
+		{"include dir doublestar", PathFilter{Include: []string{"src/**"}}, "src/b.go", true},
+		{"include misses", PathFilter{Include: []string{"src/"}}, "docs/readme.md", false},
+		{"include basename glob", PathFilter{Include: []string{"*.go"}}, "deep/pkg/x.go", true},
+		{"include basename glob misses", PathFilter{Include: []string{"*.go"}}, "deep/pkg/x.md", false},
+		{"include full path glob", PathFilter{Include: []string{"cmd/*/main.go"}}, "cmd/tool/main.go", true},
+		{"exclude only", PathFilter{Exclude: []string{"vendor/"}}, "vendor/lib.go", false},
+		{"exclude only keeps others", PathFilter{Exclude: []string{"vendor/"}}, "main.go", true},
+		{"include then exclude", PathFilter{Include: []string{"src/"}, Exclude: []string{"*_test.go"}}, "src/a_test.go", false},
+		{"include then exclude keeps", PathFilter{Include: []string{"src/"}, Exclude: []string{"*_test.go"}}, "src/a.go", true},
+		{"exclude can't re-add outside include", PathFilter{Include: []string{"src/"}, Exclude: []string{"docs/"}}, "lib/a.go", false},
+		{"checkpoints dropped by zero filter", PathFilter{}, "bumper-checkpoints/session-abc", false},
+		{"nested checkpoints dropped", PathFilter{}, "sub/.git/bumper-checkpoints/session-abc", false},
+		{"checkpoints dropped despite include", PathFilter{Include: []string{"*"}}, "bumper-checkpoints/stats-cache.json", false},
+		{"similar name kept", PathFilter{}, "my-bumper-checkpoints/x.go", true},
+		{"skip exact path", PathFilter{Skip: map[string]bool{"api.pb.go": true}}, "api.pb.go", false},
+		{"skip is not a prefix", PathFilter{Skip: map[string]bool{"api": true}}, "api/server.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Keep(tt.path); got != tt.keep {
+				t.Errorf("Keep(%q) = %v, want %v", tt.path, got, tt.keep)
+			}
+		})
+	}
+}
+
+func TestFilterStats(t *testing.T) {
+	stats := &diff.StatsJSON{
+		Files: []diff.FileStatJSON{
+			{Path: "src/a.go", Adds: 10, Dels: 1, New: true},
+			{Path: "src/a_test.go", Adds: 20},
+			{Path: "docs/guide.md", Adds: 30, Dels: 5},
+		},
+		Totals: diff.TotalsJSON{Adds: 60, Dels: 6, FileCount: 3},
+	}
+
+	t.Run("include only", func(t *testing.T) {
+		got := FilterStats(stats, PathFilter{Include: []string{"src/"}})
+		if len(got.Files) != 2 || got.Totals.Adds != 30 || got.Totals.Dels != 1 || got.Totals.FileCount != 2 {
+			t.Errorf("FilterStats() = %+v, want src/ files with totals 30/1/2", got)
+		}
+		// (10*10 + 20*13) / 10 = 36
+		if score := Calculate(got).Score; score != 36 {
+			t.Errorf("Score = %d, want 36", score)
+		}
+	})
+
+	t.Run("include and exclude", func(t *testing.T) {
+		got := FilterStats(stats, PathFilter{Include: []string{"src/"}, Exclude: []string{"*_test.go"}})
+		if len(got.Files) != 1 || got.Files[0].Path != "src/a.go" || got.Totals.Adds != 10 {
+			t.Errorf("FilterStats() = %+v, want only src/a.go", got)
+		}
+	})
+
+	t.Run("zero filter returns input", func(t *testing.T) {
+		if got := FilterStats(stats, PathFilter{}); got != stats {
+			t.Error("zero filter should return stats unchanged")
+		}
+	})
+
+	t.Run("checkpoint files never count", func(t *testing.T) {
+		leaky := &diff.StatsJSON{Files: append([]diff.FileStatJSON{
+			{Path: ".git/bumper-checkpoints/session-abc", Adds: 40, New: true},
+			{Path: "bumper-checkpoints/stats-cache.json", Adds: 500, New: true},
+		}, stats.Files...)}
+
+		got := FilterStats(leaky, PathFilter{})
+		if len(got.Files) != 3 || got.Totals.Adds != 60 {
+			t.Errorf("FilterStats() = %+v, want checkpoint paths dropped", got)
+		}
+		if a, b := Calculate(got).Score, Calculate(stats).Score; a != b {
+			t.Errorf("Score with checkpoints = %d, want %d", a, b)
+		}
+
+		diffStats := &diff.DiffStats{Files: []diff.FileStat{{Path: "bumper-checkpoints/session-x", Additions: 9}}}
+		if got := FilterDiffStats(diffStats, PathFilter{}); got.TotalFiles != 0 {
+			t.Errorf("FilterDiffStats() kept %d checkpoint files, want 0", got.TotalFiles)
+		}
+	})
+
+	t.Run("does not mutate input", func(t *testing.T) {
+		FilterStats(stats, PathFilter{Exclude: []string{"docs/"}})
+		if len(stats.Files) != 3 || stats.Totals.Adds != 60 {
+			t.Errorf("input mutated: %+v", stats)
+		}
+	})
+}
+
+func TestCalculateWithDeletionWeight(t *testing.T) {
+	stats := &diff.StatsJSON{
+		Files: []diff.FileStatJSON{
+			{Path: "edited.go", Adds: 10, Dels: 40},
+			{Path: "removed.go", Adds: 0, Dels: 60},
+		},
+	}
+
+	t.Run("deletions free by default", func(t *testing.T) {
+		result := Calculate(stats)
+		// 10 edit adds * 1.3 = 13
+		if result.Score != 13 || result.DeletionScore != 0 {
+			t.Errorf("Score = %d, DeletionScore = %d; want 13, 0", result.Score, result.DeletionScore)
+		}
+	})
+
+	t.Run("deletions add cost when weighted", func(t *testing.T) {
+		result := CalculateWithOptions(stats, Options{DeletionWeight: 0.5})
+		// 100 deletions * 0.5 = 50, plus 13
+		if result.DeletionScore != 50 {
+			t.Errorf("DeletionScore = %d, want 50", result.DeletionScore)
+		}
+		if result.Score != 63 {
+			t.Errorf("Score = %d, want 63", result.Score)
+		}
+		// Pure-deletion files still don't count toward scatter
+		if result.FilesTouched != 1 {
+			t.Errorf("FilesTouched = %d, want 1", result.FilesTouched)
+		}
+	})
+}
+
+func TestCalculateWithScatterDisabled(t *testing.T) {
+	stats := &diff.StatsJSON{}
+	for i := 0; i < 20; i++ {
+		stats.Files = append(stats.Files, diff.FileStatJSON{Path: fmt.Sprintf("file%d.go", i), Adds: 1})
+	}
+
+	if result := Calculate(stats); result.ScatterPenalty != 450 {
+		t.Fatalf("ScatterPenalty = %d, want 450 by default", result.ScatterPenalty)
+	}
+
+	result := CalculateWithOptions(stats, Options{DisableScatter: true})
+	if result.ScatterPenalty != 0 {
+		t.Errorf("ScatterPenalty = %d, want 0 when disabled", result.ScatterPenalty)
+	}
+	// 20 edit adds * 1.3 = 26
+	if result.Score != 26 {
+		t.Errorf("Score = %d, want 26", result.Score)
+	}
+	if result.FilesTouched != 20 || !result.ScatterDisabled {
+		t.Errorf("FilesTouched = %d, ScatterDisabled = %v; want 20, true", result.FilesTouched, result.ScatterDisabled)
+	}
+}
+
+func TestCalculateWithScatterWeights(t *testing.T) {
+	files := func(n int, pattern string) []diff.FileStatJSON {
+		var out []diff.FileStatJSON
+		for i := 0; i < n; i++ {
+			out = append(out, diff.FileStatJSON{Path: fmt.Sprintf(pattern, i), Adds: 1})
+		}
+		return out
+	}
+	weights := map[string]float64{"_test.go": 0.5, ".md": 0.25}
+
+	tests := []struct {
+		name        string
+		files       []diff.FileStatJSON
+		weights     map[string]float64
+		wantScatter int
+	}{
+		// 6 files: (6-5)*10
+		{"6 test files unweighted", files(6, "pkg/f%d_test.go"), nil, 10},
+		// 6 * 0.5 = 3 effective files: free tier
+		{"6 test files at 0.5", files(6, "pkg/f%d_test.go"), weights, 0},
+		// 12 files: (12-5)*30
+		{"mixed unweighted", append(files(6, "pkg/f%d.go"), files(6, "pkg/f%d_test.go")...), nil, 210},
+		// 6 + 6*0.5 = 9 effective: (9-5)*10
+		{"mixed weighted", append(files(6, "pkg/f%d.go"), files(6, "pkg/f%d_test.go")...), weights, 40},
+		// _test.go beats a shorter .go entry
+		{"longest suffix wins", files(6, "f%d_test.go"), map[string]float64{".go": 2, "_test.go": 0.5}, 0},
+		// 24 * 0.25 = 6 effective: (6-5)*10
+		{"fractional penalty", files(24, "docs/p%d.md"), weights, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &diff.StatsJSON{Files: tt.files}
+			result := CalculateWithOptions(stats, Options{ScatterWeights: tt.weights})
+			if result.ScatterPenalty != tt.wantScatter {
+				t.Errorf("ScatterPenalty = %d, want %d", result.ScatterPenalty, tt.wantScatter)
+			}
+			if result.FilesTouched != len(tt.files) {
+				t.Errorf("FilesTouched = %d, want %d (unweighted)", result.FilesTouched, len(tt.files))
+			}
+		})
+	}
+}
+
+func TestCalculateWithWeightedScatter(t *testing.T) {
+	files := func(n, adds int, pattern string) []diff.FileStatJSON {
+		var out []diff.FileStatJSON
+		for i := 0; i < n; i++ {
+			out = append(out, diff.FileStatJSON{Path: fmt.Sprintf(pattern, i), Adds: adds})
+		}
+		return out
+	}
+	concentrated := func(big, n, adds int) []diff.FileStatJSON {
+		return append([]diff.FileStatJSON{{Path: "core.go", Adds: big}}, files(n, adds, "f%d.go")...)
+	}
+
+	tests := []struct {
+		name         string
+		files        []diff.FileStatJSON
+		weights      map[string]float64
+		wantCount    int // ScatterPenalty in count mode
+		wantWeighted int // ScatterPenalty with WeightedScatter
+	}{
+		// Spread evenly: size 6, spread 6 -> (6-5)*10, same as count
+		{"6 files spread", files(6, 100, "f%d.go"), nil, 10, 10},
+		// One-line touches: size 6*0.1 = 0.6 -> free tier
+		{"6 tiny files", files(6, 1, "f%d.go"), nil, 10, 0},
+		// One dominant file: spread 550^2/250500 ~ 1.2 -> free tier
+		{"6 files concentrated", concentrated(500, 5, 10), nil, 10, 0},
+		// 12 even files: (12-5)*30 either way
+		{"12 files spread", files(12, 50, "f%d.go"), nil, 210, 210},
+		// spread 1220^2/1004400 ~ 1.5 -> free tier
+		{"12 files concentrated", concentrated(1000, 11, 20), nil, 210, 0},
+		// Scatter weights still apply: 12 * 0.5 = 6 -> (6-5)*10
+		{"12 test files at 0.5", files(12, 50, "f%d_test.go"), map[string]float64{"_test.go": 0.5}, 10, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &diff.StatsJSON{Files: tt.files}
+			count := CalculateWithOptions(stats, Options{ScatterWeights: tt.weights})
+			if count.ScatterPenalty != tt.wantCount {
+				t.Errorf("count ScatterPenalty = %d, want %d", count.ScatterPenalty, tt.wantCount)
+			}
+			weighted := CalculateWithOptions(stats, Options{ScatterWeights: tt.weights, WeightedScatter: true})
+			if weighted.ScatterPenalty != tt.wantWeighted {
+				t.Errorf("weighted ScatterPenalty = %d, want %d", weighted.ScatterPenalty, tt.wantWeighted)
+			}
+			if weighted.FilesTouched != len(tt.files) {
+				t.Errorf("FilesTouched = %d, want %d (unweighted)", weighted.FilesTouched, len(tt.files))
+			}
+		})
+	}
+}
+
+func TestScoreNumstat(t *testing.T) {
+	numstat := "10\t0\tnew.go\n10\t5\tedited.go\n-\t-\timage.png\nnot a numstat line\n"
+
+	tests := []struct {
+		name      string
+		untracked []string
+		wantNew   int
+		wantEdit  int
+		wantScore int
+	}{
+		{"no untracked treats all as edits", nil, 0, 20, 26},
+		{"untracked path scored as new", []string{"new.go"}, 10, 10, 23},
+		{"unknown untracked path ignored", []string{"missing.go"}, 0, 20, 26},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ScoreNumstat(numstat, tt.untracked)
+			if result.NewAdditions != tt.wantNew || result.EditAdditions != tt.wantEdit {
+				t.Errorf("NewAdditions = %d, EditAdditions = %d; want %d, %d",
+					result.NewAdditions, result.EditAdditions, tt.wantNew, tt.wantEdit)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", result.Score, tt.wantScore)
+			}
+			if result.FilesTouched != 2 {
+				t.Errorf("FilesTouched = %d, want 2", result.FilesTouched)
+			}
+		})
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
@@ -16,27 +18,92 @@ import (
 )
 
 // SessionState represents the persisted state for a bumper-lanes session.
+//
+// Scoring is always fresh from BaselineTree: every hook diffs baseline vs
+// current and overwrites Score. There is no incremental accumulation (no
+// previous tree, no running total), so the scatter penalty is computed once
+// over the whole diff and reverting changes lowers the score. Score is a
+// cache of the last calculation, not an input to the next one.
+//
+// Revision guards against lost updates between concurrent hooks: Save
+// re-reads the file, and if another process saved since this state was
+// loaded, fields this process didn't touch take the on-disk value.
 type SessionState struct {
-	SessionID           string `json:"session_id"`
-	BaselineTree        string `json:"baseline_tree"`
-	BaselineBranch      string `json:"baseline_branch,omitempty"`
-	Score               int    `json:"score"` // Current score (fresh calculation from baseline)
-	CreatedAt           string `json:"created_at"`
-	ThresholdLimit      int    `json:"threshold_limit"`
-	RepoPath            string `json:"repo_path"`
-	StopTriggered       bool   `json:"stop_triggered"`
-	Paused              bool   `json:"paused,omitempty"`
-	ViewMode            string `json:"view_mode,omitempty"`
-	ViewOpts            string `json:"view_opts,omitempty"`              // Additional flags like "--width 100"
-	ShowDiffVizOverride *bool  `json:"show_diff_viz_override,omitempty"` // nil=use config, true=force show
+	SessionID           string       `json:"session_id"`
+	BaselineTree        string       `json:"baseline_tree"`
+	BaselineBranch      string       `json:"baseline_branch,omitempty"`
+	Score               int          `json:"score"` // Current score (fresh calculation from baseline)
+	CreatedAt           string       `json:"created_at"`
+	LastResetAt         string       `json:"last_reset_at,omitempty"` // Updated by ResetBaseline; empty until first reset
+	ThresholdLimit      int          `json:"threshold_limit"`
+	RepoPath            string       `json:"repo_path"`
+	StopTriggered       bool         `json:"stop_triggered"`
+	Paused              bool         `json:"paused,omitempty"`
+	PausedUntil         string       `json:"paused_until,omitempty"` // RFC3339 auto-resume time for a timed pause; empty=until /bumper-resume
+	AllowOnce           bool         `json:"allow_once,omitempty"`   // Next enforcing Stop passes regardless of score, then clears
+	ViewMode            string       `json:"view_mode,omitempty"`
+	ViewOpts            string       `json:"view_opts,omitempty"`              // Additional flags like "--width 100"
+	Widget              string       `json:"widget,omitempty"`                 // Status line widget for a bare `status` (all, indicator, diff-tree); ""=all
+	ShowDiffVizOverride *bool        `json:"show_diff_viz_override,omitempty"` // nil=use config, true=force show
+	ResetHistory        []ResetEntry `json:"reset_history,omitempty"`          // Most recent last, capped at MaxResetHistory
+	CooldownAnchor      *int         `json:"cooldown_anchor,omitempty"`        // Score right after the last reset; nil=no cooldown
+	Carryover           int          `json:"carryover,omitempty"`              // Points a commit auto-reset carried into this baseline; added to fresh scores
+	Tag                 string       `json:"tag,omitempty"`                    // Task name for the current baseline; cleared on reset
+	LastHead            string       `json:"last_head,omitempty"`              // HEAD commit at session start or the last commit auto-reset
+	PreCommitHead       string       `json:"pre_commit_head,omitempty"`        // HEAD PreToolUse saw just before a git commit command; cleared by PostToolUse
+	LastGaugeTier       string       `json:"last_gauge_tier,omitempty"`        // Tier of the last fuel gauge message shown; cleared on reset
+	LastGaugeMessageAt  string       `json:"last_gauge_message_at,omitempty"`  // RFC3339 time the last fuel gauge message was shown
+	ResetConfirmAt      string       `json:"reset_confirm_at,omitempty"`       // RFC3339 time an unconfirmed reset asked to be re-issued; cleared on reset
+	ScoreHistory        []int        `json:"score_history,omitempty"`          // Recent score changes, most recent last, capped at MaxScoreHistory; kept across resets
+	Revision            int          `json:"revision,omitempty"`               // Incremented on every Save
+
+	base *SessionState // Snapshot as loaded/saved; nil for states from New
 }
 
+// ResetEntry records the pre-reset state so a baseline reset can be undone.
+type ResetEntry struct {
+	BaselineTree   string `json:"baseline_tree"`
+	BaselineBranch string `json:"baseline_branch,omitempty"`
+	Score          int    `json:"score"`
+	StopTriggered  bool   `json:"stop_triggered"`
+	LastResetAt    string `json:"last_reset_at,omitempty"`
+	Tag            string `json:"tag,omitempty"`
+	Carryover      int    `json:"carryover,omitempty"`
+	ResetAt        string `json:"reset_at"`
+	Event          string `json:"event,omitempty"` // What triggered the reset (ResetEvent*); empty for manual and other resets
+}
+
+// Reset events recorded in ResetEntry.Event by the commit auto-reset.
+const (
+	ResetEventCommit = "commit"
+	ResetEventAmend  = "amend"
+)
+
+// MaxResetHistory is the number of reset entries kept per session.
+const MaxResetHistory = 10
+
+// MaxScoreHistory is the number of scores kept in ScoreHistory.
+const MaxScoreHistory = 32
+
 // ErrNoSession is returned when the session state file doesn't exist.
 var ErrNoSession = errors.New("no session state found")
 
+// ErrInvalidSessionID is returned for session IDs that can't name a state
+// file: empty, or containing a path separator or "..".
+var ErrInvalidSessionID = errors.New("invalid session id")
+
+// ErrNoResetHistory is returned when there is no reset to undo.
+var ErrNoResetHistory = errors.New("no reset history to undo")
+
 // GetCheckpointDir returns the absolute path to the checkpoint directory.
-// Handles git worktrees where .git is a file, not a directory.
+// Handles git worktrees where .git is a file, not a directory, and the
+// repo_root override.
 func GetCheckpointDir() (string, error) {
+	if root, err := config.RepoRootOverride(); err != nil {
+		return "", err
+	} else if root != nil {
+		return filepath.Join(root.GitDir, "bumper-checkpoints"), nil
+	}
 	cmd := exec.Command("git", "rev-parse", "--absolute-git-dir")
 	output, err := cmd.Output()
 	if err != nil {
@@ -46,8 +113,13 @@ func GetCheckpointDir() (string, error) {
 	return filepath.Join(gitDir, "bumper-checkpoints"), nil
 }
 
-// GetRepoPath returns the repository root path.
+// GetRepoPath returns the repository root path, honoring the repo_root override.
 func GetRepoPath() (string, error) {
+	if root, err := config.RepoRootOverride(); err != nil {
+		return "", err
+	} else if root != nil {
+		return root.Root, nil
+	}
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
@@ -56,8 +128,20 @@ func GetRepoPath() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// ValidateSessionID rejects IDs that would escape the checkpoint dir when
+// joined into a state file path. IDs come from hook stdin and import files.
+func ValidateSessionID(sessionID string) error {
+	if sessionID == "" || strings.ContainsAny(sessionID, `/\`) || strings.Contains(sessionID, "..") {
+		return fmt.Errorf("%w: %q", ErrInvalidSessionID, sessionID)
+	}
+	return nil
+}
+
 // stateFilePath returns the path to the state file for a session.
 func stateFilePath(sessionID string) (string, error) {
+	if err := ValidateSessionID(sessionID); err != nil {
+		return "", err
+	}
 	checkpointDir, err := GetCheckpointDir()
 	if err != nil {
 		return "", err
@@ -73,12 +157,9 @@ func Load(sessionID string) (*SessionState, error) {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(path)
+	data, err := readStateFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, ErrNoSession
-		}
-		return nil, fmt.Errorf("reading state file: %w", err)
+		return nil, err
 	}
 
 	var state SessionState
@@ -86,11 +167,46 @@ func Load(sessionID string) (*SessionState, error) {
 		return nil, fmt.Errorf("parsing state file: %w", err)
 	}
 
+	// Separate decode so the snapshot shares no slices or pointers with state
+	var base SessionState
+	json.Unmarshal(data, &base)
+	state.base = &base
+
 	return &state, nil
 }
 
+// readStateFile reads a state file. Returns ErrNoSession if it doesn't exist.
+func readStateFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoSession
+		}
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+	return data, nil
+}
+
+// mergeConcurrent adopts on-disk values for every field this state hasn't
+// changed since it was loaded. Fields changed on both sides keep ours
+// (last writer wins per field, not per file).
+func (s *SessionState) mergeConcurrent(disk *SessionState) {
+	ours := reflect.ValueOf(s).Elem()
+	base := reflect.ValueOf(s.base).Elem()
+	theirs := reflect.ValueOf(disk).Elem()
+	for i := 0; i < ours.NumField(); i++ {
+		if !ours.Type().Field(i).IsExported() {
+			continue
+		}
+		if reflect.DeepEqual(ours.Field(i).Interface(), base.Field(i).Interface()) {
+			ours.Field(i).Set(theirs.Field(i))
+		}
+	}
+}
+
 // Save writes session state to disk atomically.
-// Uses temp file + rename to prevent race conditions.
+// Uses temp file + rename to prevent corruption, and merges with the on-disk
+// state if another process saved since this one loaded (see Revision).
 func (s *SessionState) Save() error {
 	path, err := stateFilePath(s.SessionID)
 	if err != nil {
@@ -103,6 +219,17 @@ func (s *SessionState) Save() error {
 		return fmt.Errorf("creating checkpoint dir: %w", err)
 	}
 
+	// Optimistic concurrency: someone saved after we loaded
+	revision := s.Revision
+	if s.base != nil {
+		var disk SessionState
+		if data, err := readStateFile(path); err == nil && json.Unmarshal(data, &disk) == nil && disk.Revision > s.base.Revision {
+			s.mergeConcurrent(&disk)
+			revision = disk.Revision
+		}
+	}
+	s.Revision = revision + 1
+
 	// Marshal to JSON with indentation for readability
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
@@ -131,6 +258,11 @@ func (s *SessionState) Save() error {
 		return fmt.Errorf("renaming temp file: %w", err)
 	}
 
+	// Later Saves from this state merge against what we just wrote
+	var snapshot SessionState
+	if json.Unmarshal(data, &snapshot) == nil {
+		s.base = &snapshot
+	}
 	return nil
 }
 
@@ -168,27 +300,259 @@ func (s *SessionState) SetStopTriggered(triggered bool) {
 	s.StopTriggered = triggered
 }
 
-// SetPaused updates the paused flag.
+// SetPaused updates the paused flag. Pausing this way has no end time,
+// and both pausing and resuming clear any timed pause.
 func (s *SessionState) SetPaused(paused bool) {
 	s.Paused = paused
+	s.PausedUntil = ""
+}
+
+// PauseFor pauses enforcement until now+d, after which IsPaused reports false.
+func (s *SessionState) PauseFor(d time.Duration, now time.Time) {
+	s.Paused = true
+	s.PausedUntil = now.Add(d).UTC().Format(time.RFC3339)
+}
+
+// IsPaused reports whether enforcement is paused at now. A timed pause
+// auto-resumes once PausedUntil passes; an unparseable PausedUntil counts
+// as paused (fail open).
+func (s *SessionState) IsPaused(now time.Time) bool {
+	if !s.Paused {
+		return false
+	}
+	if s.PausedUntil == "" {
+		return true
+	}
+	until, err := time.Parse(time.RFC3339, s.PausedUntil)
+	if err != nil {
+		return true
+	}
+	return now.Before(until)
+}
+
+// PauseRemaining returns how long a timed pause has left at now,
+// or 0 for indefinite, expired, or no pause.
+func (s *SessionState) PauseRemaining(now time.Time) time.Duration {
+	if !s.Paused || s.PausedUntil == "" {
+		return 0
+	}
+	until, err := time.Parse(time.RFC3339, s.PausedUntil)
+	if err != nil {
+		return 0
+	}
+	return max(0, until.Sub(now))
+}
+
+// SetAllowOnce sets or clears the one-shot Stop override.
+func (s *SessionState) SetAllowOnce(allow bool) {
+	s.AllowOnce = allow
 }
 
 // SetScore updates the current score (fresh calculation from baseline).
+// Each change is appended to ScoreHistory; repeats of the last score are not.
 func (s *SessionState) SetScore(score int) {
 	s.Score = score
+	if n := len(s.ScoreHistory); n > 0 && s.ScoreHistory[n-1] == score {
+		return
+	}
+	s.ScoreHistory = append(s.ScoreHistory, score)
+	if len(s.ScoreHistory) > MaxScoreHistory {
+		s.ScoreHistory = s.ScoreHistory[len(s.ScoreHistory)-MaxScoreHistory:]
+	}
 }
 
 // ResetBaseline resets the baseline to a new tree SHA.
-// Clears score and stop_triggered.
+// Clears score, stop_triggered, and tag. The previous values are pushed onto
+// ResetHistory so the reset can be reverted with UndoReset.
 func (s *SessionState) ResetBaseline(newTree, newBranch string) {
+	s.ResetHistory = append(s.ResetHistory, ResetEntry{
+		BaselineTree:   s.BaselineTree,
+		BaselineBranch: s.BaselineBranch,
+		Score:          s.Score,
+		StopTriggered:  s.StopTriggered,
+		LastResetAt:    s.LastResetAt,
+		Tag:            s.Tag,
+		Carryover:      s.Carryover,
+		ResetAt:        time.Now().UTC().Format(time.RFC3339),
+	})
+	if len(s.ResetHistory) > MaxResetHistory {
+		s.ResetHistory = s.ResetHistory[len(s.ResetHistory)-MaxResetHistory:]
+	}
+
 	s.BaselineTree = newTree
-	s.Score = 0
+	s.SetScore(0)
 	s.StopTriggered = false
+	s.CooldownAnchor = nil
+	s.Carryover = 0
+	s.Tag = ""
+	s.LastGaugeTier = ""
+	s.LastGaugeMessageAt = ""
+	s.ResetConfirmAt = ""
+	s.LastResetAt = time.Now().UTC().Format(time.RFC3339)
 	if newBranch != "" {
 		s.BaselineBranch = newBranch
 	}
 }
 
+// Age returns how long the current baseline has been accumulating changes:
+// time since the last reset, or since session creation if never reset.
+// Returns 0 if neither timestamp can be parsed.
+func (s *SessionState) Age(now time.Time) time.Duration {
+	anchor := s.LastResetAt
+	if anchor == "" {
+		anchor = s.CreatedAt
+	}
+	t, err := time.Parse(time.RFC3339, anchor)
+	if err != nil {
+		return 0
+	}
+	if age := now.Sub(t); age > 0 {
+		return age
+	}
+	return 0
+}
+
+// FormatAge formats a duration compactly for display (e.g., "45s", "12m", "2h15m", "3d").
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		hours := int(d.Hours())
+		mins := int(d.Minutes()) % 60
+		if mins == 0 {
+			return fmt.Sprintf("%dh", hours)
+		}
+		return fmt.Sprintf("%dh%dm", hours, mins)
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours())/24)
+	}
+}
+
+// UndoReset pops the most recent reset entry and restores the baseline,
+// score, stop_triggered, and tag values captured before that reset.
+// Returns ErrNoResetHistory if there is nothing to undo.
+func (s *SessionState) UndoReset() (*ResetEntry, error) {
+	if len(s.ResetHistory) == 0 {
+		return nil, ErrNoResetHistory
+	}
+
+	last := s.ResetHistory[len(s.ResetHistory)-1]
+	s.ResetHistory = s.ResetHistory[:len(s.ResetHistory)-1]
+
+	s.BaselineTree = last.BaselineTree
+	s.BaselineBranch = last.BaselineBranch
+	s.SetScore(last.Score) // Recorded so trend ends at the restored score
+	s.StopTriggered = last.StopTriggered
+	s.LastResetAt = last.LastResetAt
+	s.Tag = last.Tag
+	s.Carryover = last.Carryover
+	s.CooldownAnchor = nil
+	return &last, nil
+}
+
+// SetLastResetEvent records what triggered the most recent reset.
+// No-op when there is no reset history.
+func (s *SessionState) SetLastResetEvent(event string) {
+	if len(s.ResetHistory) > 0 {
+		s.ResetHistory[len(s.ResetHistory)-1].Event = event
+	}
+}
+
+// SetWidget sets the status line widget used when status runs without --widget.
+func (s *SessionState) SetWidget(widget string) {
+	s.Widget = widget
+}
+
+// SetTag names the task the current baseline tracks. Empty clears it.
+func (s *SessionState) SetTag(tag string) {
+	s.Tag = tag
+}
+
+// CarryOver keeps part of the pre-reset score after a commit auto-reset.
+// Call after ResetBaseline: the new baseline starts at
+// floor(prevScore * fraction) instead of 0, and that offset stays added to
+// every fresh score until the next reset. Fractions outside (0, 1] carry nothing.
+func (s *SessionState) CarryOver(prevScore int, fraction float64) {
+	if fraction <= 0 || fraction > 1 || prevScore <= 0 {
+		return
+	}
+	s.Carryover = int(math.Floor(float64(prevScore) * fraction))
+	s.Score = s.Carryover
+}
+
+// StartCooldown anchors the post-reset cooldown at the given score.
+func (s *SessionState) StartCooldown(score int) {
+	s.CooldownAnchor = &score
+}
+
+// InCooldown reports whether score is still within minDelta points of the
+// cooldown anchor. Always false when minDelta <= 0 or no cooldown is active.
+func (s *SessionState) InCooldown(score, minDelta int) bool {
+	return minDelta > 0 && s.CooldownAnchor != nil && score-*s.CooldownAnchor < minDelta
+}
+
+// Fuel gauge tiers, in escalating order.
+const (
+	GaugeTierNotice  = "NOTICE"
+	GaugeTierWarning = "WARNING"
+)
+
+// gaugeTierRank orders tiers so escalation can be detected. Unknown is 0.
+func gaugeTierRank(tier string) int {
+	switch tier {
+	case GaugeTierNotice:
+		return 1
+	case GaugeTierWarning:
+		return 2
+	}
+	return 0
+}
+
+// ShouldShowGauge reports whether a fuel gauge message of the given tier
+// should be shown at now. Messages are suppressed while a message of the
+// same or higher tier was shown less than window ago; an escalation always
+// shows. A zero window never suppresses.
+func (s *SessionState) ShouldShowGauge(tier string, now time.Time, window time.Duration) bool {
+	if window <= 0 || s.LastGaugeMessageAt == "" {
+		return true
+	}
+	if gaugeTierRank(tier) > gaugeTierRank(s.LastGaugeTier) {
+		return true
+	}
+	last, err := time.Parse(time.RFC3339, s.LastGaugeMessageAt)
+	if err != nil {
+		return true
+	}
+	return now.Sub(last) >= window
+}
+
+// RecordGauge notes that a fuel gauge message of the given tier was shown at now.
+func (s *SessionState) RecordGauge(tier string, now time.Time) {
+	s.LastGaugeTier = tier
+	s.LastGaugeMessageAt = now.UTC().Format(time.RFC3339)
+}
+
+// RequestResetConfirmation marks a reset as waiting for confirmation from now.
+func (s *SessionState) RequestResetConfirmation(now time.Time) {
+	s.ResetConfirmAt = now.UTC().Format(time.RFC3339)
+}
+
+// ResetConfirmationPending reports whether a reset asked for confirmation
+// less than window before now, so re-issuing it now confirms.
+func (s *SessionState) ResetConfirmationPending(now time.Time, window time.Duration) bool {
+	if s.ResetConfirmAt == "" {
+		return false
+	}
+	asked, err := time.Parse(time.RFC3339, s.ResetConfirmAt)
+	if err != nil {
+		return false
+	}
+	return now.Sub(asked) < window
+}
+
 // SetViewMode sets the visualization mode.
 func (s *SessionState) SetViewMode(mode string) {
 	s.ViewMode = mode
@@ -231,6 +595,59 @@ func (s *SessionState) ShouldShowDiffViz() bool {
 	return config.LoadShowDiffViz()
 }
 
+// LoadAll reads every session state file in the checkpoint dir.
+// Unparseable files are skipped. A missing checkpoint dir yields no sessions.
+func LoadAll() ([]*SessionState, error) {
+	return loadSessions(0, time.Time{})
+}
+
+// LoadRecent is LoadAll restricted to sessions whose state file was written
+// within maxIdle of now, which leaves out sessions that ended or crashed
+// without their file being cleaned up.
+func LoadRecent(maxIdle time.Duration, now time.Time) ([]*SessionState, error) {
+	return loadSessions(maxIdle, now)
+}
+
+// loadSessions reads the checkpoint dir's session files, skipping ones last
+// written more than maxIdle before now when maxIdle is positive.
+func loadSessions(maxIdle time.Duration, now time.Time) ([]*SessionState, error) {
+	checkpointDir, err := GetCheckpointDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(checkpointDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*SessionState
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "session-") || strings.HasSuffix(name, ".tmp") || strings.HasSuffix(name, ".lock") || entry.IsDir() {
+			continue
+		}
+		if maxIdle > 0 {
+			if info, err := entry.Info(); err != nil || now.Sub(info.ModTime()) > maxIdle {
+				continue
+			}
+		}
+		data, err := os.ReadFile(filepath.Join(checkpointDir, name))
+		if err != nil {
+			continue
+		}
+		var state SessionState
+		if json.Unmarshal(data, &state) != nil {
+			continue
+		}
+		sessions = append(sessions, &state)
+	}
+	return sessions, nil
+}
+
 // CheckpointWarningThreshold is the number of checkpoint files that triggers a warning.
 const CheckpointWarningThreshold = 100
 
@@ -257,6 +674,37 @@ func CountCheckpoints() int {
 	return count
 }
 
+// PruneIdle deletes every session state file other than keep's whose
+// last write is more than maxIdle before now, and returns how many it
+// removed. Errors are ignored (fail-open).
+func PruneIdle(keep string, maxIdle time.Duration, now time.Time) int {
+	checkpointDir, err := GetCheckpointDir()
+	if err != nil {
+		return 0
+	}
+
+	entries, err := os.ReadDir(checkpointDir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "session-") || strings.HasSuffix(name, ".tmp") || strings.HasSuffix(name, ".lock") || entry.IsDir() || name == "session-"+keep {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= maxIdle {
+			continue
+		}
+		if os.Remove(filepath.Join(checkpointDir, name)) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
 // CheckpointCountWarning returns a warning message if checkpoint count exceeds threshold.
 // Returns empty string if count is acceptable.
 func CheckpointCountWarning() string {
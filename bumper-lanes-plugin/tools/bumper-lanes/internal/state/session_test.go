@@ -2,12 +2,16 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 )
 
 func TestSessionState_SaveLoad(t *testing.T) {
@@ -56,6 +60,35 @@ func TestSessionState_SaveLoad(t *testing.T) {
 	}
 }
 
+// TestSessionState_NoIncrementalFields guards against reintroducing
+// incremental scoring state. Legacy files with previous_tree/accumulated_score
+// load fine, and those fields are dropped on the next save.
+func TestSessionState_NoIncrementalFields(t *testing.T) {
+	legacy := `{
+  "session_id": "legacy",
+  "baseline_tree": "abc123",
+  "previous_tree": "def456",
+  "accumulated_score": 300,
+  "score": 120,
+  "threshold_limit": 400
+}`
+
+	var state SessionState
+	if err := json.Unmarshal([]byte(legacy), &state); err != nil {
+		t.Fatalf("Failed to unmarshal legacy state: %v", err)
+	}
+	if state.Score != 120 {
+		t.Errorf("Score = %d, want 120", state.Score)
+	}
+
+	data, _ := json.Marshal(&state)
+	for _, field := range []string{"previous_tree", "accumulated_score"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("marshaled state contains %q; scoring must stay fresh-from-baseline", field)
+		}
+	}
+}
+
 func TestSessionState_ResetBaseline(t *testing.T) {
 	state := &SessionState{
 		SessionID:     "test-123",
@@ -201,3 +234,490 @@ func TestCheckpointCountWarning(t *testing.T) {
 		t.Errorf("Warning should contain cleanup command: %q", warning)
 	}
 }
+
+func TestSessionState_UndoReset(t *testing.T) {
+	state := &SessionState{
+		SessionID:      "test-123",
+		BaselineTree:   "old-tree",
+		BaselineBranch: "main",
+		StopTriggered:  true,
+	}
+	state.SetScore(450)
+
+	if _, err := state.UndoReset(); err != ErrNoResetHistory {
+		t.Errorf("UndoReset() with empty history error = %v, want ErrNoResetHistory", err)
+	}
+
+	state.ResetBaseline("new-tree", "feature-branch")
+	if len(state.ResetHistory) != 1 {
+		t.Fatalf("len(ResetHistory) = %d, want 1", len(state.ResetHistory))
+	}
+
+	entry, err := state.UndoReset()
+	if err != nil {
+		t.Fatalf("UndoReset() error = %v", err)
+	}
+	if entry.BaselineTree != "old-tree" {
+		t.Errorf("entry.BaselineTree = %q, want %q", entry.BaselineTree, "old-tree")
+	}
+	if state.BaselineTree != "old-tree" {
+		t.Errorf("BaselineTree = %q, want %q", state.BaselineTree, "old-tree")
+	}
+	if state.BaselineBranch != "main" {
+		t.Errorf("BaselineBranch = %q, want %q", state.BaselineBranch, "main")
+	}
+	if state.Score != 450 {
+		t.Errorf("Score = %d, want 450", state.Score)
+	}
+	if want := []int{450, 0, 450}; fmt.Sprint(state.ScoreHistory) != fmt.Sprint(want) {
+		t.Errorf("ScoreHistory = %v, want %v (the restored score is the latest point)", state.ScoreHistory, want)
+	}
+	if !state.StopTriggered {
+		t.Error("StopTriggered = false, want true")
+	}
+	if len(state.ResetHistory) != 0 {
+		t.Errorf("len(ResetHistory) = %d, want 0 after undo", len(state.ResetHistory))
+	}
+}
+
+func TestSessionState_ResetHistoryCapped(t *testing.T) {
+	state := &SessionState{BaselineTree: "tree-0"}
+
+	for i := 1; i <= MaxResetHistory+5; i++ {
+		state.ResetBaseline(fmt.Sprintf("tree-%d", i), "")
+	}
+
+	if len(state.ResetHistory) != MaxResetHistory {
+		t.Fatalf("len(ResetHistory) = %d, want %d", len(state.ResetHistory), MaxResetHistory)
+	}
+	// Oldest entries are dropped first
+	if got := state.ResetHistory[0].BaselineTree; got != "tree-5" {
+		t.Errorf("oldest entry BaselineTree = %q, want %q", got, "tree-5")
+	}
+}
+
+func TestSessionState_LastResetAt(t *testing.T) {
+	state := &SessionState{
+		BaselineTree: "old-tree",
+		CreatedAt:    "2025-01-01T00:00:00Z",
+	}
+
+	if state.LastResetAt != "" {
+		t.Fatalf("LastResetAt = %q, want empty before first reset", state.LastResetAt)
+	}
+
+	before := time.Now().UTC().Add(-time.Second)
+	state.ResetBaseline("new-tree", "")
+
+	resetAt, err := time.Parse(time.RFC3339, state.LastResetAt)
+	if err != nil {
+		t.Fatalf("LastResetAt %q not RFC3339: %v", state.LastResetAt, err)
+	}
+	if resetAt.Before(before) {
+		t.Errorf("LastResetAt = %v, want >= %v", resetAt, before)
+	}
+
+	// Undo restores the previous (empty) reset timestamp
+	state.UndoReset()
+	if state.LastResetAt != "" {
+		t.Errorf("LastResetAt after undo = %q, want empty", state.LastResetAt)
+	}
+}
+
+func TestSessionState_CarryOver(t *testing.T) {
+	state := &SessionState{BaselineTree: "tree", Score: 333}
+
+	state.ResetBaseline("commit-1", "")
+	state.CarryOver(333, 0.25)
+	if state.Carryover != 83 || state.Score != 83 {
+		t.Errorf("CarryOver(333, 0.25): Carryover = %d, Score = %d, want 83 (floored)", state.Carryover, state.Score)
+	}
+
+	state.Score = 120
+	state.ResetBaseline("commit-2", "")
+	if state.Carryover != 0 {
+		t.Errorf("Carryover = %d after reset, want 0", state.Carryover)
+	}
+	if _, err := state.UndoReset(); err != nil {
+		t.Fatalf("UndoReset() error = %v", err)
+	}
+	if state.Carryover != 83 {
+		t.Errorf("Carryover = %d after undo, want 83 restored", state.Carryover)
+	}
+
+	state.ResetBaseline("commit-3", "")
+	state.CarryOver(120, 0)
+	if state.Carryover != 0 || state.Score != 0 {
+		t.Errorf("CarryOver(120, 0): Carryover = %d, Score = %d, want 0", state.Carryover, state.Score)
+	}
+}
+
+func TestSessionState_InCooldown(t *testing.T) {
+	state := &SessionState{BaselineTree: "tree"}
+
+	if state.InCooldown(100, 50) {
+		t.Error("InCooldown() = true before any cooldown started")
+	}
+
+	state.StartCooldown(200)
+	tests := []struct {
+		score, minDelta int
+		want            bool
+	}{
+		{200, 50, true},  // No change since reset
+		{249, 50, true},  // Small climb
+		{250, 50, false}, // Climbed the full delta
+		{200, 0, false},  // Cooldown disabled in config
+	}
+	for _, tt := range tests {
+		if got := state.InCooldown(tt.score, tt.minDelta); got != tt.want {
+			t.Errorf("InCooldown(%d, %d) = %v, want %v", tt.score, tt.minDelta, got, tt.want)
+		}
+	}
+
+	state.ResetBaseline("new-tree", "")
+	if state.CooldownAnchor != nil {
+		t.Errorf("CooldownAnchor = %d after reset, want nil", *state.CooldownAnchor)
+	}
+}
+
+func TestSessionState_IsPaused(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		paused        bool
+		pausedUntil   string
+		want          bool
+		wantRemaining time.Duration
+	}{
+		{"not paused", false, "", false, 0},
+		{"indefinite", true, "", true, 0},
+		{"until future", true, now.Add(30 * time.Minute).Format(time.RFC3339), true, 30 * time.Minute},
+		{"until past", true, now.Add(-time.Minute).Format(time.RFC3339), false, 0},
+		{"unparseable fails open", true, "garbage", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SessionState{Paused: tt.paused, PausedUntil: tt.pausedUntil}
+			if got := s.IsPaused(now); got != tt.want {
+				t.Errorf("IsPaused() = %v, want %v", got, tt.want)
+			}
+			if got := s.PauseRemaining(now); got != tt.wantRemaining {
+				t.Errorf("PauseRemaining() = %v, want %v", got, tt.wantRemaining)
+			}
+		})
+	}
+
+	s := &SessionState{}
+	s.PauseFor(time.Hour, now)
+	if !s.IsPaused(now.Add(59*time.Minute)) || s.IsPaused(now.Add(time.Hour)) {
+		t.Errorf("PauseFor(1h): paused window wrong, PausedUntil = %q", s.PausedUntil)
+	}
+	s.SetPaused(true)
+	if s.PausedUntil != "" {
+		t.Errorf("SetPaused(true) left PausedUntil = %q, want indefinite pause", s.PausedUntil)
+	}
+}
+
+func TestSessionState_ResetConfirmationPending(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	window := 2 * time.Minute
+
+	s := &SessionState{}
+	if s.ResetConfirmationPending(now, window) {
+		t.Error("pending with no request, want false")
+	}
+	s.RequestResetConfirmation(now)
+	if !s.ResetConfirmationPending(now.Add(time.Minute), window) {
+		t.Error("not pending inside the window, want true")
+	}
+	if s.ResetConfirmationPending(now.Add(window), window) {
+		t.Error("pending once the window passed, want false")
+	}
+	s.ResetBaseline("tree", "main")
+	if s.ResetConfirmAt != "" {
+		t.Errorf("ResetBaseline left ResetConfirmAt = %q", s.ResetConfirmAt)
+	}
+}
+
+func TestSessionState_ShouldShowGauge(t *testing.T) {
+	state := &SessionState{BaselineTree: "tree"}
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := time.Minute
+
+	if !state.ShouldShowGauge(GaugeTierNotice, start, window) {
+		t.Error("ShouldShowGauge() = false before any message was shown")
+	}
+
+	state.RecordGauge(GaugeTierNotice, start)
+	tests := []struct {
+		name   string
+		tier   string
+		after  time.Duration
+		window time.Duration
+		want   bool
+	}{
+		{"same tier inside window", GaugeTierNotice, 30 * time.Second, window, false},
+		{"same tier after window", GaugeTierNotice, time.Minute, window, true},
+		{"escalation inside window", GaugeTierWarning, time.Second, window, true},
+		{"suppression disabled", GaugeTierNotice, time.Second, 0, true},
+	}
+	for _, tt := range tests {
+		if got := state.ShouldShowGauge(tt.tier, start.Add(tt.after), tt.window); got != tt.want {
+			t.Errorf("%s: ShouldShowGauge() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	state.RecordGauge(GaugeTierWarning, start)
+	if state.ShouldShowGauge(GaugeTierNotice, start.Add(time.Second), window) {
+		t.Error("ShouldShowGauge() = true for a lower tier inside the window")
+	}
+
+	state.ResetBaseline("new-tree", "")
+	if state.LastGaugeTier != "" || state.LastGaugeMessageAt != "" {
+		t.Errorf("gauge fields = %q/%q after reset, want empty", state.LastGaugeTier, state.LastGaugeMessageAt)
+	}
+}
+
+func TestSessionState_Tag(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	if out, err := exec.Command("git", "init").CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	sess, _ := New("tagged", "tree", "main", 400)
+	sess.SetTag("auth-refactor")
+	if err := sess.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := Load("tagged")
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if loaded.Tag != "auth-refactor" {
+		t.Fatalf("Tag = %q after reload, want auth-refactor", loaded.Tag)
+	}
+
+	loaded.ResetBaseline("new-tree", "")
+	if loaded.Tag != "" {
+		t.Errorf("Tag = %q after reset, want empty", loaded.Tag)
+	}
+
+	loaded.UndoReset()
+	if loaded.Tag != "auth-refactor" {
+		t.Errorf("Tag = %q after undo, want auth-refactor", loaded.Tag)
+	}
+}
+
+func TestSessionState_Age(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		createdAt   string
+		lastResetAt string
+		want        time.Duration
+	}{
+		{"since creation when never reset", "2025-01-01T10:00:00Z", "", 2 * time.Hour},
+		{"since last reset", "2025-01-01T10:00:00Z", "2025-01-01T11:48:00Z", 12 * time.Minute},
+		{"unparseable timestamps", "garbage", "", 0},
+		{"future timestamp clamps to zero", "2025-01-01T13:00:00Z", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &SessionState{CreatedAt: tt.createdAt, LastResetAt: tt.lastResetAt}
+			if got := state.Age(now); got != tt.want {
+				t.Errorf("Age() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{2 * time.Hour, "2h"},
+		{2*time.Hour + 15*time.Minute, "2h15m"},
+		{75 * time.Hour, "3d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatAge(tt.d); got != tt.want {
+				t.Errorf("FormatAge(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionState_ConcurrentSaveMerges(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldWd)
+
+	if out, err := exec.Command("git", "init").CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	initial, _ := New("concurrent", "tree", "main", 400)
+	if err := initial.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	// Two hooks load the same revision...
+	postToolUse, _ := Load("concurrent")
+	statusline, _ := Load("concurrent")
+
+	// ...and each update a different field
+	postToolUse.SetScore(150)
+	if err := postToolUse.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	statusline.SetViewMode("icicle")
+	if err := statusline.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	got, _ := Load("concurrent")
+	if got.Score != 150 {
+		t.Errorf("Score = %d, want 150 (lost update)", got.Score)
+	}
+	if got.ViewMode != "icicle" {
+		t.Errorf("ViewMode = %q, want icicle (lost update)", got.ViewMode)
+	}
+	if got.Revision != 3 {
+		t.Errorf("Revision = %d, want 3", got.Revision)
+	}
+
+	t.Run("same field changed on both sides keeps last writer", func(t *testing.T) {
+		a, _ := Load("concurrent")
+		b, _ := Load("concurrent")
+		a.SetScore(200)
+		a.Save()
+		b.SetScore(250)
+		b.Save()
+
+		if got, _ := Load("concurrent"); got.Score != 250 {
+			t.Errorf("Score = %d, want 250", got.Score)
+		}
+	})
+
+	t.Run("repeated saves from one state stay consistent", func(t *testing.T) {
+		a, _ := Load("concurrent")
+		a.SetScore(10)
+		a.Save()
+		a.SetPaused(true)
+		a.Save()
+
+		got, _ := Load("concurrent")
+		if got.Score != 10 || !got.Paused {
+			t.Errorf("Score = %d, Paused = %v; want 10, true", got.Score, got.Paused)
+		}
+	})
+}
+
+func TestCheckpointDirRepoRootOverride(t *testing.T) {
+	// A working tree whose git dir lives elsewhere, so rev-parse can't find it
+	base := t.TempDir()
+	root := filepath.Join(base, "work")
+	gitDir := filepath.Join(base, "store")
+	os.MkdirAll(root, 0755)
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("a\n"), 0644)
+	for _, args := range [][]string{{"init", "-q", root}, {"-C", root, "add", "a.txt"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@test.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.Rename(filepath.Join(root, ".git"), gitDir); err != nil {
+		t.Fatal(err)
+	}
+
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	os.MkdirAll(filepath.Join(xdg, "bumper-lanes"), 0755)
+	cfg := fmt.Sprintf(`{"repo_root": %q, "git_dir": %q}`, root, gitDir)
+	os.WriteFile(filepath.Join(xdg, "bumper-lanes", "config.json"), []byte(cfg), 0644)
+	config.ResetRepoRootOverride()
+	t.Cleanup(config.ResetRepoRootOverride)
+
+	oldWd, _ := os.Getwd()
+	os.Chdir(root)
+	defer os.Chdir(oldWd)
+
+	dir, err := GetCheckpointDir()
+	if err != nil || dir != filepath.Join(gitDir, "bumper-checkpoints") {
+		t.Fatalf("GetCheckpointDir() = %q, %v, want under %s", dir, err, gitDir)
+	}
+	if repo, err := GetRepoPath(); err != nil || repo != root {
+		t.Errorf("GetRepoPath() = %q, %v, want %q", repo, err, root)
+	}
+
+	s, err := New("override-session", "abc123", "main", 600)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "bumper-checkpoints", "session-override-session")); err != nil {
+		t.Errorf("session file not written to pinned git dir: %v", err)
+	}
+	if loaded, err := Load("override-session"); err != nil || loaded.BaselineTree != "abc123" {
+		t.Errorf("Load() = %+v, %v", loaded, err)
+	}
+}
+
+func TestSessionState_ScoreHistory(t *testing.T) {
+	s := &SessionState{}
+	for _, score := range []int{10, 10, 40, 40, 90} {
+		s.SetScore(score)
+	}
+	s.ResetBaseline("tree", "main")
+	if want := []int{10, 40, 90, 0}; fmt.Sprint(s.ScoreHistory) != fmt.Sprint(want) {
+		t.Errorf("ScoreHistory = %v, want %v (repeats dropped, reset recorded)", s.ScoreHistory, want)
+	}
+
+	for i := 1; i <= MaxScoreHistory+5; i++ {
+		s.SetScore(i)
+	}
+	if len(s.ScoreHistory) != MaxScoreHistory {
+		t.Fatalf("len(ScoreHistory) = %d, want cap %d", len(s.ScoreHistory), MaxScoreHistory)
+	}
+	if last := s.ScoreHistory[MaxScoreHistory-1]; last != MaxScoreHistory+5 {
+		t.Errorf("newest score = %d, want %d", last, MaxScoreHistory+5)
+	}
+}
+
+func TestValidateSessionID(t *testing.T) {
+	for _, id := range []string{"abc-123", "2f1c9e0a-7b3d-4e6f", "v1.2"} {
+		if err := ValidateSessionID(id); err != nil {
+			t.Errorf("ValidateSessionID(%q) = %v, want nil", id, err)
+		}
+	}
+	for _, id := range []string{"", "../../config", "a/b", `a\b`, "..", "x..y"} {
+		if err := ValidateSessionID(id); !errors.Is(err, ErrInvalidSessionID) {
+			t.Errorf("ValidateSessionID(%q) = %v, want ErrInvalidSessionID", id, err)
+		}
+		// Every state file entry point refuses it before touching disk
+		if _, err := Load(id); !errors.Is(err, ErrInvalidSessionID) {
+			t.Errorf("Load(%q) = %v, want ErrInvalidSessionID", id, err)
+		}
+		if err := (&SessionState{SessionID: id}).Save(); !errors.Is(err, ErrInvalidSessionID) {
+			t.Errorf("Save(%q) = %v, want ErrInvalidSessionID", id, err)
+		}
+	}
+}
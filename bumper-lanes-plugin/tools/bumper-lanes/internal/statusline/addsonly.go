@@ -0,0 +1,23 @@
+package statusline
+
+import "github.com/kylesnowschwartz/diff-viz/v2/diff"
+
+// addsOnlyOpt is the view option that hides files whose only changes are
+// deletions, matching the default scoring model where deletions are free.
+const addsOnlyOpt = "--adds-only"
+
+// dropDeletionOnly returns stats without the files that have deletions
+// but no additions, with totals recomputed.
+func dropDeletionOnly(stats *diff.DiffStats) *diff.DiffStats {
+	kept := &diff.DiffStats{}
+	for _, f := range stats.Files {
+		if f.Additions == 0 && f.Deletions > 0 {
+			continue
+		}
+		kept.Files = append(kept.Files, f)
+		kept.TotalAdd += f.Additions
+		kept.TotalDel += f.Deletions
+	}
+	kept.TotalFiles = len(kept.Files)
+	return kept
+}
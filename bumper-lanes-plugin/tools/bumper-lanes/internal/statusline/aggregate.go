@@ -0,0 +1,89 @@
+package statusline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+	"github.com/kylesnowschwartz/diff-viz/v2/render"
+)
+
+// maxRenderFiles is the file count above which diffs are rendered as
+// per-top-level-directory totals. Some diff-viz renderers scale badly with
+// tens of thousands of files, and the output is unreadable long before that.
+const maxRenderFiles = 2000
+
+// rootFilesLabel groups files that live at the repo root.
+const rootFilesLabel = "(root files)"
+
+// aggregateByTopDir collapses stats into one entry per top-level directory,
+// summing additions and deletions. Totals are unchanged.
+// An entry is marked untracked only if every file in it is.
+func aggregateByTopDir(stats *diff.DiffStats) *diff.DiffStats {
+	return aggregateByDepth(stats, 1)
+}
+
+// aggregateByDepth is aggregateByTopDir grouping by the first depth path
+// components instead, like the smart renderer's depth: at depth 2
+// "src/lib/a.go" and "src/lib/b.go" become "src/lib", while "src/main.go"
+// stays a file. Root-level files always group under rootFilesLabel.
+func aggregateByDepth(stats *diff.DiffStats, depth int) *diff.DiffStats {
+	depth = max(1, depth)
+	byDir := make(map[string]*diff.FileStat)
+	var order []string
+	for _, f := range stats.Files {
+		dir := rootFilesLabel
+		if strings.Contains(f.Path, "/") {
+			parts := strings.Split(f.Path, "/")
+			dir = strings.Join(parts[:min(depth, len(parts))], "/")
+		}
+		entry, ok := byDir[dir]
+		if !ok {
+			entry = &diff.FileStat{Path: dir, IsUntracked: true}
+			byDir[dir] = entry
+			order = append(order, dir)
+		}
+		entry.Additions += f.Additions
+		entry.Deletions += f.Deletions
+		entry.IsUntracked = entry.IsUntracked && f.IsUntracked
+	}
+	sort.Strings(order)
+
+	out := &diff.DiffStats{TotalAdd: stats.TotalAdd, TotalDel: stats.TotalDel, TotalFiles: stats.TotalFiles}
+	for _, dir := range order {
+		out.Files = append(out.Files, *byDir[dir])
+	}
+	return out
+}
+
+// largeDiffNote explains why a render shows directory totals.
+func largeDiffNote(files int) string {
+	return fmt.Sprintf("(%d files changed; showing totals by top-level directory)", files)
+}
+
+// groupByOpt is the view option that sets the path depth the compact
+// renderers (smart, split) aggregate changes to: "--group-by 1" or "--group-by=1".
+const groupByOpt = "--group-by"
+
+// applyBudget gives score-aware renderers the score to show and the
+// threshold to compare it against.
+func applyBudget(r Renderer, score, limit int) {
+	switch r := r.(type) {
+	case *onelineRenderer:
+		r.Score, r.Limit = score, limit
+	case *statRenderer:
+		r.Score, r.Limit = score, limit
+	}
+}
+
+// applyGroupBy sets the aggregation depth on renderers that group by path.
+// For smart it overrides --depth; other renderers are left alone.
+func applyGroupBy(r Renderer, depth int) {
+	switch r := r.(type) {
+	case *render.SmartSparklineRenderer:
+		r.MaxDepth = depth
+	case *splitRenderer:
+		r.GroupBy = depth
+	}
+}
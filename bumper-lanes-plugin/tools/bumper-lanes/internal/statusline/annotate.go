@@ -0,0 +1,78 @@
+package statusline
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// annotateOpt is the view option that tags each file in tree mode as
+// new, modified, or renamed.
+const annotateOpt = "--annotate"
+
+// File tags appended by --annotate.
+const (
+	tagNew     = "[new]"
+	tagMod     = "[mod]"
+	tagRenamed = "[renamed]"
+)
+
+// fileStatuses maps paths changed since HEAD to their git status letter
+// (A, M, R, ...), keyed by the destination path for renames. Renames are
+// only reported when git detects them, as with the numstat (diff_flags
+// "-M" forces detection on). Returns nil on error, e.g. before the first
+// commit.
+func fileStatuses(flags []string) map[string]byte {
+	args := append([]string{"diff", "--name-status", "-z"}, flags...)
+	output, err := exec.Command("git", append(args, "HEAD")...).Output()
+	if err != nil {
+		return nil
+	}
+	statuses := make(map[string]byte)
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i < len(fields); i++ {
+		status := fields[i]
+		if status == "" || i+1 >= len(fields) {
+			break
+		}
+		// Renames and copies list the source then the destination path
+		if status[0] == 'R' || status[0] == 'C' {
+			i++
+		}
+		i++
+		if i < len(fields) {
+			statuses[fields[i]] = status[0]
+		}
+	}
+	return statuses
+}
+
+// fileTag returns the --annotate tag for f. Untracked files and files
+// added since HEAD are new; everything else not renamed is modified.
+func fileTag(f diff.FileStat, statuses map[string]byte) string {
+	if f.IsUntracked {
+		return tagNew
+	}
+	switch statuses[f.Path] {
+	case 'A':
+		return tagNew
+	case 'R':
+		return tagRenamed
+	}
+	return tagMod
+}
+
+// annotateStats returns a copy of stats whose file paths end in their
+// --annotate tag, so the tree renderer prints it after the file name.
+func annotateStats(stats *diff.DiffStats) *diff.DiffStats {
+	statuses := fileStatuses(config.LoadDiffFlags())
+	annotated := *stats
+	annotated.Files = make([]diff.FileStat, len(stats.Files))
+	for i, f := range stats.Files {
+		f.Path += " " + fileTag(f, statuses)
+		annotated.Files[i] = f
+	}
+	return &annotated
+}
@@ -0,0 +1,58 @@
+package statusline
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// maxExtensions is how many extensions the status line names before
+// folding the rest into "other".
+const maxExtensions = 3
+
+// FormatExtensionBreakdown groups added lines by file extension, e.g.
+// "go:120 yaml:80 md:20 other:5". Sorted by additions (descending), capped
+// at max named extensions. Files without an extension count as "other".
+// Returns "" when nothing was added.
+func FormatExtensionBreakdown(stats *diff.DiffStats, max int) string {
+	counts := make(map[string]int)
+	other := 0
+	for _, f := range stats.Files {
+		if f.Additions == 0 {
+			continue
+		}
+		ext := strings.ToLower(strings.TrimPrefix(path.Ext(f.Path), "."))
+		if ext == "" {
+			other += f.Additions
+			continue
+		}
+		counts[ext] += f.Additions
+	}
+
+	exts := make([]string, 0, len(counts))
+	for ext := range counts {
+		exts = append(exts, ext)
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		if counts[exts[i]] != counts[exts[j]] {
+			return counts[exts[i]] > counts[exts[j]]
+		}
+		return exts[i] < exts[j]
+	})
+
+	var parts []string
+	for i, ext := range exts {
+		if i >= max {
+			other += counts[ext]
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d", ext, counts[ext]))
+	}
+	if other > 0 {
+		parts = append(parts, fmt.Sprintf("other:%d", other))
+	}
+	return strings.Join(parts, " ")
+}
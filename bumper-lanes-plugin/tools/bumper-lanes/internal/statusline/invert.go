@@ -0,0 +1,63 @@
+package statusline
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// invertOpt is the view option that renders the directories a diff left
+// untouched instead of the ones it changed.
+const invertOpt = "--invert"
+
+// repoTopDirs lists the top-level directories tracked at HEAD.
+func repoTopDirs() ([]string, error) {
+	output, err := exec.Command("git", "ls-tree", "-d", "--name-only", "HEAD").Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// untouchedDirs returns the entries of dirs that no changed file lives under, sorted.
+func untouchedDirs(dirs []string, stats *diff.DiffStats) []string {
+	touched := make(map[string]bool)
+	for _, f := range stats.Files {
+		if i := strings.Index(f.Path, "/"); i > 0 {
+			touched[f.Path[:i]] = true
+		}
+	}
+	var out []string
+	for _, d := range dirs {
+		if !touched[d] {
+			out = append(out, d)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// renderUntouched formats untouched top-level directories as a tree, e.g.
+//
+//	Untouched: 2 of 3 top-level dirs
+//	├── docs/
+//	└── lib/
+func renderUntouched(untouched []string, total int, useColor bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Untouched: %d of %d top-level dirs", len(untouched), total)
+	for i, d := range untouched {
+		branch := "├── "
+		if i == len(untouched)-1 {
+			branch = "└── "
+		}
+		name := d + "/"
+		if useColor {
+			name = colorGreen + name + colorReset
+		}
+		b.WriteString("\n" + branch + name)
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package statusline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// maxDepthOpt is the view option that clamps tree mode to N path levels:
+// "--max-depth 2" or "--max-depth=2". The other hierarchical renderers
+// already take --depth.
+const maxDepthOpt = "--max-depth"
+
+// clampDepth collapses every file more than depth path components deep
+// into one summary leaf per depth-level directory carrying the subtree's
+// totals: at depth 2, "src/lib/a.go" and "src/lib/util/b.go" become
+// "src/lib/… (2 files)", while "src/main.go" stays as is. Totals are
+// unchanged. A summary leaf is marked untracked only if every file in it is.
+func clampDepth(stats *diff.DiffStats, depth int) *diff.DiffStats {
+	depth = max(1, depth)
+	out := &diff.DiffStats{TotalAdd: stats.TotalAdd, TotalDel: stats.TotalDel, TotalFiles: stats.TotalFiles}
+	bySubtree := make(map[string]*diff.FileStat)
+	counts := make(map[string]int)
+	var order []string
+	for _, f := range stats.Files {
+		parts := strings.Split(f.Path, "/")
+		if len(parts) <= depth {
+			out.Files = append(out.Files, f)
+			continue
+		}
+		dir := strings.Join(parts[:depth], "/")
+		entry, ok := bySubtree[dir]
+		if !ok {
+			entry = &diff.FileStat{IsUntracked: true}
+			bySubtree[dir] = entry
+			order = append(order, dir)
+		}
+		entry.Additions += f.Additions
+		entry.Deletions += f.Deletions
+		entry.IsUntracked = entry.IsUntracked && f.IsUntracked
+		counts[dir]++
+	}
+	sort.Strings(order)
+
+	for _, dir := range order {
+		entry := bySubtree[dir]
+		noun := "files"
+		if counts[dir] == 1 {
+			noun = "file"
+		}
+		entry.Path = fmt.Sprintf("%s/… (%d %s)", dir, counts[dir], noun)
+		out.Files = append(out.Files, *entry)
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package statusline
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// onelineRenderer prints score and the biggest hotspot on one grep-able line
+// for CI logs. Score and Limit come from the caller (see applyBudget), so
+// the line matches what the Stop hook enforces rather than re-scoring the
+// rendered stats.
+type onelineRenderer struct {
+	w        io.Writer
+	useColor bool
+	Score    int
+	Limit    int
+}
+
+func newOnelineRenderer(w io.Writer, useColor bool) *onelineRenderer {
+	return &onelineRenderer{w: w, useColor: useColor}
+}
+
+// Render implements Renderer.
+func (r *onelineRenderer) Render(stats *diff.DiffStats) {
+	fmt.Fprintln(r.w, FormatOneline(stats, r.Score, r.Limit, r.useColor))
+}
+
+// FormatOneline formats a single-line summary, e.g.
+// "SCORE 512/400 (128%) TRIPPED | +800 -120 | 9 files | top: src/big.go(200)".
+// The hotspot is the file with the most changed lines (additions + deletions).
+// A zero limit means enforcement is disabled.
+func FormatOneline(stats *diff.DiffStats, score, limit int, useColor bool) string {
+	var head string
+	if limit > 0 {
+		status, color := "OK", colorGreen
+		if score > limit {
+			status, color = "TRIPPED", colorRed
+		}
+		if useColor {
+			status = color + status + colorReset
+		}
+		shownScore, shownLimit := config.DisplayScore(score, limit)
+		head = fmt.Sprintf("SCORE %d/%d (%d%%) %s", shownScore, shownLimit, (score*100)/limit, status)
+	} else {
+		head = fmt.Sprintf("SCORE %d DISABLED", score)
+	}
+
+	line := fmt.Sprintf("%s | +%d -%d | %d files", head, stats.TotalAdd, stats.TotalDel, stats.TotalFiles)
+
+	var top *diff.FileStat
+	for i := range stats.Files {
+		f := &stats.Files[i]
+		if top == nil || f.Additions+f.Deletions > top.Additions+top.Deletions {
+			top = f
+		}
+	}
+	if top != nil {
+		line += fmt.Sprintf(" | top: %s(%d)", top.Path, top.Additions+top.Deletions)
+	}
+	return line
+}
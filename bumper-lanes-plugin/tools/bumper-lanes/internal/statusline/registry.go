@@ -0,0 +1,103 @@
+package statusline
+
+import (
+	"io"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	diffvizconfig "github.com/kylesnowschwartz/diff-viz/v2/config"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+	"github.com/kylesnowschwartz/diff-viz/v2/render"
+)
+
+// Renderer draws diff stats, matching diff-viz's renderer pattern.
+type Renderer interface {
+	Render(stats *diff.DiffStats)
+}
+
+// RendererFactory builds a renderer writing to w. cfg carries the resolved
+// per-mode settings (width, depth, ...) from the diff-viz config system.
+type RendererFactory func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer
+
+// renderers maps view mode names to their factories.
+var renderers = map[string]RendererFactory{}
+
+// RegisterRenderer adds or replaces the renderer for a view mode and makes
+// the mode valid in config and /bumper-view. Call it before rendering,
+// typically from an init function in this package.
+func RegisterRenderer(name string, factory RendererFactory) {
+	renderers[name] = factory
+	config.RegisterMode(name)
+}
+
+// unregisterRenderer undoes RegisterRenderer; tests use it to clean up.
+func unregisterRenderer(name string) {
+	delete(renderers, name)
+	config.UnregisterMode(name)
+}
+
+// getRenderer returns the renderer registered for mode, or tree for unknown modes.
+func getRenderer(mode string, w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+	factory, ok := renderers[mode]
+	if !ok {
+		factory = renderers[config.DefaultViewMode]
+	}
+	return factory(w, useColor, cfg)
+}
+
+// Built-in modes: diff-viz v2.4.0's renderers plus the local stat,
+// oneline, and split modes. Registration order is the order /bumper-view
+// lists them in.
+func init() {
+	RegisterRenderer("tree", func(w io.Writer, useColor bool, _ diffvizconfig.ResolvedConfig) Renderer {
+		return render.NewTreeRenderer(w, useColor)
+	})
+	RegisterRenderer("smart", func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+		r := render.NewSmartSparklineRenderer(w, useColor)
+		r.Width = cfg.Width
+		r.MaxDepth = cfg.Depth
+		return r
+	})
+	RegisterRenderer("sparkline-tree", func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+		r := render.NewSparklineTreeRenderer(w, useColor)
+		r.MaxDepth = cfg.Depth
+		r.N = cfg.N
+		return r
+	})
+	RegisterRenderer("hotpath", func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+		r := render.NewHotpathRenderer(w, useColor)
+		r.MaxDepth = cfg.Depth
+		return r
+	})
+	RegisterRenderer("icicle", func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+		r := render.NewIcicleRenderer(w, useColor)
+		r.Width = cfg.Width
+		r.MaxDepth = cfg.Depth
+		return r
+	})
+	RegisterRenderer("brackets", func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+		r := render.NewBracketsRenderer(w, useColor)
+		r.Width = cfg.Width
+		r.ExpandDepth = cfg.Expand
+		return r
+	})
+	RegisterRenderer("gauge", func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+		r := render.NewGaugeRenderer(w, useColor)
+		r.Width = cfg.Width
+		return r
+	})
+	RegisterRenderer("depth", func(w io.Writer, useColor bool, cfg diffvizconfig.ResolvedConfig) Renderer {
+		r := render.NewDepthRenderer(w, useColor)
+		r.MaxDepth = cfg.Depth
+		r.Width = cfg.Width
+		return r
+	})
+	RegisterRenderer("stat", func(w io.Writer, useColor bool, _ diffvizconfig.ResolvedConfig) Renderer {
+		return newStatRenderer(w, useColor)
+	})
+	RegisterRenderer("oneline", func(w io.Writer, useColor bool, _ diffvizconfig.ResolvedConfig) Renderer {
+		return newOnelineRenderer(w, useColor)
+	})
+	RegisterRenderer("split", func(w io.Writer, useColor bool, _ diffvizconfig.ResolvedConfig) Renderer {
+		return newSplitRenderer(w, useColor)
+	})
+}
@@ -0,0 +1,61 @@
+package statusline
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+)
+
+// splitBarWidth is the cell count of each additions and deletions bar.
+const splitBarWidth = 10
+
+// splitRenderer shows one row per directory (top-level by default) with separate
+// additions and deletions bars, each scaled to its own maximum, so a
+// directory with few deletions still shows them next to large additions.
+type splitRenderer struct {
+	w        io.Writer
+	useColor bool
+	GroupBy  int // Path depth rows aggregate to (1 = top-level dirs)
+}
+
+func newSplitRenderer(w io.Writer, useColor bool) *splitRenderer {
+	return &splitRenderer{w: w, useColor: useColor, GroupBy: 1}
+}
+
+// Render implements Renderer.
+func (r *splitRenderer) Render(stats *diff.DiffStats) {
+	if len(stats.Files) == 0 {
+		fmt.Fprintln(r.w, "No changes")
+		return
+	}
+
+	dirs := aggregateByDepth(stats, r.GroupBy)
+	var maxAdd, maxDel, nameWidth int
+	for _, d := range dirs.Files {
+		maxAdd = max(maxAdd, d.Additions)
+		maxDel = max(maxDel, d.Deletions)
+		nameWidth = max(nameWidth, len(d.Path))
+	}
+
+	for _, d := range dirs.Files {
+		add := splitBar(d.Additions, maxAdd)
+		del := splitBar(d.Deletions, maxDel)
+		if r.useColor {
+			add = colorGreen + add + colorReset
+			del = colorRed + del + colorReset
+		}
+		fmt.Fprintf(r.w, "%-*s +%s %d -%s %d\n", nameWidth, d.Path, add, d.Additions, del, d.Deletions)
+	}
+}
+
+// splitBar draws value as filled cells out of splitBarWidth, relative to
+// maxValue. Any non-zero value gets at least one cell.
+func splitBar(value, maxValue int) string {
+	filled := 0
+	if maxValue > 0 && value > 0 {
+		filled = max(1, value*splitBarWidth/maxValue)
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", splitBarWidth-filled)
+}
@@ -0,0 +1,66 @@
+package statusline
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
+	"github.com/kylesnowschwartz/diff-viz/v2/render"
+)
+
+// scoreBarWidth is the cell count of the stat score bar.
+const scoreBarWidth = 20
+
+// statRenderer is diff-viz's git diff --stat output followed by a bar of
+// the bumper-lanes score against the session's limit, so one view shows
+// both the conventional stat and the review budget. Score and Limit come
+// from the caller (see applyBudget).
+type statRenderer struct {
+	stat     *render.StatRenderer
+	w        io.Writer
+	useColor bool
+	Score    int
+	Limit    int
+}
+
+func newStatRenderer(w io.Writer, useColor bool) *statRenderer {
+	return &statRenderer{stat: render.NewStatRenderer(w, nil), w: w, useColor: useColor}
+}
+
+// Render implements Renderer.
+func (r *statRenderer) Render(stats *diff.DiffStats) {
+	r.stat.Render(stats)
+	if len(stats.Files) == 0 {
+		return
+	}
+	fmt.Fprintln(r.w, formatScoreBar(r.Score, r.Limit, r.useColor))
+}
+
+// formatScoreBar draws the score as filled cells out of scoreBarWidth,
+// colored by traffic light zone, e.g. "score ██████░░░░░░░░░░░░░░ 120/400 (30%)".
+// Over the limit the bar is full. A zero limit means enforcement is disabled.
+func formatScoreBar(score, limit int, useColor bool) string {
+	if limit <= 0 {
+		return fmt.Sprintf("score %d (disabled)", score)
+	}
+	pct := (score * 100) / limit
+	filled := min(scoreBarWidth, score*scoreBarWidth/limit)
+	if score > 0 {
+		filled = max(1, filled)
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", scoreBarWidth-filled)
+	if useColor {
+		color := colorGreen
+		switch trafficLightZone(pct, score > limit) {
+		case zoneYellow:
+			color = colorYellow
+		case zoneRed:
+			color = colorRed
+		}
+		bar = color + bar + colorReset
+	}
+	shownScore, shownLimit := config.DisplayScore(score, limit)
+	return fmt.Sprintf("score %s %d/%d (%d%%)", bar, shownScore, shownLimit, pct)
+}
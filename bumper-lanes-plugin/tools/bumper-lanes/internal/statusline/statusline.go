@@ -14,9 +14,9 @@ import (
 
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/logging"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/scoring"
 	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
 	"github.com/kylesnowschwartz/diff-viz/v2/diff"
-	"github.com/kylesnowschwartz/diff-viz/v2/render"
 
 	diffvizconfig "github.com/kylesnowschwartz/diff-viz/v2/config"
 )
@@ -43,6 +43,10 @@ type StatusOutput struct {
 	BumperIndicator string
 	// DiffTree is the multi-line diff visualization (may be empty)
 	DiffTree string
+	// MaxDiffLines caps how many DiffTree lines are printed (0 = unlimited)
+	MaxDiffLines int
+	// PlainSpaces keeps regular spaces in DiffTree instead of U+00A0 (statusline_nbsp: false)
+	PlainSpaces bool
 	// State is the bumper-lanes state: "active", "tripped", "paused", or "" (inactive)
 	State string
 	// Score is the current diff score
@@ -51,6 +55,14 @@ type StatusOutput struct {
 	Limit int
 	// Percentage is score/limit as integer percentage
 	Percentage int
+	// Remaining is max(0, Limit-Score): points left before the threshold trips
+	Remaining int
+	// Age is the time since the last baseline reset (e.g., "12m"), or "" if inactive
+	Age string
+	// Extensions is the additions-by-extension breakdown (e.g., "go:120 yaml:80"), or "" if not shown
+	Extensions string
+	// Widget is the session's preferred widget (/bumper-widget), used when none is requested; "" = all
+	Widget string
 }
 
 // ANSI color codes
@@ -108,23 +120,30 @@ func Render(input *StatusInput) (*StatusOutput, error) {
 
 	// Bumper-lanes widget (if active)
 	var stateStr string
-	var score, limit, percentage int
+	var score, limit, percentage, remaining int
 	var diffTree string
+	var maxDiffLines int
+	var plainSpaces bool
 	var bumperIndicator string
+	var age string
+	var extensions string
+	var widget string
 
 	sess, err := state.Load(input.SessionID)
 	if err == nil {
+		widget = sess.Widget
 		// Use cached score (updated by PostToolUse hook on Write/Edit)
 		score = sess.Score
 		limit = sess.ThresholdLimit
 		if limit > 0 {
 			percentage = (score * 100) / limit
 		}
+		remaining = max(0, limit-score)
 
 		// Determine state
 		if sess.ThresholdLimit == 0 {
 			stateStr = "disabled"
-		} else if sess.Paused {
+		} else if sess.IsPaused(time.Now()) {
 			stateStr = "paused"
 		} else if sess.StopTriggered {
 			stateStr = "tripped"
@@ -140,13 +159,43 @@ func Render(input *StatusInput) (*StatusOutput, error) {
 
 		// Format bumper indicator (capture for both full line and standalone use)
 		// viewMode included to force status line refresh when mode changes
-		bumperIndicator = formatBumperStatus(stateStr, score, limit, percentage, viewMode)
+		bumperIndicator = formatBumperStatus(stateStr, score, limit, percentage, viewMode, sess.Tag)
+		if stateStr == "paused" && sess.PausedUntil != "" {
+			bumperIndicator += " " + state.FormatAge(sess.PauseRemaining(time.Now())) + " left"
+		}
+		age = state.FormatAge(sess.Age(time.Now()))
+		if config.LoadShowRemaining() && stateStr != "disabled" {
+			bumperIndicator += " " + formatRemaining(config.DisplayScore(score, limit))
+		}
+		if config.LoadShowSessionAge() {
+			bumperIndicator += " " + age
+		}
+		if config.LoadShowBaselineAnchor() {
+			bumperIndicator += " " + formatBaselineAnchor(sess.LastResetAt != "", age)
+		}
+
+		// Fetch diff stats once for both the extension breakdown and the diff tree
+		showDiffViz := sess.ShouldShowDiffViz()
+		showExtensions := config.LoadShowExtensions()
+		var stats *diff.DiffStats
+		if showDiffViz || showExtensions {
+			stats = loadDiffStats(config.LoadCountUntracked())
+		}
+
+		if showExtensions && stats != nil {
+			extensions = FormatExtensionBreakdown(stats, maxExtensions)
+			if extensions != "" {
+				bumperIndicator += " " + extensions
+			}
+		}
 		parts = append(parts, bumperIndicator)
 
 		// Get diff tree visualization (only if should show)
-		if sess.ShouldShowDiffViz() {
+		if showDiffViz && stats != nil {
 			viewOpts := sess.GetViewOpts()
-			diffTree = getDiffTree(viewMode, viewOpts)
+			diffTree = renderDiffTree(stats, viewMode, viewOpts, score, limit, true)
+			maxDiffLines = config.LoadStatuslineMaxDiffLines()
+			plainSpaces = !config.LoadStatuslineNBSP()
 		}
 	}
 
@@ -156,10 +205,16 @@ func Render(input *StatusInput) (*StatusOutput, error) {
 		StatusLine:      strings.Join(parts, " | "),
 		BumperIndicator: bumperIndicator,
 		DiffTree:        diffTree,
+		MaxDiffLines:    maxDiffLines,
+		PlainSpaces:     plainSpaces,
 		State:           stateStr,
 		Score:           score,
 		Limit:           limit,
 		Percentage:      percentage,
+		Remaining:       remaining,
+		Age:             age,
+		Extensions:      extensions,
+		Widget:          widget,
 	}, nil
 }
 
@@ -183,10 +238,14 @@ func isGitDirty() bool {
 // formatBumperStatus produces a traffic light gauge for bumper-lanes status.
 // Progressive reveal: ▂ green <70%, ▂▄ +yellow 70-90%, ▂▄█ +red >90% or tripped.
 // viewMode is included to force status line refresh when mode changes.
-func formatBumperStatus(stateStr string, score, limit, percentage int, viewMode string) string {
+// A non-empty tag (the active task name) is shown ahead of the gauge.
+func formatBumperStatus(stateStr string, score, limit, percentage int, viewMode, tag string) string {
 	if viewMode == "" {
 		viewMode = "tree"
 	}
+	if tag != "" {
+		return tag + " " + formatBumperStatus(stateStr, score, limit, percentage, viewMode, "")
+	}
 
 	// Disabled state shows text in blue
 	if stateStr == "disabled" {
@@ -204,6 +263,24 @@ func formatBumperStatus(stateStr string, score, limit, percentage int, viewMode
 	return fmt.Sprintf("%s [%s]", bar, viewMode)
 }
 
+// formatRemaining shows the budget left, e.g. "120 left", or "OVER by 30"
+// once the score passes the limit.
+func formatRemaining(score, limit int) string {
+	if score > limit {
+		return fmt.Sprintf("OVER by %d", score-limit)
+	}
+	return fmt.Sprintf("%d left", limit-score)
+}
+
+// formatBaselineAnchor says what the score is measured from:
+// "since reset 12m ago" once the baseline has been reset, else "since session start".
+func formatBaselineAnchor(wasReset bool, age string) string {
+	if wasReset {
+		return fmt.Sprintf("since reset %s ago", age)
+	}
+	return "since session start"
+}
+
 // formatTrafficLightBar returns a colored traffic light gauge with percentage.
 // Progressive reveal: green <70%, green+yellow 70-90%, all three >90% or tripped.
 // Uses increasing height blocks: ▂ (short), ▄ (medium), █ (tall).
@@ -217,15 +294,15 @@ func formatTrafficLightBar(percentage int, tripped bool) string {
 
 	var bar string
 
-	switch {
-	case tripped || percentage >= 90:
+	switch trafficLightZone(percentage, tripped) {
+	case zoneRed:
 		// Red zone: show all three bars
 		bar = fmt.Sprintf("%s%s%s%s%s%s%s",
 			colorGreen, shortBar,
 			colorYellow, mediumBar,
 			colorRed, tallBar,
 			colorReset)
-	case percentage >= 70:
+	case zoneYellow:
 		// Yellow zone: show green + yellow
 		bar = fmt.Sprintf("%s%s%s%s%s",
 			colorGreen, shortBar,
@@ -239,16 +316,78 @@ func formatTrafficLightBar(percentage int, tripped bool) string {
 	return fmt.Sprintf("%s %d%%", bar, percentage)
 }
 
-// getDiffTree uses diff-viz library to render the tree visualization.
+// Traffic light zones, from least to most of the budget used.
+const (
+	zoneGreen = iota
+	zoneYellow
+	zoneRed
+)
+
+// trafficLightZone returns the zone for a budget percentage:
+// green <70%, yellow 70-90%, red >=90% or tripped.
+func trafficLightZone(percentage int, tripped bool) int {
+	switch {
+	case tripped || percentage >= 90:
+		return zoneRed
+	case percentage >= 70:
+		return zoneYellow
+	}
+	return zoneGreen
+}
+
+// loadDiffStats returns current diff stats (working tree vs HEAD) with the
+// config path filter applied, or nil on error. Untracked files are included
+// only when untracked is true.
+func loadDiffStats(untracked bool) *diff.DiffStats {
+	stats, _, err := getAllStats(config.LoadDiffFlags(), untracked)
+	if err != nil {
+		return nil
+	}
+	return scoring.FilterDiffStats(stats, scoring.PathFilter{Include: config.LoadInclude(), Exclude: config.LoadExclude()})
+}
+
+// getAllStats is diff.GetAllStats with extra git diff flags (diff_flags).
+// GetAllStats drops untracked files whenever args are given, so with flags
+// set they are added back here (unless untracked is false).
+func getAllStats(flags []string, untracked bool) (*diff.DiffStats, []string, error) {
+	if len(flags) == 0 && untracked {
+		return diff.GetAllStats()
+	}
+	stats, warnings, err := diff.GetDiffStats(flags...)
+	if err != nil || !untracked {
+		return stats, warnings, err
+	}
+	untrackedFiles, untrackedWarnings, _ := diff.GetUntrackedFiles()
+	warnings = append(warnings, untrackedWarnings...)
+	for _, f := range untrackedFiles {
+		stats.Files = append(stats.Files, f)
+		stats.TotalAdd += f.Additions
+		stats.TotalFiles++
+	}
+	return stats, warnings, nil
+}
+
+// RenderDiffTree uses diff-viz library to render the tree visualization.
 // Uses diff-viz config system for per-mode defaults from .bumper-lanes.json.
-func getDiffTree(viewMode, viewOpts string) string {
+// Untracked files are shown only when untracked is true. score and limit
+// are what score-aware modes (oneline, stat) print, normally the session's
+// enforced score and ThresholdLimit; they are never re-derived from the
+// rendered stats.
+// Returns empty string when there are no changes.
+func RenderDiffTree(viewMode, viewOpts string, score, limit int, useColor, untracked bool) string {
+	stats := loadDiffStats(untracked)
+	if stats == nil {
+		return ""
+	}
+	return renderDiffTree(stats, viewMode, viewOpts, score, limit, useColor)
+}
+
+// renderDiffTree renders already-fetched stats.
+func renderDiffTree(stats *diff.DiffStats, viewMode, viewOpts string, score, limit int, useColor bool) string {
 	if viewMode == "" {
 		viewMode = "tree"
 	}
-
-	// Get current diff stats (working tree vs HEAD)
-	stats, _, err := diff.GetAllStats()
-	if err != nil || stats.TotalFiles == 0 {
+	if stats.TotalFiles == 0 {
 		return ""
 	}
 
@@ -258,10 +397,19 @@ func getDiffTree(viewMode, viewOpts string) string {
 
 	// Parse CLI-style overrides from viewOpts (legacy support)
 	var cliFlags *diffvizconfig.ModeConfig
+	var invert, annotate, addsOnly bool
+	var groupBy, maxDepth int
 	if viewOpts != "" {
 		cliFlags = &diffvizconfig.ModeConfig{}
-		for _, opt := range strings.Fields(viewOpts) {
-			if strings.HasPrefix(opt, "--width=") {
+		opts := strings.Fields(viewOpts)
+		for i, opt := range opts {
+			if opt == invertOpt {
+				invert = true
+			} else if opt == annotateOpt {
+				annotate = true
+			} else if opt == addsOnlyOpt {
+				addsOnly = true
+			} else if strings.HasPrefix(opt, "--width=") {
 				var w int
 				fmt.Sscanf(opt, "--width=%d", &w)
 				cliFlags.Width = &w
@@ -273,6 +421,14 @@ func getDiffTree(viewMode, viewOpts string) string {
 				var e int
 				fmt.Sscanf(opt, "--expand=%d", &e)
 				cliFlags.Expand = &e
+			} else if strings.HasPrefix(opt, groupByOpt+"=") {
+				fmt.Sscanf(opt, groupByOpt+"=%d", &groupBy)
+			} else if opt == groupByOpt && i+1 < len(opts) {
+				fmt.Sscanf(opts[i+1], "%d", &groupBy)
+			} else if strings.HasPrefix(opt, maxDepthOpt+"=") {
+				fmt.Sscanf(opt, maxDepthOpt+"=%d", &maxDepth)
+			} else if opt == maxDepthOpt && i+1 < len(opts) {
+				fmt.Sscanf(opts[i+1], "%d", &maxDepth)
 			}
 		}
 	}
@@ -280,10 +436,49 @@ func getDiffTree(viewMode, viewOpts string) string {
 	// Resolve config: global defaults < mode defaults < config file < CLI flags
 	resolved := cfg.Resolve(viewMode, cliFlags)
 
+	if addsOnly {
+		stats = dropDeletionOnly(stats)
+		if stats.TotalFiles == 0 {
+			return ""
+		}
+	}
+
+	// --invert (tree mode) lists the top-level dirs the diff didn't touch.
+	// Falls through to the normal tree if HEAD can't be listed.
+	if invert && viewMode == "tree" {
+		if dirs, err := repoTopDirs(); err == nil && len(dirs) > 0 {
+			return renderUntouched(untouchedDirs(dirs, stats), len(dirs), useColor)
+		}
+	}
+
+	// Huge diffs render as per-directory totals (oneline only reads totals and
+	// the hotspot; split already aggregates by directory; stat shells out to
+	// git and scores its bar from per-file stats)
+	var note string
+	if stats.TotalFiles > maxRenderFiles && viewMode != "oneline" && viewMode != "split" && viewMode != "stat" {
+		stats = aggregateByTopDir(stats)
+		note = largeDiffNote(stats.TotalFiles)
+	}
+
+	// --annotate (tree mode) tags each file line; aggregated rows are
+	// directories, so they stay untagged
+	if annotate && viewMode == "tree" && note == "" {
+		stats = annotateStats(stats)
+	}
+
+	// --max-depth (tree mode) folds deeper files into per-directory summary
+	// leaves; collapsed files drop their --annotate tags with their names
+	if maxDepth > 0 && viewMode == "tree" && note == "" {
+		stats = clampDepth(stats, maxDepth)
+	}
+
 	// Render to buffer
 	var buf bytes.Buffer
-	useColor := true
 	renderer := getRenderer(viewMode, &buf, useColor, resolved)
+	applyBudget(renderer, score, limit)
+	if groupBy > 0 {
+		applyGroupBy(renderer, groupBy)
+	}
 	renderer.Render(stats)
 
 	// Trim trailing whitespace, preserve leading
@@ -291,58 +486,10 @@ func getDiffTree(viewMode, viewOpts string) string {
 	if result == "No changes" {
 		return ""
 	}
-	return result
-}
-
-// diffRenderer is a local interface matching diff-viz's renderer pattern.
-type diffRenderer interface {
-	Render(stats *diff.DiffStats)
-}
-
-// getRenderer returns the appropriate renderer for the given mode.
-// Uses resolved config from diff-viz config system for per-mode settings.
-func getRenderer(mode string, buf *bytes.Buffer, useColor bool, cfg diffvizconfig.ResolvedConfig) diffRenderer {
-	switch mode {
-	case "tree":
-		return render.NewTreeRenderer(buf, useColor)
-	case "smart":
-		r := render.NewSmartSparklineRenderer(buf, useColor)
-		r.Width = cfg.Width
-		r.MaxDepth = cfg.Depth
-		return r
-	case "sparkline-tree":
-		r := render.NewSparklineTreeRenderer(buf, useColor)
-		r.MaxDepth = cfg.Depth
-		r.N = cfg.N
-		return r
-	case "hotpath":
-		r := render.NewHotpathRenderer(buf, useColor)
-		r.MaxDepth = cfg.Depth
-		return r
-	case "icicle":
-		r := render.NewIcicleRenderer(buf, useColor)
-		r.Width = cfg.Width
-		r.MaxDepth = cfg.Depth
-		return r
-	case "brackets":
-		r := render.NewBracketsRenderer(buf, useColor)
-		r.Width = cfg.Width
-		r.ExpandDepth = cfg.Expand
-		return r
-	case "gauge":
-		r := render.NewGaugeRenderer(buf, useColor)
-		r.Width = cfg.Width
-		return r
-	case "depth":
-		r := render.NewDepthRenderer(buf, useColor)
-		r.MaxDepth = cfg.Depth
-		r.Width = cfg.Width
-		return r
-	case "stat":
-		return render.NewStatRenderer(buf, nil)
-	default:
-		return render.NewTreeRenderer(buf, useColor)
+	if note != "" {
+		result += "\n" + note
 	}
+	return result
 }
 
 // ParseInput parses JSON input from stdin.
@@ -361,10 +508,23 @@ const (
 	WidgetDiffTree  = "diff-tree" // Just the diff visualization
 )
 
+// ValidWidget reports whether widget is one of the Widget* types.
+func ValidWidget(widget string) bool {
+	switch widget {
+	case WidgetAll, WidgetIndicator, WidgetDiffTree:
+		return true
+	}
+	return false
+}
+
 // FormatOutput converts StatusOutput to the final string output.
 // Widget selects which component to output: "all", "indicator", or "diff-tree".
-// Applies non-breaking space conversion for Claude Code compatibility.
+// An empty widget falls back to the session's preference (out.Widget), then all.
+// Applies non-breaking space conversion for Claude Code compatibility unless out.PlainSpaces.
 func FormatOutput(out *StatusOutput, widget string) string {
+	if widget == "" {
+		widget = out.Widget
+	}
 	switch widget {
 	case WidgetIndicator:
 		return out.FormatIndicator()
@@ -388,7 +548,7 @@ func (out *StatusOutput) FormatDiffTree() string {
 	if out.DiffTree == "" {
 		return ""
 	}
-	return formatDiffTreeLines(out.DiffTree)
+	return formatDiffTreeLines(out.DiffTree, out.MaxDiffLines, !out.PlainSpaces)
 }
 
 // FormatAll returns the full status line plus diff tree.
@@ -402,19 +562,27 @@ func (out *StatusOutput) FormatAll() string {
 	result.WriteString("\n")
 
 	if out.DiffTree != "" {
-		result.WriteString(formatDiffTreeLines(out.DiffTree))
+		result.WriteString(formatDiffTreeLines(out.DiffTree, out.MaxDiffLines, !out.PlainSpaces))
 	}
 
 	return result.String()
 }
 
-// formatDiffTreeLines applies non-breaking space conversion for Claude Code compatibility.
-func formatDiffTreeLines(diffTree string) string {
+// formatDiffTreeLines applies non-breaking space conversion for Claude Code compatibility
+// when nbsp is true. Keeps at most maxLines lines (0 = all), replacing the rest with a
+// "… (+K more)" line so a large diff can't overflow the status line area.
+func formatDiffTreeLines(diffTree string, maxLines int, nbsp bool) string {
 	var result strings.Builder
 	lines := strings.Split(diffTree, "\n")
+	if maxLines > 0 && len(lines) > maxLines {
+		more := len(lines) - maxLines
+		lines = append(lines[:maxLines], fmt.Sprintf("… (+%d more)", more))
+	}
 	for _, line := range lines {
 		// Replace spaces with non-breaking space (U+00A0)
-		line = strings.ReplaceAll(line, " ", "\u00A0")
+		if nbsp {
+			line = strings.ReplaceAll(line, " ", "\u00A0")
+		}
 		result.WriteString("\033[0m")
 		result.WriteString(line)
 		result.WriteString("\n")
@@ -1,10 +1,74 @@
 package statusline
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/config"
+	"github.com/kylesnowschwartz/claude-bumper-lanes/bumper-lanes-plugin/tools/bumper-lanes/internal/state"
+	diffvizconfig "github.com/kylesnowschwartz/diff-viz/v2/config"
+	"github.com/kylesnowschwartz/diff-viz/v2/diff"
 )
 
+// setupCommittedRepo creates a git repo in a temp dir with files (path to
+// content) committed as the initial commit, which is empty when files is.
+// It isolates the global config and changes into the repo for the rest of
+// the test. Returns the repo path.
+func setupCommittedRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	for path, content := range files {
+		os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, path)), 0755)
+		os.WriteFile(filepath.Join(tmpDir, path), []byte(content), 0644)
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test"},
+		{"add", "."},
+		{"commit", "--allow-empty", "-m", "initial"},
+	} {
+		runGit(t, tmpDir, args...)
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(tmpDir)
+	return tmpDir
+}
+
+// runGit runs git in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// renderSession saves sess and renders the status line for it from dir,
+// as model "Sonnet".
+func renderSession(t *testing.T, dir string, sess *state.SessionState) *StatusOutput {
+	t.Helper()
+	if err := sess.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	input := &StatusInput{SessionID: sess.SessionID}
+	input.Model.DisplayName = "Sonnet"
+	input.Workspace.CurrentDir = dir
+	out, err := Render(input)
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	return out
+}
+
 func TestParseInput(t *testing.T) {
 	t.Run("parses complete input", func(t *testing.T) {
 		input := `{
@@ -68,6 +132,7 @@ func TestFormatBumperStatus(t *testing.T) {
 		limit      int
 		percentage int
 		viewMode   string
+		tag        string
 		wantColor  string
 		wantBar    bool // true if expecting traffic light bar
 		wantText   string
@@ -123,6 +188,25 @@ func TestFormatBumperStatus(t *testing.T) {
 			wantBar:    false,
 			wantText:   "Paused",
 		},
+		{
+			name:       "tag shown ahead of gauge",
+			state:      "active",
+			score:      100,
+			limit:      400,
+			percentage: 25,
+			viewMode:   "tree",
+			tag:        "auth-refactor",
+			wantColor:  colorGreen,
+			wantBar:    true,
+		},
+		{
+			name:      "tag shown when paused",
+			state:     "paused",
+			viewMode:  "tree",
+			tag:       "docs",
+			wantColor: colorYellow,
+			wantText:  "Paused",
+		},
 		{
 			name:       "empty viewMode defaults to tree",
 			state:      "active",
@@ -137,7 +221,7 @@ func TestFormatBumperStatus(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := formatBumperStatus(tt.state, tt.score, tt.limit, tt.percentage, tt.viewMode)
+			got := formatBumperStatus(tt.state, tt.score, tt.limit, tt.percentage, tt.viewMode, tt.tag)
 
 			if !strings.Contains(got, tt.wantColor) {
 				t.Errorf("formatBumperStatus() missing color %q in: %s", tt.wantColor, got)
@@ -156,6 +240,9 @@ func TestFormatBumperStatus(t *testing.T) {
 			if tt.wantText != "" && !strings.Contains(got, tt.wantText) {
 				t.Errorf("formatBumperStatus() missing text %q in: %s", tt.wantText, got)
 			}
+			if tt.tag != "" && !strings.HasPrefix(got, tt.tag+" ") {
+				t.Errorf("formatBumperStatus() should start with tag %q, got: %s", tt.tag, got)
+			}
 			// Should end with [mode]
 			expectedMode := tt.viewMode
 			if expectedMode == "" {
@@ -168,6 +255,62 @@ func TestFormatBumperStatus(t *testing.T) {
 	}
 }
 
+func TestFormatRemaining(t *testing.T) {
+	tests := []struct {
+		name         string
+		score, limit int
+		want         string
+	}{
+		{"under threshold", 280, 400, "120 left"},
+		{"exactly at threshold", 400, 400, "0 left"},
+		{"over threshold", 430, 400, "OVER by 30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRemaining(tt.score, tt.limit); got != tt.want {
+				t.Errorf("formatRemaining(%d, %d) = %q, want %q", tt.score, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDiffTreeLinesTruncation(t *testing.T) {
+	tree := "├── a.go +1\n├── b.go +2\n├── c.go +3\n├── d.go +4\n└── e.go +5"
+	tests := []struct {
+		name      string
+		maxLines  int
+		wantLines int
+		wantMore  string
+	}{
+		{"unlimited", 0, 5, ""},
+		{"cap above line count", 8, 5, ""},
+		{"cap equal to line count", 5, 5, ""},
+		{"truncated", 2, 3, "…\u00A0(+3\u00A0more)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatDiffTreeLines(tree, tt.maxLines, true)
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			if len(lines) != tt.wantLines {
+				t.Fatalf("got %d lines, want %d: %q", len(lines), tt.wantLines, got)
+			}
+			last := lines[len(lines)-1]
+			if tt.wantMore == "" {
+				if strings.Contains(last, "more") {
+					t.Errorf("unexpected more-indicator: %q", last)
+				}
+				return
+			}
+			if !strings.HasSuffix(last, tt.wantMore) {
+				t.Errorf("last line = %q, want suffix %q", last, tt.wantMore)
+			}
+			if !strings.Contains(lines[1], "b.go") {
+				t.Errorf("kept lines should be the first ones, got %q", lines[:2])
+			}
+		})
+	}
+}
+
 func TestFormatOutput(t *testing.T) {
 	t.Run("widget=all formats full output", func(t *testing.T) {
 		out := &StatusOutput{
@@ -231,6 +374,19 @@ func TestFormatOutput(t *testing.T) {
 		}
 	})
 
+	t.Run("diff tree honors MaxDiffLines", func(t *testing.T) {
+		out := &StatusOutput{
+			StatusLine:   "[Sonnet] | project",
+			DiffTree:     "├── a.go +1\n├── b.go +2\n└── c.go +3",
+			MaxDiffLines: 1,
+		}
+
+		got := FormatOutput(out, WidgetAll)
+		if strings.Contains(got, "b.go") || !strings.Contains(got, "(+2\u00A0more)") {
+			t.Errorf("FormatOutput(all) not truncated to 1 line, got: %q", got)
+		}
+	})
+
 	t.Run("handles empty status line", func(t *testing.T) {
 		out := &StatusOutput{
 			StatusLine: "",
@@ -278,3 +434,680 @@ func TestFormatOutput(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatExtensionBreakdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []diff.FileStat
+		want  string
+	}{
+		{"no changes", nil, ""},
+		{"deletions only", []diff.FileStat{{Path: "a.go", Deletions: 10}}, ""},
+		{
+			"grouped and sorted by additions",
+			[]diff.FileStat{
+				{Path: "cmd/main.go", Additions: 70},
+				{Path: "config.yaml", Additions: 80},
+				{Path: "internal/x.go", Additions: 50},
+				{Path: "README.md", Additions: 20},
+			},
+			"go:120 yaml:80 md:20",
+		},
+		{
+			"extension case folded",
+			[]diff.FileStat{{Path: "a.YML", Additions: 3}, {Path: "b.yml", Additions: 2}},
+			"yml:5",
+		},
+		{
+			"capped with other bucket",
+			[]diff.FileStat{
+				{Path: "a.go", Additions: 40},
+				{Path: "b.ts", Additions: 30},
+				{Path: "c.md", Additions: 20},
+				{Path: "d.json", Additions: 5},
+				{Path: "Makefile", Additions: 3},
+			},
+			"go:40 ts:30 md:20 other:8",
+		},
+		{
+			"ties broken by name",
+			[]diff.FileStat{{Path: "b.ts", Additions: 10}, {Path: "a.go", Additions: 10}},
+			"go:10 ts:10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatExtensionBreakdown(&diff.DiffStats{Files: tt.files}, maxExtensions)
+			if got != tt.want {
+				t.Errorf("FormatExtensionBreakdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatOnelineScoreDisplay(t *testing.T) {
+	setupCommittedRepo(t, nil)
+	os.WriteFile(".bumper-lanes.json", []byte(`{"score_display": "percent"}`), 0644)
+
+	stats := &diff.DiffStats{Files: []diff.FileStat{{Path: "a.go", Additions: 512}}, TotalAdd: 512, TotalFiles: 1}
+	if got, want := FormatOneline(stats, 512, 400, false), "SCORE 128/100 (128%) TRIPPED"; !strings.HasPrefix(got, want) {
+		t.Errorf("FormatOneline() = %q, want prefix %q", got, want)
+	}
+}
+
+func TestFormatOneline(t *testing.T) {
+	stats := &diff.DiffStats{
+		Files: []diff.FileStat{
+			{Path: "src/big.go", Additions: 180, Deletions: 20},
+			{Path: "src/small.go", Additions: 20, Deletions: 100},
+			{Path: "docs/a.md", Additions: 600},
+		},
+		TotalAdd:   800,
+		TotalDel:   120,
+		TotalFiles: 9,
+	}
+
+	tests := []struct {
+		name     string
+		stats    *diff.DiffStats
+		score    int
+		limit    int
+		useColor bool
+		want     string
+	}{
+		{
+			name:  "over threshold",
+			stats: stats, score: 512, limit: 400,
+			want: "SCORE 512/400 (128%) TRIPPED | +800 -120 | 9 files | top: docs/a.md(600)",
+		},
+		{
+			name:  "under threshold",
+			stats: stats, score: 200, limit: 400,
+			want: "SCORE 200/400 (50%) OK | +800 -120 | 9 files | top: docs/a.md(600)",
+		},
+		{
+			name:  "disabled",
+			stats: stats, score: 512, limit: 0,
+			want: "SCORE 512 DISABLED | +800 -120 | 9 files | top: docs/a.md(600)",
+		},
+		{
+			name:  "colored status",
+			stats: stats, score: 512, limit: 400, useColor: true,
+			want: "SCORE 512/400 (128%) " + colorRed + "TRIPPED" + colorReset + " | +800 -120 | 9 files | top: docs/a.md(600)",
+		},
+		{
+			name:  "no files",
+			stats: &diff.DiffStats{}, score: 0, limit: 400,
+			want: "SCORE 0/400 (0%) OK | +0 -0 | 0 files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatOneline(tt.stats, tt.score, tt.limit, tt.useColor)
+			if got != tt.want {
+				t.Errorf("FormatOneline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateByTopDir(t *testing.T) {
+	stats := &diff.DiffStats{
+		Files: []diff.FileStat{
+			{Path: "src/a.go", Additions: 10, Deletions: 1, IsUntracked: true},
+			{Path: "src/pkg/b.go", Additions: 5},
+			{Path: "docs/c.md", Additions: 3, IsUntracked: true},
+			{Path: "README.md", Deletions: 2},
+		},
+		TotalAdd:   18,
+		TotalDel:   3,
+		TotalFiles: 4,
+	}
+
+	got := aggregateByTopDir(stats)
+	want := []diff.FileStat{
+		{Path: rootFilesLabel, Deletions: 2},
+		{Path: "docs", Additions: 3, IsUntracked: true},
+		{Path: "src", Additions: 15, Deletions: 1},
+	}
+	if len(got.Files) != len(want) {
+		t.Fatalf("aggregateByTopDir() files = %+v, want %+v", got.Files, want)
+	}
+	for i := range want {
+		if got.Files[i] != want[i] {
+			t.Errorf("Files[%d] = %+v, want %+v", i, got.Files[i], want[i])
+		}
+	}
+	if got.TotalAdd != 18 || got.TotalDel != 3 || got.TotalFiles != 4 {
+		t.Errorf("totals = +%d -%d %d files, want +18 -3 4 files", got.TotalAdd, got.TotalDel, got.TotalFiles)
+	}
+}
+
+func TestRenderDiffTreeLargeDiff(t *testing.T) {
+	stats := syntheticStats(maxRenderFiles + 1)
+
+	got := renderDiffTree(stats, "tree", "", 0, 400, false)
+	if !strings.HasSuffix(got, largeDiffNote(maxRenderFiles+1)) {
+		t.Errorf("expected large diff note, got tail:\n%s", got[max(0, len(got)-200):])
+	}
+	if strings.Contains(got, "file0.go") {
+		t.Error("expected per-directory totals, found individual files")
+	}
+
+	small := renderDiffTree(syntheticStats(10), "tree", "", 0, 400, false)
+	if strings.Contains(small, "showing totals by top-level directory") {
+		t.Error("small diff should render normally")
+	}
+}
+
+func TestRenderDiffTreeInvert(t *testing.T) {
+	setupCommittedRepo(t, map[string]string{"api/a.txt": "x\n", "docs/a.txt": "x\n", "lib/a.txt": "x\n"})
+
+	stats := &diff.DiffStats{
+		Files:      []diff.FileStat{{Path: "api/a.txt", Additions: 5}},
+		TotalAdd:   5,
+		TotalFiles: 1,
+	}
+
+	got := renderDiffTree(stats, "tree", "--invert", 0, 400, false)
+	want := "Untouched: 2 of 3 top-level dirs\n├── docs/\n└── lib/"
+	if got != want {
+		t.Errorf("renderDiffTree(--invert) =\n%s\nwant:\n%s", got, want)
+	}
+
+	if normal := renderDiffTree(stats, "tree", "", 0, 400, false); strings.Contains(normal, "Untouched") {
+		t.Errorf("without --invert should render the changed files, got:\n%s", normal)
+	}
+}
+
+func TestSplitRenderer(t *testing.T) {
+	stats := &diff.DiffStats{
+		Files: []diff.FileStat{
+			{Path: "src/a.go", Additions: 60, Deletions: 1},
+			{Path: "src/b.go", Additions: 40, Deletions: 1},
+			{Path: "docs/guide.md", Additions: 10, Deletions: 10},
+			{Path: "README.md", Additions: 5},
+		},
+		TotalAdd:   115,
+		TotalDel:   12,
+		TotalFiles: 4,
+	}
+
+	got := renderDiffTree(stats, "split", "", 0, 400, false)
+	want := strings.Join([]string{
+		"(root files) +█░░░░░░░░░ 5 -░░░░░░░░░░ 0",
+		"docs         +█░░░░░░░░░ 10 -██████████ 10",
+		"src          +██████████ 100 -██░░░░░░░░ 2",
+	}, "\n")
+	if got != want {
+		t.Errorf("split render =\n%s\nwant:\n%s", got, want)
+	}
+
+	tests := []struct {
+		value, max int
+		want       string
+	}{
+		{0, 100, "░░░░░░░░░░"},
+		{1, 100, "█░░░░░░░░░"}, // Non-zero always shows
+		{50, 100, "█████░░░░░"},
+		{100, 100, "██████████"},
+	}
+	for _, tt := range tests {
+		if got := splitBar(tt.value, tt.max); got != tt.want {
+			t.Errorf("splitBar(%d, %d) = %q, want %q", tt.value, tt.max, got, tt.want)
+		}
+	}
+}
+
+// syntheticStats builds n files spread across 50 top-level dirs.
+func syntheticStats(n int) *diff.DiffStats {
+	stats := &diff.DiffStats{TotalFiles: n}
+	for i := 0; i < n; i++ {
+		f := diff.FileStat{
+			Path:      fmt.Sprintf("dir%d/sub%d/file%d.go", i%50, i%7, i),
+			Additions: i%40 + 1,
+			Deletions: i % 5,
+		}
+		stats.Files = append(stats.Files, f)
+		stats.TotalAdd += f.Additions
+		stats.TotalDel += f.Deletions
+	}
+	return stats
+}
+
+// BenchmarkRenderDiffTreeLarge renders a synthetic 5000-file diff in each mode.
+// Expected result: a few milliseconds per render thanks to directory aggregation.
+func BenchmarkRenderDiffTreeLarge(b *testing.B) {
+	stats := syntheticStats(5000)
+	for _, mode := range config.Modes() {
+		b.Run(mode, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				renderDiffTree(stats, mode, "", 0, 400, false)
+			}
+		})
+	}
+}
+
+func TestRenderRemaining(t *testing.T) {
+	tmpDir := setupCommittedRepo(t, nil)
+	os.WriteFile(".bumper-lanes.json", []byte(`{"show_remaining": true, "show_diff_viz": false}`), 0644)
+
+	tests := []struct {
+		name          string
+		score         int
+		tripped       bool
+		wantRemaining int
+		wantText      string
+	}{
+		{"under threshold", 280, false, 120, "120 left"},
+		{"over threshold", 430, true, 0, "OVER by 30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, _ := state.New("test-remaining", "tree", "main", 400)
+			sess.SetScore(tt.score)
+			sess.SetStopTriggered(tt.tripped)
+			out := renderSession(t, tmpDir, sess)
+			if out.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %d, want %d", out.Remaining, tt.wantRemaining)
+			}
+			if !strings.Contains(out.BumperIndicator, tt.wantText) {
+				t.Errorf("BumperIndicator = %q, want it to contain %q", out.BumperIndicator, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestRenderBaselineAnchor(t *testing.T) {
+	tmpDir := setupCommittedRepo(t, nil)
+	os.WriteFile(".bumper-lanes.json", []byte(`{"show_baseline_anchor": true, "show_diff_viz": false}`), 0644)
+
+	tests := []struct {
+		name     string
+		reset    bool
+		wantText string
+	}{
+		{"never reset", false, "since session start"},
+		{"after reset", true, "since reset 12m ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, _ := state.New("test-anchor", "tree", "main", 400)
+			if tt.reset {
+				sess.ResetBaseline("new-tree", "")
+				sess.LastResetAt = time.Now().Add(-12 * time.Minute).UTC().Format(time.RFC3339)
+			}
+			out := renderSession(t, tmpDir, sess)
+			if !strings.HasSuffix(out.BumperIndicator, tt.wantText) {
+				t.Errorf("BumperIndicator = %q, want it to end with %q", out.BumperIndicator, tt.wantText)
+			}
+		})
+	}
+}
+
+// countRenderer is a minimal custom renderer for registry tests.
+type countRenderer struct{ w io.Writer }
+
+func (r countRenderer) Render(stats *diff.DiffStats) {
+	fmt.Fprintf(r.w, "%d files changed", stats.TotalFiles)
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	modes := config.Modes()
+	if len(modes) != len(renderers) {
+		t.Errorf("config.Modes() = %v, want one entry per registered renderer (%d)", modes, len(renderers))
+	}
+	for _, mode := range modes {
+		if _, ok := renderers[mode]; !ok {
+			t.Errorf("mode %q has no registered renderer", mode)
+		}
+	}
+
+	RegisterRenderer("test-count", func(w io.Writer, _ bool, _ diffvizconfig.ResolvedConfig) Renderer {
+		return countRenderer{w: w}
+	})
+	defer func() {
+		unregisterRenderer("test-count")
+		if slices.Contains(config.Modes(), "test-count") {
+			t.Errorf("config.Modes() = %v after unregister, want test-count gone", config.Modes())
+		}
+	}()
+
+	if !slices.Contains(config.Modes(), "test-count") {
+		t.Errorf("config.Modes() = %v, want it to include the registered mode", config.Modes())
+	}
+
+	stats := &diff.DiffStats{
+		Files:      []diff.FileStat{{Path: "a.go", Additions: 1}, {Path: "b.go", Additions: 2}},
+		TotalAdd:   3,
+		TotalFiles: 2,
+	}
+	if got := renderDiffTree(stats, "test-count", "", 0, 400, false); got != "2 files changed" {
+		t.Errorf("renderDiffTree(test-count) = %q, want %q", got, "2 files changed")
+	}
+}
+
+func TestGetAllStatsUntracked(t *testing.T) {
+	setupCommittedRepo(t, map[string]string{"tracked.txt": "x\n"})
+
+	os.WriteFile("tracked.txt", []byte("x\ny\n"), 0644)
+	os.WriteFile("scratch.txt", []byte("a\nb\nc\n"), 0644)
+
+	tests := []struct {
+		name      string
+		flags     []string
+		untracked bool
+		want      []string
+	}{
+		{"with untracked", nil, true, []string{"tracked.txt", "scratch.txt"}},
+		{"without untracked", nil, false, []string{"tracked.txt"}},
+		{"flags with untracked", []string{"-M"}, true, []string{"tracked.txt", "scratch.txt"}},
+		{"flags without untracked", []string{"-M"}, false, []string{"tracked.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, _, err := getAllStats(tt.flags, tt.untracked)
+			if err != nil {
+				t.Fatalf("getAllStats: %v", err)
+			}
+			var got []string
+			for _, f := range stats.Files {
+				got = append(got, f.Path)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatScoreBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		score    int
+		limit    int
+		useColor bool
+		want     string
+	}{
+		{"empty", 0, 400, false, "score ░░░░░░░░░░░░░░░░░░░░ 0/400 (0%)"},
+		{"small score still shows", 5, 400, false, "score █░░░░░░░░░░░░░░░░░░░ 5/400 (1%)"},
+		{"quarter", 100, 400, false, "score █████░░░░░░░░░░░░░░░ 100/400 (25%)"},
+		{"over limit is full", 600, 400, false, "score ████████████████████ 600/400 (150%)"},
+		{"disabled", 120, 0, false, "score 120 (disabled)"},
+		{"green", 100, 400, true, "score " + colorGreen + "█████░░░░░░░░░░░░░░░" + colorReset + " 100/400 (25%)"},
+		{"yellow", 300, 400, true, "score " + colorYellow + "███████████████░░░░░" + colorReset + " 300/400 (75%)"},
+		{"red", 380, 400, true, "score " + colorRed + "███████████████████░" + colorReset + " 380/400 (95%)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatScoreBar(tt.score, tt.limit, tt.useColor); got != tt.want {
+				t.Errorf("formatScoreBar(%d, %d, %v) = %q, want %q", tt.score, tt.limit, tt.useColor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatRenderScoreBar(t *testing.T) {
+	setupCommittedRepo(t, nil)
+	// The session limit passed in wins over the configured threshold
+	os.WriteFile(".bumper-lanes.json", []byte(`{"threshold": 500}`), 0644)
+
+	// The bar shows the enforced score passed in (e.g. carry-over or a
+	// session baseline), not a re-score of the 100 rendered lines
+	stats := &diff.DiffStats{
+		Files:      []diff.FileStat{{Path: "new.go", Additions: 100, IsUntracked: true}},
+		TotalAdd:   100,
+		TotalFiles: 1,
+	}
+	got := renderDiffTree(stats, "stat", "", 150, 200, false)
+	want := "score ███████████████░░░░░ 150/200 (75%)"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("stat render =\n%s\nwant it to end with %q", got, want)
+	}
+}
+
+func TestRenderGroupBy(t *testing.T) {
+	stats := &diff.DiffStats{
+		Files: []diff.FileStat{
+			{Path: "src/lib/a.go", Additions: 10},
+			{Path: "src/lib/b.go", Additions: 20},
+			{Path: "src/main.go", Additions: 5},
+			{Path: "README.md", Additions: 1},
+		},
+		TotalAdd:   36,
+		TotalFiles: 4,
+	}
+
+	t.Run("split", func(t *testing.T) {
+		tests := []struct {
+			opts string
+			want []string // row names in order
+		}{
+			{"", []string{"(root files)", "src"}},
+			{"--group-by=1", []string{"(root files)", "src"}},
+			{"--group-by 2", []string{"(root files)", "src/lib", "src/main.go"}},
+			{"--group-by=3", []string{"(root files)", "src/lib/a.go", "src/lib/b.go", "src/main.go"}},
+		}
+		for _, tt := range tests {
+			got := renderDiffTree(stats, "split", tt.opts, 0, 400, false)
+			var rows []string
+			for _, line := range strings.Split(got, "\n") {
+				rows = append(rows, strings.TrimSpace(strings.SplitN(line, " +", 2)[0]))
+			}
+			if strings.Join(rows, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("split %q rows = %v, want %v", tt.opts, rows, tt.want)
+			}
+		}
+	})
+
+	t.Run("smart", func(t *testing.T) {
+		renders := make(map[int]string)
+		for _, depth := range []int{1, 2, 3} {
+			renders[depth] = renderDiffTree(stats, "smart", fmt.Sprintf("--group-by=%d", depth), 0, 400, false)
+		}
+		if !strings.Contains(renders[1], "src(") || strings.Contains(renders[1], "src/") {
+			t.Errorf("group-by 1 should roll up to top-level dirs, got:\n%s", renders[1])
+		}
+		if !strings.Contains(renders[2], "src/lib") || strings.Contains(renders[2], "a.go") {
+			t.Errorf("group-by 2 should show src/lib without its files, got:\n%s", renders[2])
+		}
+		if !strings.Contains(renders[3], "src/lib/a.go") || !strings.Contains(renders[3], "src/lib/b.go") {
+			t.Errorf("group-by 3 should show individual files, got:\n%s", renders[3])
+		}
+		if renders[1] == renders[2] || renders[2] == renders[3] {
+			t.Error("each group-by depth should aggregate differently")
+		}
+	})
+}
+
+func TestRenderDiffTreeAnnotate(t *testing.T) {
+	tmpDir := setupCommittedRepo(t, map[string]string{"tracked.txt": "x\n", "old.txt": "a\nb\nc\nd\n"})
+	runGit(t, tmpDir, "mv", "old.txt", "moved.txt")
+
+	os.WriteFile("tracked.txt", []byte("x\ny\n"), 0644)
+	os.WriteFile("scratch.txt", []byte("a\nb\nc\n"), 0644)
+
+	stats, _, err := getAllStats(nil, true)
+	if err != nil {
+		t.Fatalf("getAllStats: %v", err)
+	}
+	got := renderDiffTree(stats, "tree", annotateOpt, 0, 400, false)
+	for _, want := range []string{"tracked.txt [mod]", "scratch.txt [new]"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderDiffTree(--annotate) missing %q:\n%s", want, got)
+		}
+	}
+	if plain := renderDiffTree(stats, "tree", "", 0, 400, false); strings.Contains(plain, "[mod]") {
+		t.Errorf("without --annotate should not tag files, got:\n%s", plain)
+	}
+
+	// Staged renames aren't in the working tree numstat; check the tag directly
+	if tag := fileTag(diff.FileStat{Path: "moved.txt"}, fileStatuses(nil)); tag != tagRenamed {
+		t.Errorf("fileTag(moved.txt) = %q, want %q", tag, tagRenamed)
+	}
+}
+
+func TestRenderDiffTreeAddsOnly(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	stats := &diff.DiffStats{
+		Files: []diff.FileStat{
+			{Path: "src/new.go", Additions: 10},
+			{Path: "src/edit.go", Additions: 3, Deletions: 2},
+			{Path: "src/gone.go", Deletions: 40},
+		},
+		TotalAdd:   13,
+		TotalDel:   42,
+		TotalFiles: 3,
+	}
+
+	got := renderDiffTree(stats, "tree", addsOnlyOpt, 0, 400, false)
+	if strings.Contains(got, "gone.go") {
+		t.Errorf("--adds-only should hide the deletion-only file:\n%s", got)
+	}
+	for _, want := range []string{"new.go", "edit.go"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("--adds-only dropped %s:\n%s", want, got)
+		}
+	}
+	if normal := renderDiffTree(stats, "tree", "", 0, 400, false); !strings.Contains(normal, "gone.go") {
+		t.Errorf("without --adds-only the deletion-only file should show:\n%s", normal)
+	}
+
+	kept := dropDeletionOnly(stats)
+	if kept.TotalFiles != 2 || kept.TotalAdd != 13 || kept.TotalDel != 2 {
+		t.Errorf("totals = %d files +%d -%d, want 2 files +13 -2", kept.TotalFiles, kept.TotalAdd, kept.TotalDel)
+	}
+
+	onlyDeletions := &diff.DiffStats{Files: []diff.FileStat{{Path: "gone.go", Deletions: 5}}, TotalDel: 5, TotalFiles: 1}
+	if got := renderDiffTree(onlyDeletions, "tree", addsOnlyOpt, 0, 400, false); got != "" {
+		t.Errorf("all files deletion-only: got %q, want empty", got)
+	}
+}
+
+func TestRenderSessionWidget(t *testing.T) {
+	tmpDir := setupCommittedRepo(t, nil)
+	os.WriteFile(".bumper-lanes.json", []byte(`{"show_diff_viz": false}`), 0644)
+
+	tests := []struct {
+		name          string
+		sessionWidget string
+		argWidget     string
+		wantFull      bool
+	}{
+		{"no preference prints everything", "", "", true},
+		{"session indicator preference", WidgetIndicator, "", false},
+		{"explicit widget overrides the preference", WidgetIndicator, WidgetAll, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, _ := state.New("test-widget", "tree", "main", 400)
+			sess.SetScore(100)
+			sess.SetWidget(tt.sessionWidget)
+			out := renderSession(t, tmpDir, sess)
+			got := FormatOutput(out, tt.argWidget)
+			if full := strings.Contains(got, "Sonnet"); full != tt.wantFull {
+				t.Errorf("FormatOutput(%q) with session widget %q = %q, want full status line %v", tt.argWidget, tt.sessionWidget, got, tt.wantFull)
+			}
+			if !strings.Contains(got, "25%") {
+				t.Errorf("FormatOutput(%q) missing the indicator: %q", tt.argWidget, got)
+			}
+		})
+	}
+}
+
+func TestRenderStatuslineNBSP(t *testing.T) {
+	tmpDir := setupCommittedRepo(t, map[string]string{"app.go": "x\n"})
+
+	os.WriteFile("app.go", []byte("x\ny\nz\n"), 0644)
+
+	tests := []struct {
+		name     string
+		config   string
+		wantNBSP bool
+	}{
+		{"default converts spaces", `{"show_diff_viz": true, "exclude": [".bumper-lanes.json"]}`, true},
+		{"statusline_nbsp false keeps spaces", `{"show_diff_viz": true, "statusline_nbsp": false, "exclude": [".bumper-lanes.json"]}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.WriteFile(".bumper-lanes.json", []byte(tt.config), 0644)
+			sess, _ := state.New("test-nbsp", "tree", "main", 400)
+			sess.SetViewMode("tree")
+			out := renderSession(t, tmpDir, sess)
+			for _, widget := range []string{WidgetDiffTree, WidgetAll} {
+				got := FormatOutput(out, widget)
+				if !strings.Contains(got, "app.go") {
+					t.Fatalf("FormatOutput(%s) missing the diff tree: %q", widget, got)
+				}
+				if hasNBSP := strings.Contains(got, "\u00A0"); hasNBSP != tt.wantNBSP {
+					t.Errorf("FormatOutput(%s) has NBSP = %v, want %v: %q", widget, hasNBSP, tt.wantNBSP, got)
+				}
+				if !tt.wantNBSP && !strings.Contains(got, " in 1 files") {
+					t.Errorf("FormatOutput(%s) should keep regular spaces: %q", widget, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderDiffTreeMaxDepth(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	stats := &diff.DiffStats{
+		Files: []diff.FileStat{
+			{Path: "README.md", Additions: 1},
+			{Path: "src/main.go", Additions: 2},
+			{Path: "src/lib/a.go", Additions: 3, Deletions: 1},
+			{Path: "src/lib/util/deep/b.go", Additions: 4},
+			{Path: "docs/guide/intro/c.md", Additions: 5, IsUntracked: true},
+		},
+		TotalAdd:   15,
+		TotalDel:   1,
+		TotalFiles: 5,
+	}
+
+	clamped := clampDepth(stats, 2)
+	want := map[string]diff.FileStat{
+		"README.md":             {Additions: 1},
+		"src/main.go":           {Additions: 2},
+		"src/lib/… (2 files)":   {Additions: 7, Deletions: 1},
+		"docs/guide/… (1 file)": {Additions: 5, IsUntracked: true},
+	}
+	if len(clamped.Files) != len(want) {
+		t.Fatalf("clamped to %d entries, want %d: %+v", len(clamped.Files), len(want), clamped.Files)
+	}
+	for _, f := range clamped.Files {
+		w, ok := want[f.Path]
+		if !ok {
+			t.Errorf("unexpected entry %q", f.Path)
+			continue
+		}
+		if f.Additions != w.Additions || f.Deletions != w.Deletions || f.IsUntracked != w.IsUntracked {
+			t.Errorf("%s = +%d -%d untracked=%v, want +%d -%d untracked=%v",
+				f.Path, f.Additions, f.Deletions, f.IsUntracked, w.Additions, w.Deletions, w.IsUntracked)
+		}
+	}
+	if clamped.TotalFiles != 5 || clamped.TotalAdd != 15 || clamped.TotalDel != 1 {
+		t.Errorf("totals = %d files +%d -%d, want unchanged 5 files +15 -1", clamped.TotalFiles, clamped.TotalAdd, clamped.TotalDel)
+	}
+
+	for _, opts := range []string{"--max-depth 2", "--max-depth=2"} {
+		got := renderDiffTree(stats, "tree", opts, 0, 400, false)
+		for _, hidden := range []string{"a.go", "deep", "util", "intro"} {
+			if strings.Contains(got, hidden) {
+				t.Errorf("%s should hide %s:\n%s", opts, hidden, got)
+			}
+		}
+		for _, shown := range []string{"main.go", "lib", "… (2 files)", "guide"} {
+			if !strings.Contains(got, shown) {
+				t.Errorf("%s missing %s:\n%s", opts, shown, got)
+			}
+		}
+	}
+	if full := renderDiffTree(stats, "tree", "", 0, 400, false); !strings.Contains(full, "b.go") {
+		t.Errorf("without --max-depth the deep file should show:\n%s", full)
+	}
+}